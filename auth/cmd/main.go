@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,21 +10,33 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/joho/godotenv"
 	"github.com/vterry/ddd-study/auth-server/internal/infra/api"
 	"github.com/vterry/ddd-study/auth-server/internal/infra/config"
 	"github.com/vterry/ddd-study/auth-server/internal/infra/db/mongodb"
+	"github.com/vterry/ddd-study/auth-server/internal/infra/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML configuration file")
+	flag.Parse()
+
+	// a local .env file only feeds the environment layer of the configuration
+	_ = godotenv.Load()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
 
 	// default context for the application
 	ctx := context.Background()
 
-	mongoUri := mongodb.MongoURIBuilder(config.Envs.MONGOAddress)
-	mongoOptions := mongodb.NewMongoDBStorage(mongoUri, config.Envs.MONGOUser, config.Envs.MONGOPass)
+	mongoUri := mongodb.MongoURIBuilder(cfg.Mongo.Address)
+	mongoOptions := mongodb.NewMongoDBStorage(mongoUri, cfg.Mongo.User, cfg.Mongo.Password)
 
 	// another context just for initial connection
 
@@ -42,7 +55,7 @@ func main() {
 	serverCtx, serverCancel := context.WithCancel(ctx)
 	defer serverCancel()
 
-	server := api.NewHttpServer(serverCtx, config.Envs.Port, database)
+	server := api.NewHttpServer(serverCtx, cfg, database, metrics.New())
 	serverErr := make(chan error, 1)
 	go func() {
 		serverErr <- server.Run()
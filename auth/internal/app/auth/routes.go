@@ -11,20 +11,22 @@ import (
 )
 
 type Handler struct {
-	service AuthService
+	service   AuthService
+	jwtSecret []byte
 }
 
-func NewHandler(service AuthService) *Handler {
+func NewHandler(service AuthService, jwtSecret []byte) *Handler {
 	return &Handler{
-		service: service,
+		service:   service,
+		jwtSecret: jwtSecret,
 	}
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /login", h.handleLogin)
 	mux.HandleFunc("POST /login/create", h.handleCreateLogin)
-	mux.Handle("POST /token/renew", middleware.Chain(http.HandlerFunc(h.handleRenew), middleware.Auhtentication()))
-	mux.Handle("POST /logout", middleware.Chain(http.HandlerFunc(h.handleRevoke), middleware.Auhtentication()))
+	mux.Handle("POST /token/renew", middleware.Chain(http.HandlerFunc(h.handleRenew), middleware.Auhtentication(h.jwtSecret)))
+	mux.Handle("POST /logout", middleware.Chain(http.HandlerFunc(h.handleRevoke), middleware.Auhtentication(h.jwtSecret)))
 }
 
 func (h *Handler) handleLogin(w http.ResponseWriter, r *http.Request) {
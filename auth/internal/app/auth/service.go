@@ -16,23 +16,22 @@ import (
 	"github.com/vterry/ddd-study/auth-server/internal/infra/config"
 )
 
-var secret = []byte(config.Envs.JWTSecret)
-
 type AuthService struct {
 	sessionService session.SessionService
 	loginRepo      login.Repository
+	tokens         config.TokenConfig
 }
 
-func NewAuthService(sessionService session.SessionService, loginRepo login.Repository) *AuthService {
+func NewAuthService(sessionService session.SessionService, loginRepo login.Repository, tokens config.TokenConfig) *AuthService {
 	return &AuthService{
 		sessionService: sessionService,
 		loginRepo:      loginRepo,
+		tokens:         tokens,
 	}
 }
 
 func (a *AuthService) LoginIn(userId string, pass string) (*types.LoginResult, error) {
-	accessDuration := time.Second * time.Duration(config.Envs.AccessDuration)
-	refreshDuration := time.Second * time.Duration(config.Envs.RefreshTokenDuration)
+	secret := []byte(a.tokens.Secret)
 
 	parsedId, err := uuid.Parse(userId)
 	if err != nil {
@@ -48,17 +47,17 @@ func (a *AuthService) LoginIn(userId string, pass string) (*types.LoginResult, e
 		return nil, fmt.Errorf("invalid userid or password")
 	}
 
-	jwtToken, accessClaim, err := token.GenerateJWTToken(secret, login.UserId().ID().String(), accessDuration)
+	jwtToken, accessClaim, err := token.GenerateJWTToken(secret, login.UserId().ID().String(), a.tokens.AccessDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JWT Token: %w", err)
 	}
 
-	refreshToken, refreshClaim, err := token.GenerateJWTToken(secret, login.UserId().ID().String(), refreshDuration)
+	refreshToken, refreshClaim, err := token.GenerateJWTToken(secret, login.UserId().ID().String(), a.tokens.RefreshDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Refresh Token: %w", err)
 	}
 
-	csrfToken, err := token.GenerateCSRFToken()
+	csrfToken, err := token.GenerateCSRFToken(a.tokens.CSRFLength)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CSRF Token: %w", err)
 	}
@@ -109,8 +108,7 @@ func (a *AuthService) CreateUserLogin(userId string, pass string) error {
 }
 
 func (a *AuthService) Renew(reqSessionId string, refreshToken string) (*types.LoginResult, error) {
-	accessDuration := time.Second * time.Duration(config.Envs.AccessDuration)
-	refreshDuration := time.Second * time.Duration(config.Envs.RefreshTokenDuration)
+	secret := []byte(a.tokens.Secret)
 
 	sessionId, err := uuid.Parse(reqSessionId)
 	if err != nil {
@@ -122,7 +120,7 @@ func (a *AuthService) Renew(reqSessionId string, refreshToken string) (*types.Lo
 		return nil, fmt.Errorf("failed to renew session: %w", err)
 	}
 
-	refreshTokenClaims, err := token.ValidateJWT(refreshToken)
+	refreshTokenClaims, err := token.ValidateJWT(secret, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("error verifying token: %w", err)
 	}
@@ -141,12 +139,12 @@ func (a *AuthService) Renew(reqSessionId string, refreshToken string) (*types.Lo
 		return nil, fmt.Errorf("invalid session/refresh token")
 	}
 
-	newjwtToken, accessClaim, err := token.GenerateJWTToken(secret, userSession.UserId().ID().String(), accessDuration)
+	newjwtToken, accessClaim, err := token.GenerateJWTToken(secret, userSession.UserId().ID().String(), a.tokens.AccessDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new JWT Token: %w", err)
 	}
 
-	newRefreshToken, refreshClaim, err := token.GenerateJWTToken(secret, userSession.UserId().ID().String(), refreshDuration)
+	newRefreshToken, refreshClaim, err := token.GenerateJWTToken(secret, userSession.UserId().ID().String(), a.tokens.RefreshDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Refresh Token: %w", err)
 	}
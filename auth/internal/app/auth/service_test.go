@@ -13,13 +13,21 @@ import (
 	"github.com/vterry/ddd-study/auth-server/internal/domain/common/valueobjects"
 	"github.com/vterry/ddd-study/auth-server/internal/domain/login"
 	"github.com/vterry/ddd-study/auth-server/internal/domain/session"
+	"github.com/vterry/ddd-study/auth-server/internal/infra/config"
 )
 
+var testTokens = config.TokenConfig{
+	Secret:          "test-secret-with-at-least-32-characters",
+	AccessDuration:  15 * time.Minute,
+	RefreshDuration: 30 * time.Minute,
+	CSRFLength:      32,
+}
+
 func TestNewAuthService(t *testing.T) {
 	sessionService := new(mockSessionService)
 	loginRepo := new(mockLoginRepository)
 
-	authService := NewAuthService(sessionService, loginRepo)
+	authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 	assert.NotNil(t, authService)
 	assert.Equal(t, sessionService, authService.sessionService)
@@ -32,7 +40,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		hashedPass, _ := password.HashePassword("password")
@@ -57,7 +65,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		result, err := authService.LoginIn("failed", "password")
 		assert.Nil(t, result)
@@ -70,7 +78,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		loginRepo.On("FindLoginByUserId", valueobjects.NewUserID(userId)).Return(nil, errors.New("user not found"))
@@ -86,7 +94,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		userLogin, err := login.CreateLogin(valueobjects.NewUserID(userId), "password")
@@ -104,7 +112,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		hashedPass, _ := password.HashePassword("password")
@@ -123,7 +131,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		loginRepo.On("FindLoginByUserId", valueobjects.NewUserID(userId)).Return(nil, errors.New("userid not found"))
@@ -137,7 +145,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		err := authService.CreateUserLogin("invalid id", "password")
 		assert.NotNil(t, err)
@@ -148,7 +156,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		loginRepo.On("FindLoginByUserId", valueobjects.NewUserID(userId)).Return(nil, nil)
@@ -162,7 +170,7 @@ func TestAuthService(t *testing.T) {
 		sessionService := new(mockSessionService)
 		loginRepo := new(mockLoginRepository)
 
-		authService := NewAuthService(sessionService, loginRepo)
+		authService := NewAuthService(sessionService, loginRepo, testTokens)
 
 		userId := uuid.New()
 		loginRepo.On("FindLoginByUserId", valueobjects.NewUserID(userId)).Return(nil, errors.New("userid not found"))
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"github.com/vterry/ddd-study/auth-server/internal/infra/config"
 )
 
 func GenerateJWTToken(secret []byte, userID string, duration time.Duration) (string, *LoginClaims, error) {
@@ -25,7 +24,7 @@ func GenerateJWTToken(secret []byte, userID string, duration time.Duration) (str
 	return tokenString, claims, nil
 }
 
-func ValidateJWT(tokenString string) (*LoginClaims, error) {
+func ValidateJWT(secret []byte, tokenString string) (*LoginClaims, error) {
 
 	token, err := jwt.ParseWithClaims(tokenString, &LoginClaims{}, func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
@@ -33,7 +32,7 @@ func ValidateJWT(tokenString string) (*LoginClaims, error) {
 			return nil, fmt.Errorf("invalid token signing method")
 		}
 
-		return []byte(config.Envs.JWTSecret), nil
+		return secret, nil
 	})
 
 	if err != nil {
@@ -48,8 +47,8 @@ func ValidateJWT(tokenString string) (*LoginClaims, error) {
 	return claims, nil
 }
 
-func GenerateCSRFToken() (string, error) {
-	bytes := make([]byte, config.Envs.CSRFTokenLength)
+func GenerateCSRFToken(length int) (string, error) {
+	bytes := make([]byte, length)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
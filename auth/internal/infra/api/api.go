@@ -8,29 +8,32 @@ import (
 
 	"github.com/vterry/ddd-study/auth-server/internal/app/auth"
 	"github.com/vterry/ddd-study/auth-server/internal/domain/session"
+	"github.com/vterry/ddd-study/auth-server/internal/infra/config"
 	"github.com/vterry/ddd-study/auth-server/internal/infra/db/mongodb"
+	"github.com/vterry/ddd-study/auth-server/internal/infra/metrics"
 	middleware "github.com/vterry/ddd-study/auth-server/internal/infra/middlware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ApiServer struct {
-	addr   string
-	db     *mongo.Database
-	server *http.Server
-	ctx    context.Context
+	config  config.Config
+	db      *mongo.Database
+	metrics *metrics.Metrics
+	server  *http.Server
+	ctx     context.Context
 }
 
-func NewHttpServer(ctx context.Context, addr string, db *mongo.Database) *ApiServer {
+func NewHttpServer(ctx context.Context, config config.Config, db *mongo.Database, metrics *metrics.Metrics) *ApiServer {
 	return &ApiServer{
-		addr: addr,
-		db:   db,
-		ctx:  ctx,
+		config:  config,
+		db:      db,
+		metrics: metrics,
+		ctx:     ctx,
 	}
 }
 
 func (a *ApiServer) Run() error {
 	v1 := http.NewServeMux()
-	v1.Handle("/authserver/v1/", middleware.Chain(http.StripPrefix("/authserver/v1", v1), middleware.Logger()))
 
 	loginRepository, err := mongodb.NewLoginRepository(a.ctx, a.db)
 	if err != nil {
@@ -44,16 +47,22 @@ func (a *ApiServer) Run() error {
 
 	sessionService := session.NewSessionService(sessionRepository, loginRepository)
 
-	authService := auth.NewAuthService(sessionService, loginRepository)
-	handler := auth.NewHandler(*authService)
+	authService := auth.NewAuthService(sessionService, loginRepository, a.config.Token)
+	handler := auth.NewHandler(*authService, []byte(a.config.Token.Secret))
 	handler.RegisterRoutes(v1)
 
+	instrumented := middleware.Metrics(a.metrics.HTTPRequests, a.metrics.HTTPDuration)(v1)
+
+	root := http.NewServeMux()
+	root.Handle("/authserver/v1/", middleware.Chain(http.StripPrefix("/authserver/v1", instrumented), middleware.Logger()))
+	root.Handle("GET /metrics", a.metrics.Handler())
+
 	a.server = &http.Server{
-		Addr:    a.addr,
-		Handler: v1,
+		Addr:    a.config.Port,
+		Handler: root,
 	}
 
-	log.Println("Listening on", a.addr)
+	log.Println("Listening on", a.config.Port)
 	return a.server.ListenAndServe()
 }
 
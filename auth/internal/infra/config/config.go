@@ -1,61 +1,244 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProfileDev  = "dev"
+	ProfileProd = "prod"
+)
+
+// Defaults that are only acceptable on a developer machine. Validate rejects
+// them outside the dev profile.
+const (
+	devJWTSecret     = "secret"
+	devMongoPassword = "password"
+)
+
+const minJWTSecretLength = 32
+
+var (
+	ErrInvalidConfig  = errors.New("invalid configuration")
+	ErrInsecureConfig = errors.New("insecure configuration")
 )
 
 type Config struct {
-	PublicHost           string
-	Port                 string
-	MONGOUser            string
-	MONGOPass            string
-	MONGODB              string
-	MONGOAddress         string
-	AccessDuration       int64
-	RefreshTokenDuration int64
-	CSRFTokenLength      int64
-	JWTSecret            string
-	UserServiceURL       string
+	Profile        string      `yaml:"profile"`
+	PublicHost     string      `yaml:"publicHost"`
+	Port           string      `yaml:"port"`
+	Mongo          MongoConfig `yaml:"mongo"`
+	Token          TokenConfig `yaml:"token"`
+	UserServiceURL string      `yaml:"userServiceUrl"`
 }
 
-var Envs = initConfig()
+type MongoConfig struct {
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	Address  string `yaml:"address"`
+}
 
-func initConfig() Config {
-	godotenv.Load()
+type TokenConfig struct {
+	Secret          string        `yaml:"secret"`
+	AccessDuration  time.Duration `yaml:"accessDuration"`
+	RefreshDuration time.Duration `yaml:"refreshDuration"`
+	CSRFLength      int           `yaml:"csrfLength"`
+}
 
+// Default returns the built-in configuration, the first layer Load starts from.
+func Default() Config {
 	return Config{
-		PublicHost:           getEnv("PUBLIC_HOST", "127.0.0.1"),
-		Port:                 getEnv("PORT", ":8080"),
-		MONGOUser:            getEnv("MONGO_USER", "admin"),
-		MONGOPass:            getEnv("MONGO_PASS", "password"),
-		MONGODB:              getEnv("MONGO_DB", "AuthServer"),
-		MONGOAddress:         fmt.Sprintf("%s:%s", getEnv("MONGO_HOST", "localhost"), getEnv("MONGO_PORT", "27017")),
-		AccessDuration:       getEnvAsInt("ACCESS_DURATION", 900),
-		RefreshTokenDuration: getEnvAsInt("REFRESH_EXPIRATION", 1800),
-		CSRFTokenLength:      getEnvAsInt("CSRF_TOKEN_LENGTH", 128),
-		JWTSecret:            getEnv("JWT_SECRET", "secret"),
-		UserServiceURL:       getEnv("USER_SERVICE_URL", "http://localhost:8080"),
+		Profile:    ProfileProd,
+		PublicHost: "127.0.0.1",
+		Port:       ":8080",
+		Mongo: MongoConfig{
+			User:     "admin",
+			Password: devMongoPassword,
+			Database: "AuthServer",
+			Address:  "localhost:27017",
+		},
+		Token: TokenConfig{
+			Secret:          devJWTSecret,
+			AccessDuration:  900 * time.Second,
+			RefreshDuration: 1800 * time.Second,
+			CSRFLength:      128,
+		},
+		UserServiceURL: "http://localhost:8080",
 	}
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// Load layers the configuration sources in increasing precedence: Default,
+// the YAML file at path (skipped when path is empty), environment variables
+// and finally the secret files named by the *_FILE variables. The result is
+// validated before it is returned.
+func Load(path string) (Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookup func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
 	}
-	return fallback
+
+	e := env{lookup: lookup}
+	e.string("APP_PROFILE", &cfg.Profile)
+	e.string("PUBLIC_HOST", &cfg.PublicHost)
+	e.string("PORT", &cfg.Port)
+	e.string("MONGO_USER", &cfg.Mongo.User)
+	e.string("MONGO_PASS", &cfg.Mongo.Password)
+	e.string("MONGO_DB", &cfg.Mongo.Database)
+	e.address("MONGO_HOST", "MONGO_PORT", &cfg.Mongo.Address)
+	e.string("JWT_SECRET", &cfg.Token.Secret)
+	e.seconds("ACCESS_DURATION", &cfg.Token.AccessDuration)
+	e.seconds("REFRESH_EXPIRATION", &cfg.Token.RefreshDuration)
+	e.int("CSRF_TOKEN_LENGTH", &cfg.Token.CSRFLength)
+	e.string("USER_SERVICE_URL", &cfg.UserServiceURL)
+
+	e.secretFile("MONGO_PASS_FILE", &cfg.Mongo.Password)
+	e.secretFile("JWT_SECRET_FILE", &cfg.Token.Secret)
+
+	if err := errors.Join(e.errs...); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read config file: %w", ErrInvalidConfig, err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: cannot parse config file %s: %w", ErrInvalidConfig, path, err)
+	}
+	return nil
 }
 
-func getEnvAsInt(key string, fallback int64) int64 {
-	if value, ok := os.LookupEnv(key); ok {
-		i, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			return fallback
+// Validate reports every problem found in the configuration at once.
+func (c Config) Validate() error {
+	var problems []error
+	invalid := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+	insecure := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]any{ErrInsecureConfig}, args...)...))
+	}
+
+	if c.Profile != ProfileDev && c.Profile != ProfileProd {
+		invalid("APP_PROFILE must be %q or %q, got %q", ProfileDev, ProfileProd, c.Profile)
+	}
+	if c.Port == "" {
+		invalid("PORT is required")
+	}
+	if c.Mongo.Address == "" {
+		invalid("MONGO_HOST and MONGO_PORT are required")
+	}
+	if c.Mongo.Database == "" {
+		invalid("MONGO_DB is required")
+	}
+	if c.Token.Secret == "" {
+		invalid("JWT_SECRET is required")
+	}
+	if c.Token.AccessDuration <= 0 {
+		invalid("ACCESS_DURATION must be positive")
+	}
+	if c.Token.RefreshDuration <= c.Token.AccessDuration {
+		invalid("REFRESH_EXPIRATION must be longer than ACCESS_DURATION")
+	}
+	if c.Token.CSRFLength < 32 {
+		invalid("CSRF_TOKEN_LENGTH must be at least 32 bytes")
+	}
+
+	if c.Profile != ProfileDev {
+		if c.Token.Secret == devJWTSecret || len(c.Token.Secret) < minJWTSecretLength {
+			insecure("JWT_SECRET must be set to a random value of at least %d characters outside the %s profile", minJWTSecretLength, ProfileDev)
 		}
-		return i
+		if c.Mongo.Password == devMongoPassword {
+			insecure("MONGO_PASS must not use the development default outside the %s profile", ProfileDev)
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// env applies environment variables over the configuration, collecting the
+// malformed ones instead of silently falling back to the previous layer.
+type env struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+func (e *env) string(key string, dst *string) {
+	if value, ok := e.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (e *env) int(key string, dst *int) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be an integer, got %q", key, value))
+		return
+	}
+	*dst = i
+}
+
+func (e *env) seconds(key string, dst *time.Duration) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be a number of seconds, got %q", key, value))
+		return
+	}
+	*dst = time.Duration(i) * time.Second
+}
+
+// address overrides only the half of the host:port pair that is set.
+func (e *env) address(hostKey, portKey string, dst *string) {
+	host, port, _ := strings.Cut(*dst, ":")
+	e.string(hostKey, &host)
+	e.string(portKey, &port)
+	*dst = fmt.Sprintf("%s:%s", host, port)
+}
+
+func (e *env) secretFile(key string, dst *string) {
+	path, ok := e.lookup(key)
+	if !ok || path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: cannot read secret file: %w", key, err))
+		return
 	}
-	return fallback
+	*dst = strings.TrimSpace(string(data))
 }
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const strongSecret = "0123456789abcdef0123456789abcdef"
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	file := writeFile(t, "auth.yaml", `
+profile: prod
+port: ":9000"
+mongo:
+  user: file-user
+  password: file-password
+token:
+  secret: file-secret-that-is-long-enough-to-pass
+  accessDuration: 10m
+`)
+	secret := writeFile(t, "jwt", strongSecret+"\n")
+
+	cfg, err := load(file, lookupFrom(map[string]string{
+		"PORT":            ":9100",
+		"MONGO_HOST":      "mongo",
+		"JWT_SECRET":      "env-secret-that-is-also-long-enough",
+		"JWT_SECRET_FILE": secret,
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9100", cfg.Port, "environment overrides the file")
+	assert.Equal(t, "file-user", cfg.Mongo.User, "file overrides the defaults")
+	assert.Equal(t, "mongo:27017", cfg.Mongo.Address, "only the host is overridden")
+	assert.Equal(t, 10*time.Minute, cfg.Token.AccessDuration)
+	assert.Equal(t, strongSecret, cfg.Token.Secret, "secret files override the environment")
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		env         map[string]string
+		expectedErr error
+		contains    string
+	}{
+		{
+			name:        "insecure defaults outside the dev profile",
+			env:         map[string]string{},
+			expectedErr: ErrInsecureConfig,
+			contains:    "JWT_SECRET",
+		},
+		{
+			name:        "weak secret outside the dev profile",
+			env:         map[string]string{"JWT_SECRET": "short", "MONGO_PASS": "strong-password"},
+			expectedErr: ErrInsecureConfig,
+			contains:    "JWT_SECRET",
+		},
+		{
+			name:        "malformed number",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "ACCESS_DURATION": "15m"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "ACCESS_DURATION must be a number of seconds",
+		},
+		{
+			name:        "unknown profile",
+			env:         map[string]string{"APP_PROFILE": "staging"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "APP_PROFILE",
+		},
+		{
+			name:        "refresh shorter than access",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "ACCESS_DURATION": "600", "REFRESH_EXPIRATION": "300"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "REFRESH_EXPIRATION",
+		},
+		{
+			name:        "missing secret file",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "JWT_SECRET_FILE": "/does/not/exist"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "JWT_SECRET_FILE",
+		},
+		{
+			name:        "unknown field in file",
+			file:        "tokn:\n  secret: typo\n",
+			env:         map[string]string{"APP_PROFILE": ProfileDev},
+			expectedErr: ErrInvalidConfig,
+			contains:    "tokn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.file != "" {
+				path = writeFile(t, "auth.yaml", tt.file)
+			}
+
+			_, err := load(path, lookupFrom(tt.env))
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
+
+func TestLoadDevProfileAcceptsDefaults(t *testing.T) {
+	cfg, err := load("", lookupFrom(map[string]string{"APP_PROFILE": ProfileDev}))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default().Token.Secret, cfg.Token.Secret)
+}
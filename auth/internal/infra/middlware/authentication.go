@@ -10,7 +10,7 @@ import (
 	"github.com/vterry/ddd-study/auth-server/internal/app/utils"
 )
 
-func Auhtentication() Middleware {
+func Auhtentication(secret []byte) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -28,7 +28,7 @@ func Auhtentication() Middleware {
 
 			jwtToken := fields[1]
 
-			claims, err := token.ValidateJWT(jwtToken)
+			claims, err := token.ValidateJWT(secret, jwtToken)
 			if err != nil {
 				denied(w)
 				return
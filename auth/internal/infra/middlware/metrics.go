@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of the requests served by mux. It
+// must wrap the mux itself: the route label is the pattern the mux matched.
+func Metrics(requests *prometheus.CounterVec, duration *prometheus.HistogramVec) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			lw := &responseLogger{w: w, code: http.StatusOK}
+
+			next.ServeHTTP(lw, r)
+
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			status := strconv.Itoa(lw.code)
+
+			requests.WithLabelValues(route, r.Method, status).Inc()
+			duration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
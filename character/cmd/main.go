@@ -2,61 +2,228 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/token"
+	memorybroker "github.com/vterry/ddd-study/character/internal/adapters/output/broker"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway"
+	tokenport "github.com/vterry/ddd-study/character/internal/core/ports/input/token"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+	gatewayport "github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/infra/app"
 	"github.com/vterry/ddd-study/character/internal/infra/config"
-	"github.com/vterry/ddd-study/character/internal/infra/db"
+	grpcserver "github.com/vterry/ddd-study/character/internal/infra/grpc"
+	"github.com/vterry/ddd-study/character/internal/infra/health"
 	server "github.com/vterry/ddd-study/character/internal/infra/http"
+	"github.com/vterry/ddd-study/character/internal/infra/keycloak"
 	"github.com/vterry/ddd-study/character/internal/infra/logger"
+	"github.com/vterry/ddd-study/character/internal/infra/metrics"
+	"github.com/vterry/ddd-study/character/internal/infra/storage"
+	"github.com/vterry/ddd-study/character/internal/infra/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML configuration file")
+	flag.Parse()
+
 	zapLogger := logger.NewZapLogger()
-	zapLogger.Info("Starting Character Service", "addr", config.Envs.Addr)
+
+	// a local .env file only feeds the environment layer of the configuration
+	if err := godotenv.Load(); err != nil {
+		zapLogger.Debug(".env file not loaded", "error", err)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		zapLogger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	switch command := flag.Arg(0); command {
+	case "export", "import":
+		err := runTransfer(cfg, zapLogger, command, flag.Args()[1:])
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, transferUsage)
+			os.Exit(2)
+		}
+		if err != nil {
+			zapLogger.Error("Character "+command+" failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	case "":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", command, transferUsage)
+		os.Exit(2)
+	}
+
+	if err := run(cfg, zapLogger); err != nil {
+		zapLogger.Error("Character Service stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg config.Config, zapLogger *logger.ZapLogger) error {
+	zapLogger.Info("Starting Character Service", "addr", cfg.Addr)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	mysqlCfg := mysql.Config{
-		User:                 config.Envs.Db.User,
-		Passwd:               config.Envs.Db.Password,
-		Addr:                 config.Envs.Db.Address,
-		DBName:               config.Envs.Db.Name,
-		Net:                  "tcp",
-		AllowNativePasswords: true,
-		ParseTime:            true,
+	store, err := storage.Open(cfg, clock.System{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			zapLogger.Error("failed to close storage", "error", err)
+		} else {
+			zapLogger.Info("Storage closed gracefully")
+		}
+	}()
+	zapLogger.Info("Storage opened", "storage", store.System)
+
+	// Refuse to start while a hard dependency is down
+	startup := health.NewChecker(cfg.HealthCheckTimeout)
+	readiness := health.NewChecker(cfg.HealthCheckTimeout)
+	store.RegisterChecks(startup, readiness)
+	if cfg.AuthProvider == config.AuthKeycloak {
+		oidcCheck := health.OIDC(&http.Client{}, keycloak.IssuerURL(&cfg.Auth))
+		startup.Register("oidc", oidcCheck)
+		readiness.Register("oidc", oidcCheck)
+	}
+	if report := startup.Run(ctx); report.Status != health.StatusUp {
+		return fmt.Errorf("dependencies unavailable: %+v", report.Checks)
 	}
 
-	dbConn, err := db.NewMySQLStorage(mysqlCfg)
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.Tracing)
 	if err != nil {
-		zapLogger.Error("failed to connect to MySQL", "error", err)
-		os.Exit(1)
+		return err
 	}
 	defer func() {
-		if err := dbConn.Close(); err != nil {
-			zapLogger.Error("failed to close DB connection", "error", err)
-		} else {
-			zapLogger.Info("DB connection closed gracefully")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			zapLogger.Error("failed to flush traces", "error", err)
+		}
+	}()
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	serviceMetrics := metrics.New()
+
+	var (
+		loginGateway gatewayport.Login
+		tokens       tokenport.AuthService
+	)
+	switch cfg.AuthProvider {
+	case config.AuthFake:
+		zapLogger.Info("Identity provider faked: bearer tokens name the subject and every login is valid")
+		loginGateway = gateway.NewFakeLoginGateway(zapLogger)
+		tokens = token.NewFakeTokenValidator()
+	default:
+		keycloakClient, err := keycloak.NewKeycloakClient(ctx, &cfg.Auth)
+		if err != nil {
+			return err
+		}
+		keycloakLogin := gateway.NewLoginGateway(keycloakClient, zapLogger)
+		keycloakLogin.Client = &http.Client{
+			Transport: tracing.TraceTransport(
+				metrics.InstrumentTransport(nil, metrics.TargetKeycloak, metrics.KeycloakToken, serviceMetrics),
+				tracerProvider, otel.GetTextMapPropagator(),
+			),
+		}
+		loginGateway = keycloakLogin
+		tokens = token.NewTokenValidator(keycloakClient)
+	}
+
+	var messages broker.Broker
+	if cfg.Consumer.Enabled {
+		// config only accepts the in-memory broker, and only in the dev profile
+		messages = memorybroker.NewInMemoryBroker()
+	}
+
+	application, err := app.New(cfg,
+		app.WithCharacterRepository(store.Characters, store.System),
+		app.WithEventLog(store.Events),
+		app.WithCharacterViewRepository(store.Views),
+		app.WithInventoryHistory(store.History),
+		app.WithCharacterSuspensions(store.Suspensions),
+		app.WithCharacterGold(store.Gold),
+		app.WithClassRepository(store.Classes),
+		app.WithLedgerRepository(store.Ledger),
+		app.WithWalletRepository(store.Wallets),
+		app.WithIdempotencyRepository(store.Idempotency),
+		app.WithRateLimitRepository(store.RateLimits),
+		app.WithProcessedMessages(store.Processed),
+		app.WithBroker(messages),
+		app.WithVaultGateway(gateway.NewMockVaultGateway(zapLogger)),
+		app.WithLoginGateway(loginGateway),
+		app.WithTokenValidator(tokens),
+		app.WithLogger(zapLogger),
+		app.WithMetrics(serviceMetrics),
+		app.WithReadiness(readiness),
+	)
+	if err != nil {
+		return err
+	}
+
+	projector := application.Projector()
+	if cfg.Projection.RebuildOnStartup {
+		if err := projector.Rebuild(ctx); err != nil {
+			return err
 		}
+	}
+	go func() {
+		_ = projector.Run(ctx, cfg.Projection.PollInterval)
+	}()
+	go func() {
+		_ = application.LedgerPoster().Run(ctx, cfg.Ledger.PollInterval)
+	}()
+	go func() {
+		_ = application.LedgerReconciler().Run(ctx, cfg.Ledger.ReconcileInterval)
+	}()
+	go func() {
+		_ = application.SuspensionSweeper().Run(ctx, cfg.Moderation.SweepInterval)
+	}()
+	go func() {
+		_ = application.ClassCatalog().Run(ctx, cfg.Classes.RefreshInterval)
 	}()
+	if consumer := application.InventoryConsumer(); consumer != nil {
+		go func() {
+			_ = consumer.Run(ctx)
+		}()
+	}
 
-	httpServer := server.NewHttpServer(ctx, config.Envs.Addr, dbConn)
+	httpServer := server.NewHttpServer(cfg.Addr, application.Handler())
+	grpcServer := grpcserver.NewGrpcServer(cfg.GrpcAddr, application.GRPCServer())
 
-	serverErr := make(chan error, 1)
+	serverErr := make(chan error, 2)
 	go func() {
 		serverErr <- httpServer.Run()
 	}()
+	go func() {
+		zapLogger.Info("Starting gRPC server", "addr", cfg.GrpcAddr)
+		serverErr <- grpcServer.Run()
+	}()
 
 	shutdownChan := make(chan os.Signal, 1)
 	signal.Notify(shutdownChan, os.Interrupt, syscall.SIGTERM)
 
+	var runErr error
 	select {
-	case err := <-serverErr:
-		zapLogger.Error("HTTP server error", "error", err)
+	case runErr = <-serverErr:
+		zapLogger.Error("Server error", "error", runErr)
 	case sig := <-shutdownChan:
 		zapLogger.Info("Received shutdown signal", "signal", sig)
 	}
@@ -69,4 +236,12 @@ func main() {
 	} else {
 		zapLogger.Info("HTTP server shutdown gracefully")
 	}
+
+	if err := grpcServer.Stop(shutdownCtx); err != nil {
+		zapLogger.Error("gRPC server shutdown error", "error", err)
+	} else {
+		zapLogger.Info("gRPC server shutdown gracefully")
+	}
+
+	return runErr
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/transfer"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	gatewayport "github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	coreservice "github.com/vterry/ddd-study/character/internal/core/service"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+	"github.com/vterry/ddd-study/character/internal/infra/keycloak"
+	"github.com/vterry/ddd-study/character/internal/infra/logger"
+	"github.com/vterry/ddd-study/character/internal/infra/storage"
+)
+
+const transferUsage = `Usage:
+  character export [-o file] <characterId>
+      writes the character as a JSON document to stdout or to file
+  character import [-remap old=new]... <file>
+      imports a document read from file, or from stdin when file is -,
+      replacing each old login, guild, vault or item id with the new one
+
+The import checks the login id against the login service, like the REST
+endpoint does.`
+
+var errUsage = errors.New("invalid arguments")
+
+// runTransfer serves the export and import subcommands. They work on the
+// configured storage directly and do not start the servers.
+func runTransfer(cfg config.Config, zapLogger *logger.ZapLogger, command string, args []string) error {
+	store, err := storage.Open(cfg, clock.System{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			zapLogger.Error("failed to close storage", "error", err)
+		}
+	}()
+
+	ctx := context.Background()
+	classes := coreservice.NewClassCatalog(store.Classes, zapLogger)
+	if err := classes.Refresh(ctx); err != nil {
+		return err
+	}
+	nicknames, err := character.NewNicknameRules(cfg.Nickname.Scripts...)
+	if err != nil {
+		return err
+	}
+	loginGateway, err := newLoginGateway(ctx, cfg, zapLogger)
+	if err != nil {
+		return err
+	}
+	transfers := coreservice.NewCharacterTransferService(store.Characters, loginGateway, classes, nicknames, clock.System{}, zapLogger)
+
+	if command == "export" {
+		return exportCharacter(ctx, transfers, args, os.Stdout)
+	}
+	return importCharacter(ctx, transfers, args, os.Stdin, os.Stdout)
+}
+
+// newLoginGateway checks logins against the configured identity provider.
+func newLoginGateway(ctx context.Context, cfg config.Config, zapLogger *logger.ZapLogger) (gatewayport.Login, error) {
+	if cfg.AuthProvider == config.AuthFake {
+		return gateway.NewFakeLoginGateway(zapLogger), nil
+	}
+	keycloakClient, err := keycloak.NewKeycloakClient(ctx, &cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	loginGateway := gateway.NewLoginGateway(keycloakClient, zapLogger)
+	loginGateway.Client = &http.Client{}
+	return loginGateway, nil
+}
+
+func exportCharacter(ctx context.Context, transfers service.CharacterTransfer, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	output := flags.String("o", "", "file the document is written to")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: export takes one character id", errUsage)
+	}
+
+	characterId, err := uuid.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%w: character id: %v", errUsage, err)
+	}
+
+	exported, err := transfers.Export(ctx, character.NewCharacterID(characterId))
+	if err != nil {
+		return err
+	}
+
+	document := transfer.Export(exported, clock.System{}.Now())
+	if *output == "" {
+		return writeDocument(stdout, document)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeDocument(file, document); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func importCharacter(ctx context.Context, transfers service.CharacterTransfer, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	remap := remapFlag{}
+	flags.Var(remap, "remap", "old=new id to replace, may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: import takes one file", errUsage)
+	}
+
+	input := stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	document, err := transfer.Decode(input)
+	if err != nil {
+		return err
+	}
+	ids, err := transfer.ParseRemap(remap)
+	if err != nil {
+		return err
+	}
+	imported, err := document.Import(ids)
+	if err != nil {
+		return err
+	}
+
+	created, err := transfers.Import(ctx, imported)
+	if err != nil {
+		return err
+	}
+	return writeDocument(stdout, transfer.NewResult(document, created))
+}
+
+func writeDocument(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// remapFlag collects the repeated -remap old=new flags.
+type remapFlag map[string]string
+
+func (f remapFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for from, to := range f {
+		pairs = append(pairs, from+"="+to)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f remapFlag) Set(value string) error {
+	from, to, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected old=new, got %q", value)
+	}
+	f[from] = to
+	return nil
+}
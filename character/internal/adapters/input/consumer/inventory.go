@@ -0,0 +1,225 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+const (
+	CommandPickItem = "PickItem"
+	CommandDropItem = "DropItem"
+	CommandPickGold = "PickGold"
+)
+
+const (
+	HeaderOriginalTopic = "x-original-topic"
+	HeaderFailureReason = "x-failure-reason"
+	HeaderAttempts      = "x-attempts"
+)
+
+var (
+	ErrMalformedCommand = errors.New("malformed inventory command")
+	ErrUnknownCommand   = errors.New("unknown inventory command")
+)
+
+// permanentErrors are failures a retry cannot fix, so the message goes
+// straight to the dead-letter topic.
+var permanentErrors = []error{
+	ErrMalformedCommand,
+	ErrUnknownCommand,
+	character.ErrCharacterNotActive,
+	inventory.ErrInventoryIsFull,
+	inventory.ErrPlayerItemNotFound,
+	inventory.ErrInvalidGoldAmount,
+	inventory.ErrNotEnoughGold,
+	playeritem.ErrNilItemId,
+	playeritem.ErrNilDescription,
+	playeritem.ErrNilQuantity,
+	repository.ErrCharacterNotFound,
+}
+
+// Command is the JSON payload game servers publish to the inventory topic.
+type Command struct {
+	Type         string `json:"type"`
+	CharacterID  string `json:"characterId"`
+	ItemID       string `json:"itemId,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Quantity     int    `json:"quantity,omitempty"`
+	PlayerItemID string `json:"playerItemId,omitempty"`
+	Amount       int    `json:"amount,omitempty"`
+}
+
+type Config struct {
+	Topic           string
+	DeadLetterTopic string
+	MaxAttempts     int
+	Backoff         time.Duration
+}
+
+// InventoryConsumer applies inventory commands read from a broker topic.
+// Messages are deduplicated by id, retried on transient failures and moved
+// to the dead-letter topic once they cannot be applied. Handle returns an
+// error only when the message should be delivered again.
+type InventoryConsumer struct {
+	broker           broker.Broker
+	processed        repository.ProcessedMessageRepository
+	characterService service.CharacterService
+	logger           logger.Logger
+	config           Config
+}
+
+func NewInventoryConsumer(broker broker.Broker, processed repository.ProcessedMessageRepository, characterService service.CharacterService, logger logger.Logger, config Config) *InventoryConsumer {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	return &InventoryConsumer{
+		broker:           broker,
+		processed:        processed,
+		characterService: characterService,
+		logger:           logger,
+		config:           config,
+	}
+}
+
+func (c *InventoryConsumer) Run(ctx context.Context) error {
+	return c.broker.Subscribe(ctx, c.config.Topic, c.Handle)
+}
+
+func (c *InventoryConsumer) Handle(ctx context.Context, msg broker.Message) error {
+	done, err := c.processed.IsProcessed(ctx, msg.ID)
+	if err != nil {
+		// the message itself is fine: leave it to the broker to redeliver
+		c.logger.Error("Failed to check processed message", "messageId", msg.ID, "error", err)
+		return err
+	}
+
+	if done {
+		c.logger.Debug("Skipping duplicated message", "messageId", msg.ID)
+		return nil
+	}
+
+	cmd, err := decodeCommand(msg)
+	if err != nil {
+		return c.deadLetter(ctx, msg, err, 0)
+	}
+
+	// the character repository records the message id in the transaction
+	// applying the command, so a redelivery racing this one cannot apply it
+	// twice
+	ctx = repository.WithMessageID(ctx, msg.ID)
+
+	attempt := 0
+	for {
+		attempt++
+		err = c.apply(ctx, cmd)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, repository.ErrMessageAlreadyProcessed) {
+			c.logger.Debug("Skipping duplicated message", "messageId", msg.ID)
+			return nil
+		}
+
+		c.logger.Warn("Failed to apply inventory command", "messageId", msg.ID, "type", cmd.Type, "attempt", attempt, "error", err)
+		if isPermanent(err) || attempt >= c.config.MaxAttempts {
+			return c.deadLetter(ctx, msg, err, attempt)
+		}
+
+		select {
+		case <-time.After(c.config.Backoff * time.Duration(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *InventoryConsumer) apply(ctx context.Context, cmd Command) error {
+	characterID, err := parseUUID(cmd.CharacterID)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Type {
+	case CommandPickItem:
+		itemID, err := parseUUID(cmd.ItemID)
+		if err != nil {
+			return err
+		}
+		return c.characterService.PickItem(ctx, character.NewCharacterID(characterID), item.NewItemID(itemID), cmd.Description, cmd.Quantity)
+	case CommandDropItem:
+		playerItemID, err := parseUUID(cmd.PlayerItemID)
+		if err != nil {
+			return err
+		}
+		return c.characterService.DropItem(ctx, character.NewCharacterID(characterID), playeritem.NewPlayerItemID(playerItemID))
+	case CommandPickGold:
+		return c.characterService.PickGold(ctx, character.NewCharacterID(characterID), cmd.Amount)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, cmd.Type)
+	}
+}
+
+func (c *InventoryConsumer) deadLetter(ctx context.Context, msg broker.Message, reason error, attempts int) error {
+	headers := make(map[string]string, len(msg.Headers)+3)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[HeaderOriginalTopic] = c.config.Topic
+	headers[HeaderFailureReason] = reason.Error()
+	headers[HeaderAttempts] = strconv.Itoa(attempts)
+
+	deadLetter := broker.Message{
+		ID:      msg.ID,
+		Key:     msg.Key,
+		Payload: msg.Payload,
+		Headers: headers,
+	}
+
+	if err := c.broker.Publish(ctx, c.config.DeadLetterTopic, deadLetter); err != nil {
+		c.logger.Error("Failed to publish dead letter", "messageId", msg.ID, "error", err)
+		return err
+	}
+
+	c.logger.Warn("Message moved to dead-letter topic", "messageId", msg.ID, "reason", reason)
+	return nil
+}
+
+func decodeCommand(msg broker.Message) (Command, error) {
+	var cmd Command
+	if err := json.Unmarshal(msg.Payload, &cmd); err != nil {
+		return Command{}, fmt.Errorf("%w: %v", ErrMalformedCommand, err)
+	}
+	return cmd, nil
+}
+
+func parseUUID(value string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %v", ErrMalformedCommand, err)
+	}
+	return parsed, nil
+}
+
+func isPermanent(err error) bool {
+	for _, permanent := range permanentErrors {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
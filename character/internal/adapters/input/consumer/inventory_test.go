@@ -0,0 +1,258 @@
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	memorybroker "github.com/vterry/ddd-study/character/internal/adapters/output/broker"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	coreservice "github.com/vterry/ddd-study/character/internal/core/service"
+)
+
+const (
+	testTopic      = "inventory.commands"
+	testDeadLetter = "inventory.commands.dlq"
+)
+
+// flakyCharacters fails the first updates, as a briefly unreachable
+// database would
+type flakyCharacters struct {
+	repository.CharacterRepository
+	failures int
+}
+
+func (f *flakyCharacters) Update(ctx context.Context, c character.Character) error {
+	if f.failures > 0 {
+		f.failures--
+		return assert.AnError
+	}
+	return f.CharacterRepository.Update(ctx, c)
+}
+
+// flakyProcessed fails the first checks, and then answers as processed
+// would, or always answers not processed when processed is nil
+type flakyProcessed struct {
+	processed repository.ProcessedMessageRepository
+	failures  int
+}
+
+func (f *flakyProcessed) IsProcessed(ctx context.Context, messageID string) (bool, error) {
+	if f.failures > 0 {
+		f.failures--
+		return false, assert.AnError
+	}
+	if f.processed == nil {
+		return false, nil
+	}
+	return f.processed.IsProcessed(ctx, messageID)
+}
+
+func newCharacters() *memory.CharacterRepository {
+	return memory.NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+}
+
+func newTestConsumer(characters repository.CharacterRepository, processed repository.ProcessedMessageRepository) (*InventoryConsumer, *memorybroker.InMemoryBroker) {
+	b := memorybroker.NewInMemoryBroker()
+	characterService := coreservice.NewCharacterService(characters, gateway.NewMockVaultGateway(logger.Nop{}), nil, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+	c := NewInventoryConsumer(b, processed, characterService, logger.Nop{}, Config{
+		Topic:           testTopic,
+		DeadLetterTopic: testDeadLetter,
+		MaxAttempts:     3,
+	})
+	return c, b
+}
+
+func saveCharacter(t *testing.T, characters repository.CharacterRepository) character.CharacterID {
+	t.Helper()
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Arthas", login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	require.NoError(t, characters.Save(context.Background(), *c))
+	return c.CharacterID
+}
+
+func gold(t *testing.T, characters repository.CharacterRepository, id character.CharacterID) int {
+	t.Helper()
+	c, err := characters.FindCharacterById(context.Background(), id)
+	require.NoError(t, err)
+	inventory := c.Inventory()
+	return inventory.GetCurrentGold()
+}
+
+func pickGoldMessage(id string, characterID character.CharacterID, amount int) broker.Message {
+	return broker.Message{
+		ID:      id,
+		Payload: []byte(`{"type":"PickGold","characterId":"` + characterID.ID().String() + `","amount":` + strconv.Itoa(amount) + `}`),
+	}
+}
+
+// receive reads one message from topic, or returns false once wait is over
+func receive(b *memorybroker.InMemoryBroker, topic string, wait time.Duration) (broker.Message, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+
+	received := make(chan broker.Message, 1)
+	go func() {
+		_ = b.Subscribe(ctx, topic, func(ctx context.Context, msg broker.Message) error {
+			received <- msg
+			cancel()
+			return nil
+		})
+	}()
+
+	select {
+	case msg := <-received:
+		return msg, true
+	case <-ctx.Done():
+		select {
+		case msg := <-received:
+			return msg, true
+		default:
+			return broker.Message{}, false
+		}
+	}
+}
+
+func deadLetter(t *testing.T, b *memorybroker.InMemoryBroker) broker.Message {
+	t.Helper()
+	msg, ok := receive(b, testDeadLetter, time.Second)
+	require.True(t, ok, "no message was dead-lettered")
+	return msg
+}
+
+func assertNothingDeadLettered(t *testing.T, b *memorybroker.InMemoryBroker) {
+	t.Helper()
+	_, ok := receive(b, testDeadLetter, 50*time.Millisecond)
+	assert.False(t, ok, "a message was dead-lettered")
+}
+
+func TestConsumerAppliesCommandsOnce(t *testing.T) {
+	t.Run("redelivered after being applied", func(t *testing.T) {
+		characters := newCharacters()
+		id := saveCharacter(t, characters)
+		c, b := newTestConsumer(characters, characters)
+
+		msg := pickGoldMessage("msg-1", id, 50)
+		require.NoError(t, c.Handle(context.Background(), msg))
+		require.NoError(t, c.Handle(context.Background(), msg))
+
+		assert.Equal(t, 50, gold(t, characters, id))
+		assertNothingDeadLettered(t, b)
+	})
+
+	t.Run("redelivered while being applied", func(t *testing.T) {
+		// both deliveries pass the check before either applies the command
+		characters := newCharacters()
+		id := saveCharacter(t, characters)
+		c, b := newTestConsumer(characters, &flakyProcessed{})
+
+		msg := pickGoldMessage("msg-1", id, 50)
+		require.NoError(t, c.Handle(context.Background(), msg))
+		require.NoError(t, c.Handle(context.Background(), msg))
+
+		assert.Equal(t, 50, gold(t, characters, id))
+		assertNothingDeadLettered(t, b)
+	})
+}
+
+func TestConsumerRetriesTransientFailures(t *testing.T) {
+	characters := newCharacters()
+	id := saveCharacter(t, characters)
+	c, _ := newTestConsumer(&flakyCharacters{CharacterRepository: characters, failures: 1}, characters)
+
+	require.NoError(t, c.Handle(context.Background(), pickGoldMessage("msg-1", id, 50)))
+
+	assert.Equal(t, 50, gold(t, characters, id))
+}
+
+func TestConsumerLeavesMessageToBrokerWhenCheckFails(t *testing.T) {
+	characters := newCharacters()
+	id := saveCharacter(t, characters)
+	c, b := newTestConsumer(characters, &flakyProcessed{processed: characters, failures: 1})
+
+	err := c.Handle(context.Background(), pickGoldMessage("msg-1", id, 50))
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, gold(t, characters, id))
+	assertNothingDeadLettered(t, b)
+}
+
+func TestConsumerDeadLettersFailedMessages(t *testing.T) {
+	t.Run("malformed payload", func(t *testing.T) {
+		characters := newCharacters()
+		c, b := newTestConsumer(characters, characters)
+
+		require.NoError(t, c.Handle(context.Background(), broker.Message{ID: "msg-1", Payload: []byte(`not json`)}))
+
+		dead := deadLetter(t, b)
+		assert.Equal(t, "msg-1", dead.ID)
+		assert.Equal(t, testTopic, dead.Headers[HeaderOriginalTopic])
+	})
+
+	t.Run("domain rule violation is not retried", func(t *testing.T) {
+		characters := newCharacters()
+		id := saveCharacter(t, characters)
+		c, b := newTestConsumer(characters, characters)
+
+		require.NoError(t, c.Handle(context.Background(), pickGoldMessage("msg-1", id, -50)))
+
+		dead := deadLetter(t, b)
+		assert.Equal(t, "1", dead.Headers[HeaderAttempts])
+	})
+
+	t.Run("banned character is not retried", func(t *testing.T) {
+		characters := newCharacters()
+		id := saveCharacter(t, characters)
+		banned, err := characters.FindCharacterById(context.Background(), id)
+		require.NoError(t, err)
+		require.NoError(t, banned.Ban("botting"))
+		require.NoError(t, characters.Update(context.Background(), *banned))
+		c, b := newTestConsumer(characters, characters)
+
+		require.NoError(t, c.Handle(context.Background(), pickGoldMessage("msg-1", id, 50)))
+
+		dead := deadLetter(t, b)
+		assert.Equal(t, "1", dead.Headers[HeaderAttempts])
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		characters := newCharacters()
+		id := saveCharacter(t, characters)
+		c, b := newTestConsumer(&flakyCharacters{CharacterRepository: characters, failures: 3}, characters)
+
+		require.NoError(t, c.Handle(context.Background(), pickGoldMessage("msg-1", id, 50)))
+
+		dead := deadLetter(t, b)
+		assert.Equal(t, "3", dead.Headers[HeaderAttempts])
+		assert.Equal(t, 0, gold(t, characters, id))
+	})
+}
+
+func TestConsumerRunReadsFromTopic(t *testing.T) {
+	characters := newCharacters()
+	id := saveCharacter(t, characters)
+	// the first delivery fails the check, so the broker delivers it again
+	c, b := newTestConsumer(characters, &flakyProcessed{processed: characters, failures: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Run(ctx) }()
+
+	require.NoError(t, b.Publish(ctx, testTopic, pickGoldMessage("msg-1", id, 10)))
+
+	assert.Eventually(t, func() bool { return gold(t, characters, id) == 10 }, time.Second, 10*time.Millisecond)
+}
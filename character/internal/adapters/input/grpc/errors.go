@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrMalformedID = errors.New("id must be a valid uuid")
+
+type errorMapping struct {
+	err  error
+	code codes.Code
+}
+
+// errorMappings translates sentinel errors into gRPC status codes. Entries
+// are matched in order with errors.Is.
+var errorMappings = []errorMapping{
+	{character.ErrInvalidNicknameSize, codes.InvalidArgument},
+	{character.ErrInvalidNicknameChars, codes.InvalidArgument},
+	{character.ErrInvalidClass, codes.InvalidArgument},
+	{character.ErrInvalidLoginId, codes.InvalidArgument},
+	{character.ErrNicknameTaken, codes.AlreadyExists},
+	{character.ErrEmptyCharacterID, codes.InvalidArgument},
+	{character.ErrCannotJoinGuild, codes.FailedPrecondition},
+	{character.ErrCharacterNotActive, codes.FailedPrecondition},
+
+	{inventory.ErrInventoryIsFull, codes.FailedPrecondition},
+	{inventory.ErrPlayerItemNotFound, codes.NotFound},
+	{inventory.ErrInvalidGoldAmount, codes.InvalidArgument},
+	{inventory.ErrNotEnoughGold, codes.FailedPrecondition},
+
+	{playeritem.ErrNilItemId, codes.InvalidArgument},
+	{playeritem.ErrNilDescription, codes.InvalidArgument},
+	{playeritem.ErrNilQuantity, codes.InvalidArgument},
+
+	{repository.ErrCharacterNotFound, codes.NotFound},
+	{repository.ErrConcurrentUpdate, codes.Aborted},
+
+	{service.ErrUnknownLogin, codes.FailedPrecondition},
+
+	{ErrMalformedID, codes.InvalidArgument},
+}
+
+// toStatus converts err into a gRPC status error. The message only holds the
+// message of the mapped sentinel, never the chain wrapping it, which is
+// logged instead; unknown errors are reported as internal errors.
+func toStatus(ctx context.Context, err error) error {
+	fields := append(logger.FieldsFromContext(ctx), "error", err)
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			slog.Info("Call rejected", append(fields, "code", m.code.String())...)
+			return status.Error(m.code, m.err.Error())
+		}
+	}
+	slog.Error("Call failed", fields...)
+	return status.Error(codes.Internal, "an unexpected error occurred")
+}
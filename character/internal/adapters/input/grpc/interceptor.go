@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/token"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const metadataRequestID = "x-request-id"
+
+// validRequestID bounds the request ids accepted from callers, so that log
+// lines cannot be forged or bloated through the header.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type subjectKey struct{}
+
+// AuthInterceptor validates the bearer token sent in the "authorization"
+// metadata entry before the call reaches the server.
+func AuthInterceptor(authService token.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		}
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+		}
+
+		parts := strings.Fields(values[0])
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "malformed authorization token")
+		}
+
+		subject, err := authService.Authenticate(ctx, parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization token")
+		}
+
+		ctx = context.WithValue(ctx, subjectKey{}, subject)
+		return handler(logger.ContextWithFields(ctx, logger.FieldSubject, subject), req)
+	}
+}
+
+// RequireSubject lets through only the calls authenticated as one of
+// allowed, the game servers. It must run after AuthInterceptor.
+func RequireSubject(allowed []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		subject, ok := ctx.Value(subjectKey{}).(string)
+		if !ok || !slices.Contains(allowed, subject) {
+			return nil, status.Error(codes.PermissionDenied, "the authenticated subject may not call this service")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RequestIDInterceptor tags the call with the "x-request-id" metadata entry,
+// generating one when the caller sent none, and echoes it in the header.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(metadataRequestID); len(values) > 0 && validRequestID.MatchString(values[0]) {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, requestID))
+		return handler(logger.ContextWithFields(ctx, logger.FieldRequestID, requestID), req)
+	}
+}
@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: character.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateCharacterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Class         string                 `protobuf:"bytes,3,opt,name=class,proto3" json:"class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCharacterRequest) Reset() {
+	*x = CreateCharacterRequest{}
+	mi := &file_character_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCharacterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCharacterRequest) ProtoMessage() {}
+
+func (x *CreateCharacterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCharacterRequest.ProtoReflect.Descriptor instead.
+func (*CreateCharacterRequest) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateCharacterRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateCharacterRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *CreateCharacterRequest) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+type CreateCharacterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCharacterResponse) Reset() {
+	*x = CreateCharacterResponse{}
+	mi := &file_character_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCharacterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCharacterResponse) ProtoMessage() {}
+
+func (x *CreateCharacterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCharacterResponse.ProtoReflect.Descriptor instead.
+func (*CreateCharacterResponse) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{1}
+}
+
+type DepositGoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CharacterId   string                 `protobuf:"bytes,1,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VaultId       string                 `protobuf:"bytes,3,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositGoldRequest) Reset() {
+	*x = DepositGoldRequest{}
+	mi := &file_character_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositGoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositGoldRequest) ProtoMessage() {}
+
+func (x *DepositGoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositGoldRequest.ProtoReflect.Descriptor instead.
+func (*DepositGoldRequest) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DepositGoldRequest) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+func (x *DepositGoldRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *DepositGoldRequest) GetVaultId() string {
+	if x != nil {
+		return x.VaultId
+	}
+	return ""
+}
+
+type DepositGoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositGoldResponse) Reset() {
+	*x = DepositGoldResponse{}
+	mi := &file_character_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositGoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositGoldResponse) ProtoMessage() {}
+
+func (x *DepositGoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositGoldResponse.ProtoReflect.Descriptor instead.
+func (*DepositGoldResponse) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{3}
+}
+
+type LeaveGuildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CharacterId   string                 `protobuf:"bytes,1,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveGuildRequest) Reset() {
+	*x = LeaveGuildRequest{}
+	mi := &file_character_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveGuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveGuildRequest) ProtoMessage() {}
+
+func (x *LeaveGuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveGuildRequest.ProtoReflect.Descriptor instead.
+func (*LeaveGuildRequest) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LeaveGuildRequest) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+type LeaveGuildResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveGuildResponse) Reset() {
+	*x = LeaveGuildResponse{}
+	mi := &file_character_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveGuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveGuildResponse) ProtoMessage() {}
+
+func (x *LeaveGuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_character_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveGuildResponse.ProtoReflect.Descriptor instead.
+func (*LeaveGuildResponse) Descriptor() ([]byte, []int) {
+	return file_character_proto_rawDescGZIP(), []int{5}
+}
+
+var File_character_proto protoreflect.FileDescriptor
+
+const file_character_proto_rawDesc = "" +
+	"\n" +
+	"\x0fcharacter.proto\x12\fcharacter.v1\"c\n" +
+	"\x16CreateCharacterRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\x12\x14\n" +
+	"\x05class\x18\x03 \x01(\tR\x05class\"\x19\n" +
+	"\x17CreateCharacterResponse\"n\n" +
+	"\x12DepositGoldRequest\x12!\n" +
+	"\fcharacter_id\x18\x01 \x01(\tR\vcharacterId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x19\n" +
+	"\bvault_id\x18\x03 \x01(\tR\avaultId\"\x15\n" +
+	"\x13DepositGoldResponse\"6\n" +
+	"\x11LeaveGuildRequest\x12!\n" +
+	"\fcharacter_id\x18\x01 \x01(\tR\vcharacterId\"\x14\n" +
+	"\x12LeaveGuildResponse2\x97\x02\n" +
+	"\x10CharacterService\x12^\n" +
+	"\x0fCreateCharacter\x12$.character.v1.CreateCharacterRequest\x1a%.character.v1.CreateCharacterResponse\x12R\n" +
+	"\vDepositGold\x12 .character.v1.DepositGoldRequest\x1a!.character.v1.DepositGoldResponse\x12O\n" +
+	"\n" +
+	"LeaveGuild\x12\x1f.character.v1.LeaveGuildRequest\x1a .character.v1.LeaveGuildResponseBGZEgithub.com/vterry/ddd-study/character/internal/adapters/input/grpc/pbb\x06proto3"
+
+var (
+	file_character_proto_rawDescOnce sync.Once
+	file_character_proto_rawDescData []byte
+)
+
+func file_character_proto_rawDescGZIP() []byte {
+	file_character_proto_rawDescOnce.Do(func() {
+		file_character_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_character_proto_rawDesc), len(file_character_proto_rawDesc)))
+	})
+	return file_character_proto_rawDescData
+}
+
+var file_character_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_character_proto_goTypes = []any{
+	(*CreateCharacterRequest)(nil),  // 0: character.v1.CreateCharacterRequest
+	(*CreateCharacterResponse)(nil), // 1: character.v1.CreateCharacterResponse
+	(*DepositGoldRequest)(nil),      // 2: character.v1.DepositGoldRequest
+	(*DepositGoldResponse)(nil),     // 3: character.v1.DepositGoldResponse
+	(*LeaveGuildRequest)(nil),       // 4: character.v1.LeaveGuildRequest
+	(*LeaveGuildResponse)(nil),      // 5: character.v1.LeaveGuildResponse
+}
+var file_character_proto_depIdxs = []int32{
+	0, // 0: character.v1.CharacterService.CreateCharacter:input_type -> character.v1.CreateCharacterRequest
+	2, // 1: character.v1.CharacterService.DepositGold:input_type -> character.v1.DepositGoldRequest
+	4, // 2: character.v1.CharacterService.LeaveGuild:input_type -> character.v1.LeaveGuildRequest
+	1, // 3: character.v1.CharacterService.CreateCharacter:output_type -> character.v1.CreateCharacterResponse
+	3, // 4: character.v1.CharacterService.DepositGold:output_type -> character.v1.DepositGoldResponse
+	5, // 5: character.v1.CharacterService.LeaveGuild:output_type -> character.v1.LeaveGuildResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_character_proto_init() }
+func file_character_proto_init() {
+	if File_character_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_character_proto_rawDesc), len(file_character_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_character_proto_goTypes,
+		DependencyIndexes: file_character_proto_depIdxs,
+		MessageInfos:      file_character_proto_msgTypes,
+	}.Build()
+	File_character_proto = out.File
+	file_character_proto_goTypes = nil
+	file_character_proto_depIdxs = nil
+}
@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: character.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CharacterService_CreateCharacter_FullMethodName = "/character.v1.CharacterService/CreateCharacter"
+	CharacterService_DepositGold_FullMethodName     = "/character.v1.CharacterService/DepositGold"
+	CharacterService_LeaveGuild_FullMethodName      = "/character.v1.CharacterService/LeaveGuild"
+)
+
+// CharacterServiceClient is the client API for CharacterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CharacterService exposes the character use cases to game servers.
+// Every call must carry an "authorization: Bearer <token>" metadata entry.
+type CharacterServiceClient interface {
+	CreateCharacter(ctx context.Context, in *CreateCharacterRequest, opts ...grpc.CallOption) (*CreateCharacterResponse, error)
+	DepositGold(ctx context.Context, in *DepositGoldRequest, opts ...grpc.CallOption) (*DepositGoldResponse, error)
+	LeaveGuild(ctx context.Context, in *LeaveGuildRequest, opts ...grpc.CallOption) (*LeaveGuildResponse, error)
+}
+
+type characterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCharacterServiceClient(cc grpc.ClientConnInterface) CharacterServiceClient {
+	return &characterServiceClient{cc}
+}
+
+func (c *characterServiceClient) CreateCharacter(ctx context.Context, in *CreateCharacterRequest, opts ...grpc.CallOption) (*CreateCharacterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCharacterResponse)
+	err := c.cc.Invoke(ctx, CharacterService_CreateCharacter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *characterServiceClient) DepositGold(ctx context.Context, in *DepositGoldRequest, opts ...grpc.CallOption) (*DepositGoldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DepositGoldResponse)
+	err := c.cc.Invoke(ctx, CharacterService_DepositGold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *characterServiceClient) LeaveGuild(ctx context.Context, in *LeaveGuildRequest, opts ...grpc.CallOption) (*LeaveGuildResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaveGuildResponse)
+	err := c.cc.Invoke(ctx, CharacterService_LeaveGuild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CharacterServiceServer is the server API for CharacterService service.
+// All implementations must embed UnimplementedCharacterServiceServer
+// for forward compatibility.
+//
+// CharacterService exposes the character use cases to game servers.
+// Every call must carry an "authorization: Bearer <token>" metadata entry.
+type CharacterServiceServer interface {
+	CreateCharacter(context.Context, *CreateCharacterRequest) (*CreateCharacterResponse, error)
+	DepositGold(context.Context, *DepositGoldRequest) (*DepositGoldResponse, error)
+	LeaveGuild(context.Context, *LeaveGuildRequest) (*LeaveGuildResponse, error)
+	mustEmbedUnimplementedCharacterServiceServer()
+}
+
+// UnimplementedCharacterServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCharacterServiceServer struct{}
+
+func (UnimplementedCharacterServiceServer) CreateCharacter(context.Context, *CreateCharacterRequest) (*CreateCharacterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCharacter not implemented")
+}
+func (UnimplementedCharacterServiceServer) DepositGold(context.Context, *DepositGoldRequest) (*DepositGoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DepositGold not implemented")
+}
+func (UnimplementedCharacterServiceServer) LeaveGuild(context.Context, *LeaveGuildRequest) (*LeaveGuildResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveGuild not implemented")
+}
+func (UnimplementedCharacterServiceServer) mustEmbedUnimplementedCharacterServiceServer() {}
+func (UnimplementedCharacterServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeCharacterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CharacterServiceServer will
+// result in compilation errors.
+type UnsafeCharacterServiceServer interface {
+	mustEmbedUnimplementedCharacterServiceServer()
+}
+
+func RegisterCharacterServiceServer(s grpc.ServiceRegistrar, srv CharacterServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCharacterServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CharacterService_ServiceDesc, srv)
+}
+
+func _CharacterService_CreateCharacter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCharacterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CharacterServiceServer).CreateCharacter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CharacterService_CreateCharacter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CharacterServiceServer).CreateCharacter(ctx, req.(*CreateCharacterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CharacterService_DepositGold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositGoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CharacterServiceServer).DepositGold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CharacterService_DepositGold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CharacterServiceServer).DepositGold(ctx, req.(*DepositGoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CharacterService_LeaveGuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveGuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CharacterServiceServer).LeaveGuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CharacterService_LeaveGuild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CharacterServiceServer).LeaveGuild(ctx, req.(*LeaveGuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CharacterService_ServiceDesc is the grpc.ServiceDesc for CharacterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CharacterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "character.v1.CharacterService",
+	HandlerType: (*CharacterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCharacter",
+			Handler:    _CharacterService_CreateCharacter_Handler,
+		},
+		{
+			MethodName: "DepositGold",
+			Handler:    _CharacterService_DepositGold_Handler,
+		},
+		{
+			MethodName: "LeaveGuild",
+			Handler:    _CharacterService_LeaveGuild_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "character.proto",
+}
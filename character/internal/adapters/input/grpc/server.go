@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/grpc/pb"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+)
+
+type CharacterServer struct {
+	pb.UnimplementedCharacterServiceServer
+	characterService service.CharacterService
+}
+
+func NewCharacterServer(characterService service.CharacterService) *CharacterServer {
+	return &CharacterServer{
+		characterService: characterService,
+	}
+}
+
+func (s *CharacterServer) CreateCharacter(ctx context.Context, req *pb.CreateCharacterRequest) (*pb.CreateCharacterResponse, error) {
+	parsedId, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, toStatus(ctx, fmt.Errorf("%w: %w: %v", service.ErrCannotCreateCharacter, ErrMalformedID, err))
+	}
+
+	if err := s.characterService.CreateCharacter(ctx, login.NewLoginID(parsedId), req.GetNickname(), class.FromID(req.GetClass())); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &pb.CreateCharacterResponse{}, nil
+}
+
+func (s *CharacterServer) DepositGold(ctx context.Context, req *pb.DepositGoldRequest) (*pb.DepositGoldResponse, error) {
+	characterID, err := parseCharacterID(req.GetCharacterId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	vaultID, err := uuid.Parse(req.GetVaultId())
+	if err != nil {
+		return nil, toStatus(ctx, fmt.Errorf("%w: %v", ErrMalformedID, err))
+	}
+
+	if err := s.characterService.DepositGold(ctx, characterID, int(req.GetQuantity()), vault.NewVaultID(vaultID)); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &pb.DepositGoldResponse{}, nil
+}
+
+func (s *CharacterServer) LeaveGuild(ctx context.Context, req *pb.LeaveGuildRequest) (*pb.LeaveGuildResponse, error) {
+	characterID, err := parseCharacterID(req.GetCharacterId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	if err := s.characterService.LeaveGuild(ctx, characterID); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &pb.LeaveGuildResponse{}, nil
+}
+
+func parseCharacterID(value string) (character.CharacterID, error) {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return character.CharacterID{}, fmt.Errorf("%w: %v", ErrMalformedID, err)
+	}
+	return character.NewCharacterID(parsed), nil
+}
@@ -0,0 +1,201 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/grpc/pb"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MockCharacterService is a mock implementation of service.CharacterService
+type MockCharacterService struct {
+	mock.Mock
+}
+
+func (m *MockCharacterService) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error {
+	return m.Called(ctx, loginId, nickname, class).Error(0)
+}
+
+func (m *MockCharacterService) TransferItemTo(ctx context.Context, characterId character.CharacterID, playeritem playeritem.PlayerItem, quantity int, vaultId vault.VaultID) error {
+	return m.Called(ctx, characterId, playeritem, quantity, vaultId).Error(0)
+}
+
+func (m *MockCharacterService) TradeItem(ctx context.Context, origin character.CharacterID, playeritem playeritem.PlayerItem, quantity int, destiny character.CharacterID) error {
+	return m.Called(ctx, origin, playeritem, quantity, destiny).Error(0)
+}
+
+func (m *MockCharacterService) DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) error {
+	return m.Called(ctx, characterId, quantity, vaultId).Error(0)
+}
+
+func (m *MockCharacterService) PickItem(ctx context.Context, characterId character.CharacterID, itemId item.ItemID, description string, quantity int) error {
+	return m.Called(ctx, characterId, itemId, description, quantity).Error(0)
+}
+
+func (m *MockCharacterService) DropItem(ctx context.Context, characterId character.CharacterID, playerItemID playeritem.PlayerItemID) error {
+	return m.Called(ctx, characterId, playerItemID).Error(0)
+}
+
+func (m *MockCharacterService) PickGold(ctx context.Context, characterId character.CharacterID, amount int) error {
+	return m.Called(ctx, characterId, amount).Error(0)
+}
+
+func (m *MockCharacterService) LeaveGuild(ctx context.Context, characterID character.CharacterID) error {
+	return m.Called(ctx, characterID).Error(0)
+}
+
+// stubLogin accepts every login
+type stubLogin struct{}
+
+func (stubLogin) IsLoginValid(ctx context.Context, loginId login.LoginID) (bool, error) {
+	return true, nil
+}
+
+// stubAuth accepts the "valid" token of the game server and the "player"
+// token of a player
+type stubAuth struct{}
+
+func (stubAuth) TokenValidation(ctx context.Context, token string) (bool, error) {
+	return token == "valid" || token == "player", nil
+}
+
+func (stubAuth) Authenticate(ctx context.Context, token string) (string, error) {
+	switch token {
+	case "valid":
+		return "game-server", nil
+	case "player":
+		return "player-1", nil
+	}
+	return "", errors.New("invalid token")
+}
+
+func newTestClient(t *testing.T, svc *MockCharacterService) pb.CharacterServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(RequestIDInterceptor(), AuthInterceptor(stubAuth{}), RequireSubject([]string{"game-server"})))
+	pb.RegisterCharacterServiceServer(server, NewCharacterServer(svc))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewCharacterServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestCreateCharacter(t *testing.T) {
+	svc := new(MockCharacterService)
+	svc.On("CreateCharacter", mock.Anything, mock.Anything, "Arthas", class.Warrior).Return(nil)
+	client := newTestClient(t, svc)
+
+	_, err := client.CreateCharacter(withToken("valid"), &pb.CreateCharacterRequest{
+		UserId:   uuid.NewString(),
+		Nickname: "Arthas",
+		Class:    "warrior",
+	})
+
+	assert.NoError(t, err)
+	svc.AssertExpectations(t)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t, new(MockCharacterService))
+
+	_, err := client.LeaveGuild(context.Background(), &pb.LeaveGuildRequest{CharacterId: uuid.NewString()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.LeaveGuild(withToken("forged"), &pb.LeaveGuildRequest{CharacterId: uuid.NewString()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRequireSubject(t *testing.T) {
+	svc := new(MockCharacterService)
+	client := newTestClient(t, svc)
+
+	_, err := client.DepositGold(withToken("player"), &pb.DepositGoldRequest{
+		CharacterId: uuid.NewString(),
+		Quantity:    10,
+		VaultId:     uuid.NewString(),
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "players cannot move gold of any character")
+
+	_, err = client.LeaveGuild(withToken("player"), &pb.LeaveGuildRequest{CharacterId: uuid.NewString()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.CreateCharacter(withToken("player"), &pb.CreateCharacterRequest{UserId: uuid.NewString(), Nickname: "Arthas", Class: "warrior"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	svc.AssertNotCalled(t, "DepositGold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "LeaveGuild", mock.Anything, mock.Anything)
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	svc := new(MockCharacterService)
+	svc.On("LeaveGuild", mock.Anything, mock.Anything).Return(nil)
+	client := newTestClient(t, svc)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(withToken("valid"), "x-request-id", "req-42")
+	_, err := client.LeaveGuild(ctx, &pb.LeaveGuildRequest{CharacterId: uuid.NewString()}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-42"}, header.Get("x-request-id"))
+
+	callCtx := svc.Calls[0].Arguments.Get(0).(context.Context)
+	assert.Equal(t, []interface{}{logger.FieldRequestID, "req-42", logger.FieldSubject, "game-server"}, logger.FieldsFromContext(callCtx))
+}
+
+func TestErrorsAreMappedToStatusCodes(t *testing.T) {
+	svc := new(MockCharacterService)
+	svc.On("DepositGold", mock.Anything, mock.Anything, 500, mock.Anything).Return(inventory.ErrNotEnoughGold)
+	client := newTestClient(t, svc)
+
+	_, err := client.DepositGold(withToken("valid"), &pb.DepositGoldRequest{
+		CharacterId: uuid.NewString(),
+		Quantity:    500,
+		VaultId:     uuid.NewString(),
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.LeaveGuild(withToken("valid"), &pb.LeaveGuildRequest{CharacterId: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestStatusMessageHidesWrappedErrors(t *testing.T) {
+	svc := new(MockCharacterService)
+	svc.On("LeaveGuild", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: dial tcp db.internal:3306: connection refused", repository.ErrConcurrentUpdate))
+	client := newTestClient(t, svc)
+
+	_, err := client.LeaveGuild(withToken("valid"), &pb.LeaveGuildRequest{CharacterId: uuid.NewString()})
+
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Equal(t, repository.ErrConcurrentUpdate.Error(), status.Convert(err).Message())
+}
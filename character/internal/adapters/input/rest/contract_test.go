@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/middleware"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/openapi"
+)
+
+// recordingRouter captures the patterns registered by RegisterRoutes
+type recordingRouter struct {
+	patterns []string
+}
+
+func (r *recordingRouter) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func TestRoutesMatchOpenAPIContract(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	router := &recordingRouter{}
+	NewHandler(CharacterService{}, nil, nil, 0, nil, nil, Access{}, nil, middleware.RateLimits{}).RegisterRoutes(router)
+
+	sort.Strings(documented)
+	sort.Strings(router.patterns)
+	assert.Equal(t, documented, router.patterns, "rest.Handler routes and openapi.json have drifted apart")
+}
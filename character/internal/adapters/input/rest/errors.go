@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/transfer"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/specifications"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings translates sentinel errors into HTTP statuses and stable
+// error codes. Entries are matched in order with errors.Is, so the most
+// specific errors must come before the generic ones that wrap them.
+var errorMappings = []errorMapping{
+	{character.ErrInvalidNicknameSize, http.StatusBadRequest, "INVALID_NICKNAME_SIZE"},
+	{character.ErrInvalidNicknameChars, http.StatusBadRequest, "INVALID_NICKNAME_CHARS"},
+	{character.ErrInvalidClass, http.StatusBadRequest, "INVALID_CLASS"},
+	{character.ErrInvalidLoginId, http.StatusBadRequest, "INVALID_LOGIN_ID"},
+	{character.ErrNicknameTaken, http.StatusConflict, "NICKNAME_TAKEN"},
+	{character.ErrEmptyCharacterID, http.StatusBadRequest, "EMPTY_CHARACTER_ID"},
+	{character.ErrCannotJoinGuild, http.StatusConflict, "ALREADY_IN_GUILD"},
+	{character.ErrCharacterNotActive, http.StatusConflict, "CHARACTER_NOT_ACTIVE"},
+	{character.ErrInvalidStatusTransition, http.StatusConflict, "INVALID_STATUS_TRANSITION"},
+	{character.ErrMissingStatusReason, http.StatusBadRequest, "MISSING_STATUS_REASON"},
+	{character.ErrInvalidSuspensionEnd, http.StatusBadRequest, "INVALID_SUSPENSION_END"},
+
+	{class.ErrInvalidClassID, http.StatusBadRequest, "INVALID_CLASS_ID"},
+	{class.ErrMissingDisplayName, http.StatusBadRequest, "MISSING_CLASS_DISPLAY_NAME"},
+	{class.ErrInvalidStartingItem, http.StatusBadRequest, "INVALID_STARTING_ITEM"},
+	{class.ErrTooManyStartingItems, http.StatusBadRequest, "TOO_MANY_STARTING_ITEMS"},
+	{class.ErrInvalidStat, http.StatusBadRequest, "INVALID_CLASS_STAT"},
+
+	{inventory.ErrInventoryIsFull, http.StatusConflict, "INVENTORY_FULL"},
+	{inventory.ErrPlayerItemNotFound, http.StatusNotFound, "PLAYER_ITEM_NOT_FOUND"},
+	{inventory.ErrInvalidGoldAmount, http.StatusBadRequest, "INVALID_GOLD_AMOUNT"},
+	{inventory.ErrNotEnoughGold, http.StatusUnprocessableEntity, "NOT_ENOUGH_GOLD"},
+
+	{playeritem.ErrNilItemId, http.StatusBadRequest, "MISSING_ITEM_ID"},
+	{playeritem.ErrNilDescription, http.StatusBadRequest, "MISSING_ITEM_DESCRIPTION"},
+	{playeritem.ErrNilQuantity, http.StatusBadRequest, "MISSING_ITEM_QUANTITY"},
+
+	{ledger.ErrInvalidAccount, http.StatusBadRequest, "INVALID_LEDGER_ACCOUNT"},
+	{ledger.ErrMissingTransactionID, http.StatusBadRequest, "MISSING_TRANSACTION_ID"},
+	{ledger.ErrTooFewEntries, http.StatusBadRequest, "TOO_FEW_LEDGER_ENTRIES"},
+	{ledger.ErrZeroEntry, http.StatusBadRequest, "ZERO_LEDGER_ENTRY"},
+	{ledger.ErrDuplicateAccount, http.StatusBadRequest, "DUPLICATE_LEDGER_ACCOUNT"},
+	{ledger.ErrUnbalancedTransaction, http.StatusUnprocessableEntity, "UNBALANCED_TRANSACTION"},
+
+	{wallet.ErrInvalidLoginID, http.StatusBadRequest, "INVALID_LOGIN_ID"},
+	{wallet.ErrInvalidCashAmount, http.StatusBadRequest, "INVALID_CASH_AMOUNT"},
+	{wallet.ErrInvalidReference, http.StatusBadRequest, "INVALID_CASH_REFERENCE"},
+	{wallet.ErrNotEnoughCash, http.StatusUnprocessableEntity, "NOT_ENOUGH_CASH"},
+	{wallet.ErrUntrustedSource, http.StatusUnprocessableEntity, "UNTRUSTED_CASH_SOURCE"},
+	{wallet.ErrDuplicateReference, http.StatusConflict, "DUPLICATE_CASH_REFERENCE"},
+
+	{repository.ErrCharacterNotFound, http.StatusNotFound, "CHARACTER_NOT_FOUND"},
+	{repository.ErrConcurrentUpdate, http.StatusConflict, "CONCURRENT_UPDATE"},
+	{repository.ErrTransactionConflict, http.StatusConflict, "TRANSACTION_CONFLICT"},
+	{repository.ErrConcurrentWalletUpdate, http.StatusConflict, "CONCURRENT_UPDATE"},
+
+	{service.ErrCharacterAccountPosting, http.StatusUnprocessableEntity, "CHARACTER_ACCOUNT_NOT_POSTABLE"},
+	{service.ErrReservedTransactionID, http.StatusUnprocessableEntity, "RESERVED_TRANSACTION_ID"},
+	{service.ErrUnknownLogin, http.StatusUnprocessableEntity, "INVALID_LOGIN"},
+	{service.ErrInvalidSearchLimit, http.StatusBadRequest, "INVALID_SEARCH"},
+
+	{transfer.ErrInvalidDocument, http.StatusBadRequest, "INVALID_EXPORT_DOCUMENT"},
+	{transfer.ErrUnsupportedDocument, http.StatusBadRequest, "UNSUPPORTED_EXPORT_DOCUMENT"},
+
+	{ErrMalformedLoginID, http.StatusBadRequest, "MALFORMED_LOGIN_ID"},
+	{ErrMalformedCharacterID, http.StatusBadRequest, "MALFORMED_CHARACTER_ID"},
+	{ErrInvalidHistoryFilter, http.StatusBadRequest, "INVALID_HISTORY_FILTER"},
+	{ErrInvalidSearch, http.StatusBadRequest, "INVALID_SEARCH"},
+}
+
+// fieldAliases renames domain fields that are exposed under another name in
+// the request payload.
+var fieldAliases = map[string]string{
+	"loginId": "userId",
+}
+
+// problemFromError builds the problem document for err. Specification
+// violations found in err are listed as field errors. The detail only holds
+// the message of the mapped sentinel, never the chain wrapping it, which may
+// carry repository or driver errors; unknown errors are reported as internal
+// errors.
+func problemFromError(err error) problem.Details {
+	fieldErrors := fieldErrorsFromViolations(specifications.Violations(err))
+
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			p := problem.New(m.status, m.code, m.err.Error())
+			p.Errors = fieldErrors
+			return p
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		p := problem.New(http.StatusBadRequest, problem.CodeValidationFailed, "request failed validation")
+		p.Errors = fieldErrors
+		return p
+	}
+
+	return problem.New(http.StatusInternalServerError, problem.CodeInternal, "an unexpected error occurred")
+}
+
+func validationProblem(errs validator.ValidationErrors) problem.Details {
+	p := problem.New(http.StatusBadRequest, problem.CodeInvalidPayload, "request payload failed validation")
+	for _, fe := range errs {
+		var params map[string]any
+		if fe.Param() != "" {
+			params = map[string]any{"param": fe.Param()}
+		}
+		p.Errors = append(p.Errors, problem.FieldError{
+			Field:   fe.Field(),
+			Rule:    strings.ToUpper(fe.Tag()),
+			Message: fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag()),
+			Params:  params,
+		})
+	}
+	return p
+}
+
+func fieldErrorsFromViolations(violations []*specifications.Violation) []problem.FieldError {
+	var fieldErrors []problem.FieldError
+	for _, v := range violations {
+		field := v.Field
+		if alias, ok := fieldAliases[field]; ok {
+			field = alias
+		}
+		fieldErrors = append(fieldErrors, problem.FieldError{
+			Field:   field,
+			Rule:    v.Rule,
+			Message: v.Error(),
+			Params:  v.Params,
+		})
+	}
+	return fieldErrors
+}
+
+// writeError answers with the problem document for err and logs the whole
+// error chain, which the client does not get to see.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	p := problemFromError(err)
+	fields := append(logger.FieldsFromContext(r.Context()), "status", p.Status, "code", p.Code, "error", err)
+	if p.Status >= http.StatusInternalServerError {
+		slog.Error("Request failed", fields...)
+	} else {
+		slog.Info("Request rejected", fields...)
+	}
+	problem.Write(w, r, p)
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"github.com/vterry/ddd-study/character/internal/utils"
+)
+
+func TestProblemFromError(t *testing.T) {
+	_, nicknameErr := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "abc", login.NewLoginID(uuid.New()), class.Mage, vault.NewVaultID(uuid.New()))
+	_, classErr := class.ParseClass(class.Defaults(), "paladin")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"wrapped nickname size", fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, nicknameErr), http.StatusBadRequest, "INVALID_NICKNAME_SIZE"},
+		{"invalid class", fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, classErr), http.StatusBadRequest, "INVALID_CLASS"},
+		{"not enough gold", fmt.Errorf("failed: %w", inventory.ErrNotEnoughGold), http.StatusUnprocessableEntity, "NOT_ENOUGH_GOLD"},
+		{"invalid login", fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, service.ErrUnknownLogin), http.StatusUnprocessableEntity, "INVALID_LOGIN"},
+		{"unknown error", errors.New("database is down"), http.StatusInternalServerError, problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := problemFromError(tt.err)
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, tt.wantCode, p.Code)
+		})
+	}
+}
+
+func TestWriteErrorUsesProblemJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/character", nil)
+
+	writeError(rec, req, errors.New("database password is hunter2"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+	var body problem.Details
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, problem.CodeInternal, body.Code)
+	assert.Equal(t, "/character", body.Instance)
+	assert.NotContains(t, body.Detail, "hunter2")
+}
+
+func TestProblemDetailHidesWrappedErrors(t *testing.T) {
+	err := fmt.Errorf("failed to find character: %w: Error 1045: Access denied for user 'character'@'db.internal'", repository.ErrCharacterNotFound)
+
+	p := problemFromError(err)
+
+	assert.Equal(t, http.StatusNotFound, p.Status)
+	assert.Equal(t, repository.ErrCharacterNotFound.Error(), p.Detail)
+}
+
+func TestProblemFromErrorListsFieldErrors(t *testing.T) {
+	_, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "a!", login.LoginID{}, class.Mage, vault.NewVaultID(uuid.New()))
+
+	p := problemFromError(fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err))
+
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Len(t, p.Errors, 3)
+	assert.Equal(t, problem.FieldError{
+		Field:   "nickname",
+		Rule:    character.RuleLength,
+		Message: character.ErrInvalidNicknameSize.Error(),
+		Params:  map[string]any{"min": character.MinNicknameLength, "max": character.MaxNicknameLength},
+	}, p.Errors[0])
+	assert.Equal(t, "nickname", p.Errors[1].Field)
+	assert.Equal(t, character.RuleCharset, p.Errors[1].Rule)
+	assert.Equal(t, "userId", p.Errors[2].Field)
+	assert.Equal(t, character.RuleRequired, p.Errors[2].Rule)
+}
+
+func TestValidationProblem(t *testing.T) {
+	err := utils.Validate.Struct(CreateCharacterRequest{Nickname: "Arthas"})
+
+	p := validationProblem(err.(validator.ValidationErrors))
+
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, problem.CodeInvalidPayload, p.Code)
+	assert.Len(t, p.Errors, 2)
+	assert.Equal(t, "userId", p.Errors[0].Field)
+	assert.Equal(t, "REQUIRED", p.Errors[0].Rule)
+	assert.Equal(t, "class", p.Errors[1].Field)
+}
@@ -1,36 +1,71 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"slices"
 	"strings"
 
-	"github.com/vterry/ddd-study/character/internal/adapters/input/token"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/token"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 )
 
-func Auhtentication(tokenAdapter token.TokenValidationAdapter) func(http.Handler) http.Handler {
+type subjectKey struct{}
+
+// SubjectFromContext returns the subject authenticated for the request, and
+// false when the request went through no authentication.
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
+
+func Auhtentication(tokenAdapter token.AuthService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				unauthorized(w, r)
 				return
 			}
 
 			// Check if the Authorization header has the correct format
 			parts := strings.Fields(authHeader)
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				unauthorized(w, r)
 				return
 			}
 
-			token := parts[1]
-			valid, err := tokenAdapter.TokenValidation(r.Context(), token)
-			if err != nil || !valid {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			subject, err := tokenAdapter.Authenticate(r.Context(), parts[1])
+			if err != nil {
+				unauthorized(w, r)
 				return
 			}
 
+			recordSubject(r.Context(), subject)
+			ctx := context.WithValue(r.Context(), subjectKey{}, subject)
+			ctx = logger.ContextWithFields(ctx, logger.FieldSubject, subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireSubject lets through only the requests authenticated as one of
+// allowed. It must run after Auhtentication.
+func RequireSubject(allowed []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, ok := SubjectFromContext(r.Context())
+			if !ok || !slices.Contains(allowed, subject) {
+				problem.Write(w, r, problem.New(http.StatusForbidden, problem.CodeForbidden, "the authenticated subject may not call this operation"))
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	problem.Write(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "a valid bearer token is required"))
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTokens struct{}
+
+func (fakeTokens) TokenValidation(ctx context.Context, token string) (bool, error) {
+	return token == "valid", nil
+}
+
+func (fakeTokens) Authenticate(ctx context.Context, token string) (string, error) {
+	if token != "valid" {
+		return "", errors.New("invalid token")
+	}
+	return "player-1", nil
+}
+
+func TestRequireSubject(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		allowed        []string
+		expectedStatus int
+	}{
+		{name: "allowed subject", header: "Bearer valid", allowed: []string{"payments", "player-1"}, expectedStatus: http.StatusNoContent},
+		{name: "other subject", header: "Bearer valid", allowed: []string{"payments"}, expectedStatus: http.StatusForbidden},
+		{name: "no allowed subjects", header: "Bearer valid", expectedStatus: http.StatusForbidden},
+		{name: "invalid token", header: "Bearer other", allowed: []string{"player-1"}, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject string
+			handler := Chain(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					subject, _ = SubjectFromContext(r.Context())
+					w.WriteHeader(http.StatusNoContent)
+				}),
+				Auhtentication(fakeTokens{}),
+				RequireSubject(tt.allowed),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, "player-1", subject)
+			}
+		})
+	}
+}
+
+func TestSubjectFromContextWithoutAuthentication(t *testing.T) {
+	_, ok := SubjectFromContext(context.Background())
+	assert.False(t, ok)
+}
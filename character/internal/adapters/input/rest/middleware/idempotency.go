@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+const (
+	IdempotencyKeyHeader    = "Idempotency-Key"
+	IdempotentReplayHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 128
+
+	// idempotencyStoreTimeout bounds the writes made after the handler ran,
+	// which no longer follow the request context.
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+var (
+	ErrIdempotencyKeyMissing  = errors.New("idempotency key header is required")
+	ErrIdempotencyKeyTooLong  = errors.New("idempotency key must not exceed 128 characters")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different payload")
+	ErrIdempotencyKeyPending  = errors.New("a request with this idempotency key is still being processed")
+)
+
+const (
+	codeIdempotencyKeyMissing  = "IDEMPOTENCY_KEY_MISSING"
+	codeIdempotencyKeyTooLong  = "IDEMPOTENCY_KEY_TOO_LONG"
+	codeIdempotencyKeyMismatch = "IDEMPOTENCY_KEY_MISMATCH"
+	codeIdempotencyKeyPending  = "IDEMPOTENCY_KEY_PENDING"
+)
+
+// Idempotency makes mutating routes safe to retry. The first request for a key
+// reserves it together with a fingerprint of the payload, and its response is
+// stored until the ttl expires. Retries with the same key and payload receive
+// the stored response, while reusing the key with another payload is rejected.
+// Keys are scoped by the authenticated subject, so it must run after
+// Auhtentication.
+func Idempotency(store repository.IdempotencyRepository, ttl time.Duration, clock clock.Clock) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				problem.Write(w, r, problem.New(http.StatusBadRequest, codeIdempotencyKeyMissing, ErrIdempotencyKeyMissing.Error()))
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				problem.Write(w, r, problem.New(http.StatusBadRequest, codeIdempotencyKeyTooLong, ErrIdempotencyKeyTooLong.Error()))
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				if body, err = io.ReadAll(r.Body); err != nil {
+					problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+					return
+				}
+			}
+			r.Body = io.NopCloser(bytes.NewBuffer(body))
+
+			scopedKey := scopeKey(r, key)
+			fingerprint := requestFingerprint(r, body)
+
+			record, err := store.Find(r.Context(), scopedKey)
+			switch {
+			case err == nil:
+				replay(w, r, record, fingerprint)
+				return
+			case !errors.Is(err, repository.ErrIdempotencyKeyNotFound):
+				internalError(w, r)
+				return
+			}
+
+			err = store.Reserve(r.Context(), scopedKey, fingerprint, clock.Now().Add(ttl))
+			if errors.Is(err, repository.ErrIdempotencyKeyInUse) {
+				problem.Write(w, r, problem.New(http.StatusConflict, codeIdempotencyKeyPending, ErrIdempotencyKeyPending.Error()))
+				return
+			}
+			if err != nil {
+				internalError(w, r)
+				return
+			}
+
+			rw := newResponseWriter(w)
+			defer func() {
+				// a panicking handler never produced a response to store, so
+				// the key is released for the retry
+				if p := recover(); p != nil {
+					releaseKey(r, store, scopedKey)
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+
+			// Server failures are not stored so the client can retry them.
+			if rw.status >= http.StatusInternalServerError {
+				releaseKey(r, store, scopedKey)
+				return
+			}
+
+			// the response is already sent, possibly to a client that went
+			// away, so the write must not fail with the request context
+			ctx, cancel := storeContext(r)
+			defer cancel()
+			if err := store.Complete(ctx, scopedKey, rw.status, rw.Header().Get("Content-Type"), rw.responseBody.Bytes()); err != nil {
+				slog.Error("Failed to store idempotent response", append(logger.FieldsFromContext(r.Context()), "key", scopedKey, "error", err)...)
+			}
+		})
+	}
+}
+
+// storeContext detaches the store writes from the request, whose context is
+// canceled once the client disconnects, while keeping its values.
+func storeContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyStoreTimeout)
+}
+
+func releaseKey(r *http.Request, store repository.IdempotencyRepository, key string) {
+	ctx, cancel := storeContext(r)
+	defer cancel()
+	if err := store.Release(ctx, key); err != nil {
+		slog.Error("Failed to release idempotency key", append(logger.FieldsFromContext(r.Context()), "key", key, "error", err)...)
+	}
+}
+
+// scopeKey scopes the client key by subject, method and path, so that
+// clients choosing the same key never see each other's responses. The path
+// has no length limit, so the scoped key is stored as its SHA-256.
+func scopeKey(r *http.Request, key string) string {
+	subject, _ := SubjectFromContext(r.Context())
+	hash := sha256.Sum256([]byte(subject + " " + r.Method + " " + r.URL.Path + " " + key))
+	return hex.EncodeToString(hash[:])
+}
+
+func replay(w http.ResponseWriter, r *http.Request, record *repository.IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, codeIdempotencyKeyMismatch, ErrIdempotencyKeyMismatch.Error()))
+		return
+	}
+
+	if !record.Completed {
+		problem.Write(w, r, problem.New(http.StatusConflict, codeIdempotencyKeyPending, ErrIdempotencyKeyPending.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", record.ContentType)
+	w.Header().Set(IdempotentReplayHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte(r.URL.Path))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
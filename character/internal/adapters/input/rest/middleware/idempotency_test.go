@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// fakeIdempotencyStore is an in-memory implementation of IdempotencyRepository for tests
+type fakeIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]repository.IdempotencyRecord
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{records: make(map[string]repository.IdempotencyRecord)}
+}
+
+func (f *fakeIdempotencyStore) Find(ctx context.Context, key string) (*repository.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, ok := f.records[key]
+	if !ok || time.Now().After(record.ExpiresAt) {
+		return nil, repository.ErrIdempotencyKeyNotFound
+	}
+	return &record, nil
+}
+
+func (f *fakeIdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if record, ok := f.records[key]; ok && time.Now().Before(record.ExpiresAt) {
+		return repository.ErrIdempotencyKeyInUse
+	}
+	f.records[key] = repository.IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record := f.records[key]
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	record.Completed = true
+	f.records[key] = record
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, key)
+	return nil
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func newIdempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/character", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("missing key is rejected", func(t *testing.T) {
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newIdempotentRequest("", `{}`))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("retry replays stored response", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`"character created"`))
+		}))
+
+		first := httptest.NewRecorder()
+		handler.ServeHTTP(first, newIdempotentRequest("key-1", `{"nickname":"Arthas"}`))
+
+		second := httptest.NewRecorder()
+		handler.ServeHTTP(second, newIdempotentRequest("key-1", `{"nickname":"Arthas"}`))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayHeader))
+	})
+
+	t.Run("different payload is rejected", func(t *testing.T) {
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{"nickname":"Arthas"}`))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newIdempotentRequest("key-1", `{"nickname":"Jaina"}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("server failures are not stored", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{}`))
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{}`))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("request in progress is reported as conflict", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		req := newIdempotentRequest("key-1", `{}`)
+		assert.NoError(t, store.Reserve(context.Background(), scopeKey(req, "key-1"), requestFingerprint(req, []byte(`{}`)), time.Now().Add(time.Hour)))
+
+		handler := Idempotency(store, time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+	t.Run("reservation expires one ttl after the clock's now", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		now := time.Now().Add(time.Minute).Truncate(time.Second)
+		handler := Idempotency(store, time.Hour, fixedClock(now))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := newIdempotentRequest("key-1", `{}`)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		record := store.records[scopeKey(req, "key-1")]
+		assert.Equal(t, now.Add(time.Hour), record.ExpiresAt)
+	})
+
+	t.Run("keys are scoped by subject", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		for _, subject := range []string{"player-1", "player-2"} {
+			req := newIdempotentRequest("key-1", `{}`)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req.WithContext(context.WithValue(req.Context(), subjectKey{}, subject)))
+			assert.Empty(t, rec.Header().Get(IdempotentReplayHeader))
+		}
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("stored keys have a fixed length whatever the path", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		handler := Idempotency(store, time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/character/"+strings.Repeat("x", 4096), strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Len(t, store.records, 1)
+		for key := range store.records {
+			assert.Len(t, key, 64, "the column holds a SHA-256")
+		}
+	})
+
+	t.Run("response is stored after the client went away", func(t *testing.T) {
+		calls := 0
+		ctx, cancel := context.WithCancel(context.Background())
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`"character created"`))
+			cancel()
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{}`).WithContext(ctx))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newIdempotentRequest("key-1", `{}`))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `"character created"`, rec.Body.String())
+		assert.Equal(t, "true", rec.Header().Get(IdempotentReplayHeader))
+	})
+
+	t.Run("panicking handler releases the key", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newFakeIdempotencyStore(), time.Hour, systemClock{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				panic("boom")
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		assert.Panics(t, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{}`))
+		})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newIdempotentRequest("key-1", `{}`))
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+}
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 )
 
 // responseWriter aprimorado para capturar status e corpo da resposta.
@@ -35,6 +38,22 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+type accessLogKey struct{}
+
+// accessLog guarda os campos conhecidos só depois do LoggingMiddleware, como
+// o subject gravado pela Auhtentication, para a linha de log de acesso.
+type accessLog struct {
+	subject string
+}
+
+// recordSubject anota o subject autenticado na linha de log de acesso, quando
+// a requisição passou pelo LoggingMiddleware.
+func recordSubject(ctx context.Context, subject string) {
+	if log, ok := ctx.Value(accessLogKey{}).(*accessLog); ok {
+		log.subject = subject
+	}
+}
+
 // LoggingMiddleware aprimorado.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -51,23 +70,30 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		// Cria nosso responseWriter customizado para capturar status e corpo.
 		rw := newResponseWriter(w)
 
-		next.ServeHTTP(rw, r)
+		// A autenticação roda depois deste middleware e anota o subject aqui.
+		access := &accessLog{}
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, access)))
 
 		duration := time.Since(start)
 
+		// Inclui os campos de correlação (requestId) gravados no contexto pelo RequestID.
+		attrs := append([]any{}, logger.FieldsFromContext(r.Context())...)
+		if access.subject != "" {
+			attrs = append(attrs, logger.FieldSubject, access.subject)
+		}
+
 		// Loga as informações do request e response usando slog.Group para melhor estrutura.
-		slog.Info(
-			"requisição processada",
-			slog.Group("http",
-				slog.String("method", r.Method),
-				slog.String("uri", r.RequestURI),
-				slog.String("remote_addr", r.RemoteAddr),
-				slog.Int("status", rw.status),
-				slog.Duration("duration", duration),
-				slog.String("user_agent", r.UserAgent()),
-				slog.String("request_body", string(requestBodyBytes)),
-				slog.String("response_body", rw.responseBody.String()),
-			),
-		)
+		attrs = append(attrs, slog.Group("http",
+			slog.String("method", r.Method),
+			slog.String("uri", r.RequestURI),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.Int("status", rw.status),
+			slog.Duration("duration", duration),
+			slog.String("user_agent", r.UserAgent()),
+			slog.String("request_body", string(requestBodyBytes)),
+			slog.String("response_body", rw.responseBody.String()),
+		))
+
+		slog.Info("requisição processada", attrs...)
 	})
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddlewareLogsTheSubject(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	handler := Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+		RequestID,
+		LoggingMiddleware,
+		Auhtentication(fakeTokens{}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/character/search", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "player-1", line["subject"], "the subject authenticated inside the chain is logged")
+	assert.NotEmpty(t, line["requestId"])
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests no route pattern matched, so unknown paths
+// cannot blow up the cardinality of the metrics.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of the requests served by mux, both
+// labelled by route pattern, method and status. It must wrap the mux itself:
+// the route is read from the pattern the mux sets on the request.
+func Metrics(requests *prometheus.CounterVec, duration *prometheus.HistogramVec) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			status := strconv.Itoa(rec.status)
+
+			requests.WithLabelValues(route, r.Method, status).Inc()
+			duration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests"}, []string{"route", "method", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "duration"}, []string{"route", "method", "status"})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /character", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	mux.Handle("GET /character/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	handler := Metrics(requests, duration)(mux)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/character", nil),
+		httptest.NewRequest(http.MethodGet, "/character/1", nil),
+		httptest.NewRequest(http.MethodGet, "/character/2", nil),
+		httptest.NewRequest(http.MethodGet, "/unknown", nil),
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(requests.WithLabelValues("POST /character", http.MethodPost, "201")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(requests.WithLabelValues("GET /character/{id}", http.MethodGet, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requests.WithLabelValues(unmatchedRoute, http.MethodGet, "404")))
+	assert.Equal(t, 3, testutil.CollectAndCount(duration))
+}
@@ -1,6 +1,10 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+)
 
 type Middleware func(http.Handler) http.Handler
 
@@ -10,3 +14,7 @@ func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
 	}
 	return h
 }
+
+func internalError(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.New(http.StatusInternalServerError, problem.CodeInternal, "an unexpected error occurred"))
+}
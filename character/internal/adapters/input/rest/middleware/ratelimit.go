@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+var ErrRateLimited = errors.New("too many requests, retry later")
+
+const codeRateLimited = "RATE_LIMITED"
+
+// RateLimits are the token buckets applied by RateLimit. Routes are keyed by
+// the pattern they were registered with, e.g. "POST /character"; the other
+// routes use Default.
+type RateLimits struct {
+	Default ratelimit.Limit
+	Routes  map[string]ratelimit.Limit
+}
+
+func (l RateLimits) forRoute(pattern string) ratelimit.Limit {
+	if limit, ok := l.Routes[pattern]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// RateLimit gives every caller its own token bucket per route. Callers are
+// told apart by the subject of their token, so it must run after
+// Auhtentication, and by the client IP on routes without authentication.
+// Every response carries the state of the bucket, and a refused request is
+// answered with 429 and a Retry-After header.
+func RateLimit(store repository.RateLimitRepository, limits RateLimits, clock clock.Clock) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Pattern
+			if route == "" {
+				route = r.Method + " " + r.URL.Path
+			}
+
+			decision, err := store.Take(r.Context(), route+" "+caller(r), limits.forRoute(route), clock.Now())
+			if err != nil {
+				internalError(w, r)
+				return
+			}
+
+			w.Header().Set(HeaderRateLimitLimit, strconv.Itoa(decision.Limit))
+			w.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(decision.Remaining))
+			w.Header().Set(HeaderRateLimitReset, seconds(decision.Reset))
+			if !decision.Allowed {
+				w.Header().Set(HeaderRetryAfter, seconds(decision.RetryAfter))
+				problem.Write(w, r, problem.New(http.StatusTooManyRequests, codeRateLimited, ErrRateLimited.Error()))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// caller identifies who is calling. Forwarding headers are ignored: anyone
+// could set them to get a fresh bucket.
+func caller(r *http.Request) string {
+	if subject, ok := SubjectFromContext(r.Context()); ok {
+		return "subject:" + subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds d up to whole seconds, as the rate limit headers expect.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("store is down")
+}
+
+func newRateLimitedMux(limits RateLimits) *http.ServeMux {
+	store := memory.NewRateLimitRepository()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /character", Chain(ok, Auhtentication(fakeTokens{}), RateLimit(store, limits, fixedClock(time.Now()))))
+	mux.Handle("GET /character/{characterId}", Chain(ok, Auhtentication(fakeTokens{}), RateLimit(store, limits, fixedClock(time.Now()))))
+	mux.Handle("GET /openapi.json", RateLimit(store, limits, fixedClock(time.Now()))(ok))
+	return mux
+}
+
+func serve(mux http.Handler, method, path, token, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	mux := newRateLimitedMux(RateLimits{
+		Default: ratelimit.Limit{Requests: 5, Per: time.Minute},
+		Routes:  map[string]ratelimit.Limit{"POST /character": {Requests: 2, Per: time.Minute}},
+	})
+
+	rec := serve(mux, http.MethodPost, "/character", "valid", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "1", rec.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "30", rec.Header().Get(HeaderRateLimitReset))
+
+	serve(mux, http.MethodPost, "/character", "valid", "10.0.0.2:1234")
+	rec = serve(mux, http.MethodPost, "/character", "valid", "10.0.0.3:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the subject is limited whatever its address")
+	assert.Equal(t, "30", rec.Header().Get(HeaderRetryAfter))
+	assert.Equal(t, "0", rec.Header().Get(HeaderRateLimitRemaining))
+	assert.Contains(t, rec.Body.String(), codeRateLimited)
+
+	rec = serve(mux, http.MethodGet, "/character/1", "valid", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "other routes have their own bucket")
+	assert.Equal(t, "5", rec.Header().Get(HeaderRateLimitLimit))
+}
+
+func TestRateLimitFallsBackToClientIP(t *testing.T) {
+	mux := newRateLimitedMux(RateLimits{Default: ratelimit.Limit{Requests: 1, Per: time.Minute}})
+
+	assert.Equal(t, http.StatusNoContent, serve(mux, http.MethodGet, "/openapi.json", "", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(mux, http.MethodGet, "/openapi.json", "", "10.0.0.1:5678").Code)
+	assert.Equal(t, http.StatusNoContent, serve(mux, http.MethodGet, "/openapi.json", "", "10.0.0.2:1234").Code)
+}
+
+func TestRateLimitStoreFailure(t *testing.T) {
+	handler := RateLimit(failingRateLimitStore{}, RateLimits{Default: ratelimit.Limit{Requests: 1, Per: time.Minute}}, fixedClock(time.Now()))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Fail(t, "the request must not go through")
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// validRequestID bounds the request ids accepted from clients, so that log
+// lines cannot be forged or bloated through the header.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID tags the request with the X-Request-ID sent by the client, or a
+// new one when it is missing or malformed, and echoes it in the response.
+// Every logger built with WithContext downstream includes it.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+		ctx := logger.ContextWithFields(r.Context(), logger.FieldRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
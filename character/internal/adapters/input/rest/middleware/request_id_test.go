@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		expectSame bool
+	}{
+		{name: "keeps the client id", header: "client-id-123", expectSame: true},
+		{name: "generates a missing id", header: ""},
+		{name: "replaces a malformed id", header: "bad id\nwith newline"},
+		{name: "replaces an oversized id", header: strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []interface{}
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fields = logger.FieldsFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(HeaderRequestID)
+			if tt.expectSame {
+				assert.Equal(t, tt.header, requestID)
+			} else {
+				_, err := uuid.Parse(requestID)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, []interface{}{logger.FieldRequestID, requestID}, fields)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/vterry/ddd-study/character/internal/adapters/input/rest"
+
+// Tracing opens a server span for every request served by mux, continuing the
+// trace of the caller when it sends a W3C traceparent header. Like Metrics it
+// must wrap the mux itself, so the span is named after the matched route.
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) Middleware {
+	tracer := provider.Tracer(tracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			traced := r.WithContext(ctx)
+
+			next.ServeHTTP(rec, traced)
+
+			route := traced.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			span.SetName(route)
+			span.SetAttributes(
+				semconv.HTTPRoute(route),
+				semconv.HTTPResponseStatusCode(rec.status),
+			)
+			if rec.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rec.status))
+			}
+		})
+	}
+}
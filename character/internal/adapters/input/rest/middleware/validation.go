@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/openapi"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+)
+
+// RequestValidation rejects requests that do not match the OpenAPI document
+// before they reach the handler. Routes missing from the document are left to
+// the contract test and pass through unvalidated.
+func RequestValidation(validator *openapi.Validator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := validator.Validate(r)
+			if err != nil && !errors.Is(err, openapi.ErrRouteNotDocumented) {
+				p := problem.New(http.StatusBadRequest, problem.CodeInvalidPayload, "request does not match the api contract")
+				p.Errors = contractFieldErrors(err)
+				problem.Write(w, r, p)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func contractFieldErrors(err error) []problem.FieldError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var fieldErrors []problem.FieldError
+		for _, inner := range multi {
+			fieldErrors = append(fieldErrors, contractFieldErrors(inner)...)
+		}
+		return fieldErrors
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		if requestErr.Parameter != nil {
+			return []problem.FieldError{{
+				Field:   requestErr.Parameter.Name,
+				Rule:    schemaRule(requestErr.Err),
+				Message: requestErr.Error(),
+			}}
+		}
+		if requestErr.Err != nil {
+			return contractFieldErrors(requestErr.Err)
+		}
+		return []problem.FieldError{{Rule: "INVALID", Message: requestErr.Error()}}
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []problem.FieldError{{
+			Field:   strings.Join(schemaErr.JSONPointer(), "."),
+			Rule:    schemaRule(schemaErr),
+			Message: schemaErr.Reason,
+		}}
+	}
+
+	return []problem.FieldError{{Rule: "INVALID", Message: err.Error()}}
+}
+
+func schemaRule(err error) string {
+	if errors.Is(err, openapi3filter.ErrInvalidRequired) {
+		return "REQUIRED"
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.SchemaField != "" {
+		return strings.ToUpper(schemaErr.SchemaField)
+	}
+	return "INVALID"
+}
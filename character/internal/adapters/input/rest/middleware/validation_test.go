@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/openapi"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+)
+
+func newTestValidator(t *testing.T) *openapi.Validator {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	validator, err := openapi.NewValidator(doc)
+	require.NoError(t, err)
+	return validator
+}
+
+func TestRequestValidation(t *testing.T) {
+	validator := newTestValidator(t)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RequestValidation(validator)(next)
+
+	t.Run("valid request passes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/character", strings.NewReader(`{"userId":"7f9c4a52-7a4e-4d39-9d43-4a6f1f0d1c11","nickname":"Arthas","class":"WARRIOR"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("missing fields are reported", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/character", strings.NewReader(`{"nickname":"Arthas"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+		var body problem.Details
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.Equal(t, problem.CodeInvalidPayload, body.Code)
+
+		fields := make([]string, 0, len(body.Errors))
+		for _, fe := range body.Errors {
+			fields = append(fields, fe.Field)
+		}
+		assert.Contains(t, fields, IdempotencyKeyHeader)
+		assert.Contains(t, fields, "userId")
+		assert.Contains(t, fields, "class")
+	})
+}
@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// Spec is the OpenAPI document describing the routes served under /character/v1.
+//
+//go:embed openapi.json
+var Spec []byte
+
+var ErrRouteNotDocumented = errors.New("route is not described in the openapi document")
+
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load openapi document: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// Validator checks incoming requests against the OpenAPI document. Requests
+// reach it with the /character/v1 prefix already stripped, so routes are
+// matched against the bare paths of the document.
+type Validator struct {
+	router routers.Router
+}
+
+func NewValidator(doc *openapi3.T) (*Validator, error) {
+	stripped := *doc
+	stripped.Servers = nil
+
+	router, err := legacy.NewRouter(&stripped)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build openapi router: %w", err)
+	}
+
+	return &Validator{router: router}, nil
+}
+
+// Validate returns every violation found in r. Authentication is left to the
+// authentication middleware and is not checked here.
+func (v *Validator) Validate(r *http.Request) error {
+	route, pathParams, err := v.router.FindRoute(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRouteNotDocumented, err)
+	}
+
+	return openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	})
+}
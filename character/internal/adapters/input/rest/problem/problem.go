@@ -0,0 +1,62 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType is the media type defined by RFC 7807 for problem details.
+const ContentType = "application/problem+json"
+
+const (
+	CodeInternal         = "INTERNAL_ERROR"
+	CodeMalformedJSON    = "MALFORMED_JSON"
+	CodeInvalidPayload   = "INVALID_PAYLOAD"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeNotFound         = "NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+)
+
+// Details is the RFC 7807 problem document. Code is an extension member that
+// carries a stable, machine-readable identifier clients can switch on, and
+// Errors lists the individual fields that failed validation.
+type Details struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string         `json:"field"`
+	Rule    string         `json:"rule"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+func New(status int, code string, detail string) Details {
+	return Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func Write(w http.ResponseWriter, r *http.Request, p Details) {
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -1,51 +1,156 @@
 package rest
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/middleware"
-	"github.com/vterry/ddd-study/character/internal/adapters/input/token"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/openapi"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/problem"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/transfer"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/token"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
 	"github.com/vterry/ddd-study/character/internal/utils"
 )
 
+// Router is the subset of *http.ServeMux used to register the routes.
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// Access lists the token subjects granted more than playing their own
+// characters.
+type Access struct {
+	// PaymentSubjects may credit purchased cash.
+	PaymentSubjects []string
+	// DebitSubjects may spend the cash of any wallet.
+	DebitSubjects []string
+	// AdminSubjects see the private fields of every account, such as the
+	// login ids of the characters.
+	AdminSubjects []string
+}
+
 type Handler struct {
-	svc          CharacterService
-	tokenAdapter token.TokenValidationAdapter
+	svc              CharacterService
+	tokenAdapter     token.AuthService
+	idempotencyStore repository.IdempotencyRepository
+	idempotencyTTL   time.Duration
+	clock            clock.Clock
+	validator        *openapi.Validator
+	access           Access
+	rateLimitStore   repository.RateLimitRepository
+	rateLimits       middleware.RateLimits
 }
 
-func NewHandler(svc CharacterService, tokenAdapter token.TokenValidationAdapter) *Handler {
+// NewHandler builds the REST handler. access grants the payment, debit and
+// admin privileges to token subjects.
+func NewHandler(svc CharacterService, tokenAdapter token.AuthService, idempotencyStore repository.IdempotencyRepository, idempotencyTTL time.Duration, clock clock.Clock, validator *openapi.Validator, access Access, rateLimitStore repository.RateLimitRepository, rateLimits middleware.RateLimits) *Handler {
 	return &Handler{
-		svc:          svc,
-		tokenAdapter: tokenAdapter,
+		svc:              svc,
+		tokenAdapter:     tokenAdapter,
+		idempotencyStore: idempotencyStore,
+		idempotencyTTL:   idempotencyTTL,
+		clock:            clock,
+		validator:        validator,
+		access:           access,
+		rateLimitStore:   rateLimitStore,
+		rateLimits:       rateLimits,
 	}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.Handle("POST /character", middleware.Chain(
-		http.HandlerFunc(h.handleCreateLogin),
+// RegisterRoutes registers every route of the API. Each route must also be
+// described in openapi/openapi.json; the contract test fails otherwise.
+func (h *Handler) RegisterRoutes(mux Router) {
+	mux.Handle("GET /openapi.json", middleware.Chain(
+		http.HandlerFunc(h.handleOpenAPI),
 		middleware.LoggingMiddleware,
-		middleware.Auhtentication(h.tokenAdapter),
+		h.rateLimit(),
 	))
+	mux.Handle("POST /character", h.mutating(http.HandlerFunc(h.handleCreateLogin)))
+	mux.Handle("POST /character/import", h.mutating(h.administering(http.HandlerFunc(h.handleImportCharacter))))
+	mux.Handle("GET /character/search", h.reading(http.HandlerFunc(h.handleSearchCharacters)))
+	mux.Handle("GET /character/{characterId}", h.reading(http.HandlerFunc(h.handleGetCharacter)))
+	mux.Handle("GET /character/{characterId}/inventory", h.reading(http.HandlerFunc(h.handleGetInventory)))
+	mux.Handle("GET /character/{characterId}/export", h.reading(h.administering(http.HandlerFunc(h.handleExportCharacter))))
+	mux.Handle("POST /character/{characterId}/suspension", h.mutating(h.administering(http.HandlerFunc(h.handleSuspendCharacter))))
+	mux.Handle("POST /character/{characterId}/suspension/lift", h.mutating(h.administering(http.HandlerFunc(h.handleLiftSuspension))))
+	mux.Handle("POST /character/{characterId}/ban", h.mutating(h.administering(http.HandlerFunc(h.handleBanCharacter))))
+	mux.Handle("GET /character/{characterId}/inventory/history", h.reading(h.administering(http.HandlerFunc(h.handleGetInventoryHistory))))
+	mux.Handle("GET /classes", h.reading(http.HandlerFunc(h.handleListClasses)))
+	mux.Handle("PUT /classes/{classId}", h.mutating(h.administering(http.HandlerFunc(h.handleDefineClass))))
+	mux.Handle("POST /ledger/transactions", h.mutating(h.administering(http.HandlerFunc(h.handlePostLedgerTransaction))))
+	mux.Handle("GET /ledger/accounts/{account}/balance", h.reading(h.administering(http.HandlerFunc(h.handleGetAccountBalance))))
+	mux.Handle("GET /wallet/{loginId}", h.reading(http.HandlerFunc(h.handleGetWallet)))
+	mux.Handle("POST /wallet/{loginId}/purchases", h.mutating(middleware.Chain(
+		http.HandlerFunc(h.handleCreditPurchase),
+		middleware.RequireSubject(h.access.PaymentSubjects),
+	)))
+	mux.Handle("POST /wallet/{loginId}/debits", h.mutating(middleware.Chain(
+		http.HandlerFunc(h.handleDebitWallet),
+		middleware.RequireSubject(h.access.DebitSubjects),
+	)))
+}
+
+// mutating wraps routes that change state, requiring an Idempotency-Key so
+// that client retries never apply the same change twice.
+func (h *Handler) mutating(handler http.Handler) http.Handler {
+	return middleware.Chain(
+		handler,
+		middleware.LoggingMiddleware,
+		middleware.Auhtentication(h.tokenAdapter),
+		h.rateLimit(),
+		middleware.RequestValidation(h.validator),
+		middleware.Idempotency(h.idempotencyStore, h.idempotencyTTL, h.clock),
+	)
+}
+
+// administering wraps the routes only admins may call.
+func (h *Handler) administering(handler http.Handler) http.Handler {
+	return middleware.Chain(handler, middleware.RequireSubject(h.access.AdminSubjects))
+}
 
+// reading wraps routes that only read state; retrying them is always safe.
+func (h *Handler) reading(handler http.Handler) http.Handler {
+	return middleware.Chain(
+		handler,
+		middleware.LoggingMiddleware,
+		middleware.Auhtentication(h.tokenAdapter),
+		h.rateLimit(),
+		middleware.RequestValidation(h.validator),
+	)
+}
+
+// rateLimit runs after authentication, so that callers are limited by their
+// token subject rather than their address.
+func (h *Handler) rateLimit() middleware.Middleware {
+	return middleware.RateLimit(h.rateLimitStore, h.rateLimits, h.clock)
+}
+
+func (h *Handler) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openapi.Spec)
 }
 
 func (h *Handler) handleCreateLogin(w http.ResponseWriter, r *http.Request) {
 	var payload CreateCharacterRequest
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
 		return
 	}
 
 	if err := h.svc.NewCharacter(r.Context(), payload.UserID, payload.Nickname, payload.Class); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("invalid payload %v", err))
+		writeError(w, r, err)
 		return
 	}
 
@@ -54,3 +159,294 @@ func (h *Handler) handleCreateLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+func (h *Handler) handleGetCharacter(w http.ResponseWriter, r *http.Request) {
+	view, err := h.svc.GetCharacter(r.Context(), r.PathValue("characterId"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, characterSummaryFromView(view, h.isAdmin(r))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleGetInventory(w http.ResponseWriter, r *http.Request) {
+	view, err := h.svc.GetCharacter(r.Context(), r.PathValue("characterId"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, characterInventoryFromView(view)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleSearchCharacters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := CharacterSearchRequest{
+		Nickname: query.Get("nickname"),
+		Class:    query.Get("class"),
+		GuildID:  query.Get("guildId"),
+		MinLevel: query.Get("minLevel"),
+		MaxLevel: query.Get("maxLevel"),
+		Sort:     query.Get("sort"),
+		Order:    query.Get("order"),
+		Limit:    query.Get("limit"),
+		Cursor:   query.Get("cursor"),
+	}
+
+	views, nextCursor, err := h.svc.SearchCharacters(r.Context(), request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, characterSearchFromViews(views, nextCursor, h.isAdmin(r))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// isAdmin tells whether the request was authenticated as an admin.
+func (h *Handler) isAdmin(r *http.Request) bool {
+	subject, ok := middleware.SubjectFromContext(r.Context())
+	return ok && slices.Contains(h.access.AdminSubjects, subject)
+}
+
+func (h *Handler) handleGetInventoryHistory(w http.ResponseWriter, r *http.Request) {
+	characterId, query := r.PathValue("characterId"), r.URL.Query()
+	history, err := h.svc.InventoryHistory(r.Context(), characterId, query.Get("from"), query.Get("to"), query.Get("itemId"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, inventoryHistoryFromEvents(characterId, history)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handlePostLedgerTransaction(w http.ResponseWriter, r *http.Request) {
+	var payload PostLedgerTransactionRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	tx, err := h.svc.PostLedgerTransaction(r.Context(), payload, h.clock.Now())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, ledgerTransactionFromDomain(tx)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleGetAccountBalance(w http.ResponseWriter, r *http.Request) {
+	account, balance, err := h.svc.AccountBalance(r.Context(), r.PathValue("account"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, AccountBalanceResponse{Account: account.String(), Balance: balance}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleGetWallet(w http.ResponseWriter, r *http.Request) {
+	loginId := r.PathValue("loginId")
+	// the payment and shop services read the balance they credit or spend
+	subject, _ := middleware.SubjectFromContext(r.Context())
+	if subject != loginId && !h.isAdmin(r) && !slices.Contains(h.access.PaymentSubjects, subject) && !slices.Contains(h.access.DebitSubjects, subject) {
+		problem.Write(w, r, problem.New(http.StatusForbidden, problem.CodeForbidden, "only the login, admins and the wallet services may see its wallet"))
+		return
+	}
+
+	wallet, err := h.svc.GetWallet(r.Context(), loginId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, walletFromDomain(wallet)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleCreditPurchase(w http.ResponseWriter, r *http.Request) {
+	var payload CreditPurchaseRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	wallet, err := h.svc.CreditPurchase(r.Context(), r.PathValue("loginId"), payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, walletFromDomain(wallet)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleDebitWallet(w http.ResponseWriter, r *http.Request) {
+	var payload DebitWalletRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	wallet, err := h.svc.DebitWallet(r.Context(), r.PathValue("loginId"), payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, walletFromDomain(wallet)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleSuspendCharacter(w http.ResponseWriter, r *http.Request) {
+	var payload SuspendCharacterRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	characterId := r.PathValue("characterId")
+	standing, err := h.svc.SuspendCharacter(r.Context(), characterId, payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, characterStandingFromDomain(characterId, standing)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleLiftSuspension(w http.ResponseWriter, r *http.Request) {
+	h.changeStanding(w, r, h.svc.LiftSuspension)
+}
+
+func (h *Handler) handleBanCharacter(w http.ResponseWriter, r *http.Request) {
+	h.changeStanding(w, r, h.svc.BanCharacter)
+}
+
+// changeStanding serves the moderation actions that only take a reason.
+func (h *Handler) changeStanding(w http.ResponseWriter, r *http.Request, change func(context.Context, string, StandingChangeRequest) (character.Standing, error)) {
+	var payload StandingChangeRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	characterId := r.PathValue("characterId")
+	standing, err := change(r.Context(), characterId, payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, characterStandingFromDomain(characterId, standing)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleExportCharacter(w http.ResponseWriter, r *http.Request) {
+	exported, err := h.svc.ExportCharacter(r.Context(), r.PathValue("characterId"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, transfer.Export(exported, h.clock.Now())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleImportCharacter(w http.ResponseWriter, r *http.Request) {
+	var payload ImportCharacterRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	result, err := h.svc.ImportCharacter(r.Context(), payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusCreated, result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleListClasses(w http.ResponseWriter, r *http.Request) {
+	definitions, err := h.svc.ListClasses(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, classListFromDomain(definitions)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) handleDefineClass(w http.ResponseWriter, r *http.Request) {
+	var payload DefineClassRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeMalformedJSON, err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		problem.Write(w, r, validationProblem(err.(validator.ValidationErrors)))
+		return
+	}
+
+	definition, err := h.svc.DefineClass(r.Context(), r.PathValue("classId"), payload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := utils.WriteJSON(w, http.StatusOK, classFromDomain(definition)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -2,60 +2,401 @@ package rest
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/transfer"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
 	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
-	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
 )
 
 var (
-	ErrCannotCreateCharacter = errors.New("cannot create a character")
-	ErrInvalidLoginInfo      = errors.New("invalid login info")
+	ErrMalformedLoginID     = errors.New("login id must be a valid uuid")
+	ErrMalformedCharacterID = errors.New("character id must be a valid uuid")
+	ErrInvalidHistoryFilter = errors.New("invalid inventory history filter")
+	ErrInvalidSearch        = errors.New("invalid character search")
 )
 
 type CharacterService struct {
 	charaterService service.CharacterService
-	loginGateway    gateway.Login
+	queries         service.CharacterQueries
+	ledger          service.Ledger
+	wallets         service.Wallets
+	moderation      service.Moderation
+	transfers       service.CharacterTransfer
+	classes         service.Classes
 }
 
-func NewCharacterService(characterHandler service.CharacterService, loginGateway gateway.Login) *CharacterService {
+func NewCharacterService(characterHandler service.CharacterService, queries service.CharacterQueries, ledger service.Ledger, wallets service.Wallets, moderation service.Moderation, transfers service.CharacterTransfer, classes service.Classes) *CharacterService {
 	return &CharacterService{
 		charaterService: characterHandler,
-		loginGateway:    loginGateway,
+		queries:         queries,
+		ledger:          ledger,
+		wallets:         wallets,
+		moderation:      moderation,
+		transfers:       transfers,
+		classes:         classes,
 	}
 }
 
 func (h *CharacterService) NewCharacter(ctx context.Context, loginId string, nickname string, characterClass string) error {
-
 	parsedId, err := uuid.Parse(loginId)
+	if err != nil {
+		return fmt.Errorf("%w: %w: %v", service.ErrCannotCreateCharacter, ErrMalformedLoginID, err)
+	}
+
+	return h.charaterService.CreateCharacter(ctx, login.NewLoginID(parsedId), nickname, class.FromID(characterClass))
+}
 
+// GetCharacter reads the character from the read model, so it may lag
+// behind a change that was just accepted.
+func (h *CharacterService) GetCharacter(ctx context.Context, characterId string) (*repository.CharacterView, error) {
+	parsedId, err := uuid.Parse(characterId)
 	if err != nil {
-		return fmt.Errorf("%v: %w", ErrCannotCreateCharacter, err)
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCharacterID, err)
 	}
 
-	loginID := login.NewLoginID(parsedId)
-	ok, err := h.loginGateway.IsLoginValid(ctx, loginID)
+	return h.queries.GetCharacter(ctx, character.NewCharacterID(parsedId))
+}
 
+// InventoryHistory reads the inventory events of the character. from and to
+// are RFC 3339 instants and itemId a player item or item id; empty values do
+// not filter.
+func (h *CharacterService) InventoryHistory(ctx context.Context, characterId, from, to, itemId string) ([]repository.RecordedEvent, error) {
+	parsedId, err := uuid.Parse(characterId)
 	if err != nil {
-		return fmt.Errorf("%v: %w", ErrCannotCreateCharacter, err)
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCharacterID, err)
 	}
 
-	if !ok {
-		return fmt.Errorf("%v: %w", ErrCannotCreateCharacter, ErrInvalidLoginInfo)
+	var filter repository.InventoryHistoryFilter
+	if from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, fmt.Errorf("%w: from: %v", ErrInvalidHistoryFilter, err)
+		}
+	}
+	if to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, fmt.Errorf("%w: to: %v", ErrInvalidHistoryFilter, err)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidHistoryFilter)
 	}
+	if itemId != "" {
+		if filter.ItemID, err = uuid.Parse(itemId); err != nil {
+			return nil, fmt.Errorf("%w: itemId: %v", ErrInvalidHistoryFilter, err)
+		}
+	}
+
+	return h.queries.InventoryHistory(ctx, character.NewCharacterID(parsedId), filter)
+}
+
+// defaultSearchLimit is the page size of a search that does not ask for one.
+const defaultSearchLimit = 20
 
-	classValue, err := class.ParseClass(characterClass)
+// searchCursor is where the next page of a search starts. It carries the
+// order it was issued for, so it cannot be replayed against another one.
+type searchCursor struct {
+	Sort        repository.CharacterSort `json:"s"`
+	Descending  bool                     `json:"d,omitempty"`
+	Nickname    string                   `json:"n,omitempty"`
+	Gold        int                      `json:"g,omitempty"`
+	CharacterID uuid.UUID                `json:"id"`
+}
+
+// SearchCharacters reads one page of the characters matching request from
+// the read model. It returns the cursor of the next page, empty on the last
+// one.
+func (h *CharacterService) SearchCharacters(ctx context.Context, request CharacterSearchRequest) ([]repository.CharacterView, string, error) {
+	search := repository.CharacterSearch{
+		NicknamePrefix: character.NormalizeNickname(request.Nickname),
+		Sort:           repository.SortByNickname,
+		Limit:          defaultSearchLimit,
+	}
+
+	if request.Class != "" {
+		classValue, err := class.ParseClass(h.classes, request.Class)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+		}
+		search.Class = classValue.String()
+	}
+	if request.GuildID != "" {
+		guildId, err := uuid.Parse(request.GuildID)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: guildId: %v", ErrInvalidSearch, err)
+		}
+		search.GuildID = guildId
+	}
+	if request.MinLevel != "" {
+		level, err := parseLevel("minLevel", request.MinLevel)
+		if err != nil {
+			return nil, "", err
+		}
+		search.MinLevel = level
+	}
+	if request.MaxLevel != "" {
+		level, err := parseLevel("maxLevel", request.MaxLevel)
+		if err != nil {
+			return nil, "", err
+		}
+		search.MaxLevel = level
+	}
+	if search.MaxLevel != 0 && search.MinLevel > search.MaxLevel {
+		return nil, "", fmt.Errorf("%w: minLevel %d is above maxLevel %d", ErrInvalidSearch, search.MinLevel, search.MaxLevel)
+	}
+	switch repository.CharacterSort(request.Sort) {
+	case "", repository.SortByNickname:
+	case repository.SortByGold:
+		search.Sort = repository.SortByGold
+	default:
+		return nil, "", fmt.Errorf("%w: unknown sort %q", ErrInvalidSearch, request.Sort)
+	}
+	switch request.Order {
+	case "", "asc":
+	case "desc":
+		search.Descending = true
+	default:
+		return nil, "", fmt.Errorf("%w: unknown order %q", ErrInvalidSearch, request.Order)
+	}
+	if request.Limit != "" {
+		limit, err := strconv.Atoi(request.Limit)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: limit: %v", ErrInvalidSearch, err)
+		}
+		search.Limit = limit
+	}
+	if request.Cursor != "" {
+		after, err := decodeSearchCursor(request.Cursor, search)
+		if err != nil {
+			return nil, "", err
+		}
+		search.After = after
+	}
+
+	page, err := h.queries.SearchCharacters(ctx, search)
 	if err != nil {
-		return fmt.Errorf("%v: %w", ErrCannotCreateCharacter, err)
+		return nil, "", err
+	}
+	if page.Next == nil {
+		return page.Characters, "", nil
 	}
 
-	if err := h.charaterService.CreateCharacter(ctx, loginID, nickname, classValue); err != nil {
-		return fmt.Errorf("%v: %w", ErrCannotCreateCharacter, err)
+	cursor, err := encodeSearchCursor(search, *page.Next)
+	if err != nil {
+		return nil, "", err
 	}
+	return page.Characters, cursor, nil
+}
 
-	return nil
+func parseLevel(name, value string) (int, error) {
+	level, err := strconv.Atoi(value)
+	if err != nil || level < character.StartingLevel {
+		return 0, fmt.Errorf("%w: %s must be a level, got %q", ErrInvalidSearch, name, value)
+	}
+	return level, nil
+}
+
+func encodeSearchCursor(search repository.CharacterSearch, key repository.CharacterSearchKey) (string, error) {
+	raw, err := json.Marshal(searchCursor{
+		Sort:        search.Sort,
+		Descending:  search.Descending,
+		Nickname:    key.Nickname,
+		Gold:        key.Gold,
+		CharacterID: key.CharacterID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeSearchCursor(cursor string, search repository.CharacterSearch) (*repository.CharacterSearchKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidSearch)
+	}
+
+	var decoded searchCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidSearch)
+	}
+	if decoded.Sort != search.Sort || decoded.Descending != search.Descending {
+		return nil, fmt.Errorf("%w: cursor was issued for another order", ErrInvalidSearch)
+	}
+
+	return &repository.CharacterSearchKey{
+		Nickname:    decoded.Nickname,
+		Gold:        decoded.Gold,
+		CharacterID: decoded.CharacterID,
+	}, nil
+}
+
+// PostLedgerTransaction records the gold movement described by request, as
+// of occurredAt.
+func (h *CharacterService) PostLedgerTransaction(ctx context.Context, request PostLedgerTransactionRequest, occurredAt time.Time) (*ledger.Transaction, error) {
+	entries := make([]ledger.Entry, 0, len(request.Entries))
+	for _, entry := range request.Entries {
+		account, err := ledger.ParseAccountID(entry.Account)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, ledger.Entry{Account: account, Amount: entry.Amount})
+	}
+
+	tx, err := ledger.NewTransaction(request.TransactionID, request.Reason, occurredAt, entries...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ledger.Post(ctx, *tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// AccountBalance reads the ledger balance of account, given as kind:owner.
+func (h *CharacterService) AccountBalance(ctx context.Context, account string) (ledger.AccountID, int, error) {
+	accountId, err := ledger.ParseAccountID(account)
+	if err != nil {
+		return ledger.AccountID{}, 0, err
+	}
+
+	balance, err := h.ledger.Balance(ctx, accountId)
+	if err != nil {
+		return ledger.AccountID{}, 0, err
+	}
+	return accountId, balance, nil
+}
+
+func (h *CharacterService) GetWallet(ctx context.Context, loginId string) (*wallet.Wallet, error) {
+	parsedId, err := parseLoginID(loginId)
+	if err != nil {
+		return nil, err
+	}
+	return h.wallets.GetWallet(ctx, parsedId)
+}
+
+// CreditPurchase credits the cash of a purchase confirmed by the payment
+// provider.
+func (h *CharacterService) CreditPurchase(ctx context.Context, loginId string, request CreditPurchaseRequest) (*wallet.Wallet, error) {
+	parsedId, err := parseLoginID(loginId)
+	if err != nil {
+		return nil, err
+	}
+	return h.wallets.CreditPurchase(ctx, parsedId, request.PurchaseID, request.Amount)
+}
+
+func (h *CharacterService) DebitWallet(ctx context.Context, loginId string, request DebitWalletRequest) (*wallet.Wallet, error) {
+	parsedId, err := parseLoginID(loginId)
+	if err != nil {
+		return nil, err
+	}
+	return h.wallets.Debit(ctx, parsedId, request.Reference, request.Amount, request.Reason)
+}
+
+func (h *CharacterService) SuspendCharacter(ctx context.Context, characterId string, request SuspendCharacterRequest) (character.Standing, error) {
+	parsedId, err := parseCharacterID(characterId)
+	if err != nil {
+		return character.Standing{}, err
+	}
+	return h.moderation.Suspend(ctx, parsedId, request.Until, request.Reason)
+}
+
+func (h *CharacterService) LiftSuspension(ctx context.Context, characterId string, request StandingChangeRequest) (character.Standing, error) {
+	parsedId, err := parseCharacterID(characterId)
+	if err != nil {
+		return character.Standing{}, err
+	}
+	return h.moderation.LiftSuspension(ctx, parsedId, request.Reason)
+}
+
+func (h *CharacterService) BanCharacter(ctx context.Context, characterId string, request StandingChangeRequest) (character.Standing, error) {
+	parsedId, err := parseCharacterID(characterId)
+	if err != nil {
+		return character.Standing{}, err
+	}
+	return h.moderation.Ban(ctx, parsedId, request.Reason)
+}
+
+func (h *CharacterService) ExportCharacter(ctx context.Context, characterId string) (*character.Character, error) {
+	parsedId, err := parseCharacterID(characterId)
+	if err != nil {
+		return nil, err
+	}
+	return h.transfers.Export(ctx, parsedId)
+}
+
+// ImportCharacter imports the document with the remapped ids.
+func (h *CharacterService) ImportCharacter(ctx context.Context, request ImportCharacterRequest) (transfer.Result, error) {
+	remap, err := transfer.ParseRemap(request.Remap)
+	if err != nil {
+		return transfer.Result{}, err
+	}
+
+	imported, err := request.Document.Import(remap)
+	if err != nil {
+		return transfer.Result{}, err
+	}
+
+	created, err := h.transfers.Import(ctx, imported)
+	if err != nil {
+		return transfer.Result{}, err
+	}
+	return transfer.NewResult(request.Document, created), nil
+}
+
+func (h *CharacterService) ListClasses(ctx context.Context) ([]class.Definition, error) {
+	return h.classes.ListClasses(ctx)
+}
+
+func (h *CharacterService) DefineClass(ctx context.Context, classId string, request DefineClassRequest) (class.Definition, error) {
+	startingItems := make([]class.StartingItem, 0, len(request.StartingItems))
+	for i, startingItem := range request.StartingItems {
+		itemId, err := uuid.Parse(startingItem.ItemID)
+		if err != nil {
+			return class.Definition{}, fmt.Errorf("%w: item %d: %v", class.ErrInvalidStartingItem, i, err)
+		}
+		startingItems = append(startingItems, class.StartingItem{
+			Item:        item.NewItemID(itemId),
+			Description: startingItem.Description,
+			Quantity:    startingItem.Quantity,
+		})
+	}
+
+	definition, err := class.NewDefinition(class.FromID(classId).String(), request.DisplayName, startingItems, request.Stats)
+	if err != nil {
+		return class.Definition{}, err
+	}
+	if err := h.classes.DefineClass(ctx, definition); err != nil {
+		return class.Definition{}, err
+	}
+	return definition, nil
+}
+
+func parseCharacterID(characterId string) (character.CharacterID, error) {
+	parsedId, err := uuid.Parse(characterId)
+	if err != nil {
+		return character.CharacterID{}, fmt.Errorf("%w: %v", ErrMalformedCharacterID, err)
+	}
+	return character.NewCharacterID(parsedId), nil
+}
+
+func parseLoginID(loginId string) (login.LoginID, error) {
+	parsedId, err := uuid.Parse(loginId)
+	if err != nil {
+		return login.LoginID{}, fmt.Errorf("%w: %v", ErrMalformedLoginID, err)
+	}
+	return login.NewLoginID(parsedId), nil
 }
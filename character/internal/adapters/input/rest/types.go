@@ -1,7 +1,354 @@
 package rest
 
+import (
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/input/transfer"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
 type CreateCharacterRequest struct {
 	UserID   string `json:"userId" validate:"required"`
 	Nickname string `json:"nickname" validate:"required"`
 	Class    string `json:"class" validate:"required"`
 }
+
+type CharacterSummaryResponse struct {
+	ID string `json:"id"`
+	// LoginID is only shown to admins.
+	LoginID   string `json:"loginId,omitempty"`
+	Nickname  string `json:"nickname"`
+	Class     string `json:"class"`
+	GuildID   string `json:"guildId"`
+	VaultID   string `json:"vaultId"`
+	Level     int    `json:"level"`
+	Gold      int    `json:"gold"`
+	ItemCount int    `json:"itemCount"`
+}
+
+type InventoryItemResponse struct {
+	PlayerItemID string `json:"playerItemId"`
+	ItemID       string `json:"itemId"`
+	Description  string `json:"description"`
+	Quantity     int    `json:"quantity"`
+}
+
+type CharacterInventoryResponse struct {
+	CharacterID string                  `json:"characterId"`
+	InventoryID string                  `json:"inventoryId"`
+	Gold        int                     `json:"gold"`
+	Items       []InventoryItemResponse `json:"items"`
+}
+
+func characterSummaryFromView(view *repository.CharacterView, admin bool) CharacterSummaryResponse {
+	summary := CharacterSummaryResponse{
+		ID:        view.CharacterID.String(),
+		Nickname:  view.Nickname,
+		Class:     view.Class,
+		GuildID:   view.GuildID.String(),
+		VaultID:   view.VaultID.String(),
+		Level:     view.Level,
+		Gold:      view.Gold,
+		ItemCount: len(view.Items),
+	}
+	if admin {
+		summary.LoginID = view.LoginID.String()
+	}
+	return summary
+}
+
+// CharacterSearchRequest holds the query parameters of a character search,
+// as sent by the client.
+type CharacterSearchRequest struct {
+	Nickname string
+	Class    string
+	GuildID  string
+	MinLevel string
+	MaxLevel string
+	Sort     string
+	Order    string
+	Limit    string
+	Cursor   string
+}
+
+type CharacterSearchResult struct {
+	ID string `json:"id"`
+	// LoginID is only shown to admins.
+	LoginID  string `json:"loginId,omitempty"`
+	Nickname string `json:"nickname"`
+	Class    string `json:"class"`
+	GuildID  string `json:"guildId"`
+	Level    int    `json:"level"`
+	Gold     int    `json:"gold"`
+}
+
+type CharacterSearchResponse struct {
+	Characters []CharacterSearchResult `json:"characters"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
+
+func characterSearchFromViews(views []repository.CharacterView, nextCursor string, admin bool) CharacterSearchResponse {
+	characters := make([]CharacterSearchResult, 0, len(views))
+	for _, view := range views {
+		result := CharacterSearchResult{
+			ID:       view.CharacterID.String(),
+			Nickname: view.Nickname,
+			Class:    view.Class,
+			GuildID:  view.GuildID.String(),
+			Level:    view.Level,
+			Gold:     view.Gold,
+		}
+		if admin {
+			result.LoginID = view.LoginID.String()
+		}
+		characters = append(characters, result)
+	}
+
+	return CharacterSearchResponse{
+		Characters: characters,
+		NextCursor: nextCursor,
+	}
+}
+
+func characterInventoryFromView(view *repository.CharacterView) CharacterInventoryResponse {
+	items := make([]InventoryItemResponse, 0, len(view.Items))
+	for _, item := range view.Items {
+		items = append(items, InventoryItemResponse{
+			PlayerItemID: item.PlayerItemID.String(),
+			ItemID:       item.ItemID.String(),
+			Description:  item.Description,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	return CharacterInventoryResponse{
+		CharacterID: view.CharacterID.String(),
+		InventoryID: view.InventoryID.String(),
+		Gold:        view.Gold,
+		Items:       items,
+	}
+}
+
+// InventoryHistoryEntry is one inventory event. Item fields are set for item
+// events and Amount for gold events, with VaultID for gold deposited.
+type InventoryHistoryEntry struct {
+	Position     int64     `json:"position"`
+	Type         string    `json:"type"`
+	OccurredAt   time.Time `json:"occurredAt"`
+	PlayerItemID string    `json:"playerItemId,omitempty"`
+	ItemID       string    `json:"itemId,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Quantity     int       `json:"quantity,omitempty"`
+	Amount       int       `json:"amount,omitempty"`
+	VaultID      string    `json:"vaultId,omitempty"`
+}
+
+func (e *InventoryHistoryEntry) setItem(item playeritem.PlayerItem) {
+	e.PlayerItemID = item.ID().String()
+	e.ItemID = item.ItemID().ID().String()
+	e.Description = item.Describe()
+	e.Quantity = item.GetCurrentQuantity()
+}
+
+type InventoryHistoryResponse struct {
+	CharacterID string                  `json:"characterId"`
+	Entries     []InventoryHistoryEntry `json:"entries"`
+}
+
+func inventoryHistoryFromEvents(characterId string, recorded []repository.RecordedEvent) InventoryHistoryResponse {
+	entries := make([]InventoryHistoryEntry, 0, len(recorded))
+	for _, r := range recorded {
+		entry := InventoryHistoryEntry{
+			Position:   r.Position,
+			Type:       r.Event.EventType(),
+			OccurredAt: r.OccurredAt.UTC(),
+		}
+		switch e := r.Event.(type) {
+		case character.ItemAdded:
+			entry.setItem(e.Item)
+		case character.ItemDropped:
+			entry.setItem(e.Item)
+		case character.GoldAdded:
+			entry.Amount = e.Amount
+		case character.GoldWithdrawn:
+			entry.Amount = e.Amount
+			if !e.Vault.Equals(vault.VaultID{}) {
+				entry.VaultID = e.Vault.ID().String()
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return InventoryHistoryResponse{
+		CharacterID: characterId,
+		Entries:     entries,
+	}
+}
+
+type DefineClassRequest struct {
+	DisplayName   string                `json:"displayName" validate:"required"`
+	StartingItems []StartingItemRequest `json:"startingItems"`
+	Stats         map[string]int        `json:"stats"`
+}
+
+type StartingItemRequest struct {
+	ItemID      string `json:"itemId"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
+type ClassResponse struct {
+	ID            string                 `json:"id"`
+	DisplayName   string                 `json:"displayName"`
+	StartingItems []StartingItemResponse `json:"startingItems"`
+	Stats         map[string]int         `json:"stats"`
+}
+
+type StartingItemResponse struct {
+	ItemID      string `json:"itemId"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
+type ClassListResponse struct {
+	Classes []ClassResponse `json:"classes"`
+}
+
+func classFromDomain(definition class.Definition) ClassResponse {
+	startingItems := make([]StartingItemResponse, 0, len(definition.StartingItems))
+	for _, startingItem := range definition.StartingItems {
+		startingItems = append(startingItems, StartingItemResponse{
+			ItemID:      startingItem.Item.ID().String(),
+			Description: startingItem.Description,
+			Quantity:    startingItem.Quantity,
+		})
+	}
+	stats := definition.Stats
+	if stats == nil {
+		stats = map[string]int{}
+	}
+	return ClassResponse{
+		ID:            definition.Class.String(),
+		DisplayName:   definition.DisplayName,
+		StartingItems: startingItems,
+		Stats:         stats,
+	}
+}
+
+func classListFromDomain(definitions []class.Definition) ClassListResponse {
+	classes := make([]ClassResponse, 0, len(definitions))
+	for _, definition := range definitions {
+		classes = append(classes, classFromDomain(definition))
+	}
+	return ClassListResponse{Classes: classes}
+}
+
+// ImportCharacterRequest carries an exported character and the ids of other
+// services it points to that must change in this environment, old id to
+// new id.
+type ImportCharacterRequest struct {
+	Document transfer.Document `json:"document"`
+	Remap    map[string]string `json:"remap"`
+}
+
+type SuspendCharacterRequest struct {
+	Until  time.Time `json:"until" validate:"required"`
+	Reason string    `json:"reason" validate:"required"`
+}
+
+// StandingChangeRequest carries the reason of a moderation action other
+// than a suspension.
+type StandingChangeRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type CharacterStandingResponse struct {
+	CharacterID    string     `json:"characterId"`
+	Status         string     `json:"status"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+}
+
+func characterStandingFromDomain(characterId string, standing character.Standing) CharacterStandingResponse {
+	response := CharacterStandingResponse{
+		CharacterID: characterId,
+		Status:      standing.Status.String(),
+		Reason:      standing.Reason,
+	}
+	if !standing.SuspendedUntil.IsZero() {
+		until := standing.SuspendedUntil.UTC()
+		response.SuspendedUntil = &until
+	}
+	return response
+}
+
+type LedgerEntryRequest struct {
+	Account string `json:"account" validate:"required"`
+	Amount  int    `json:"amount"`
+}
+
+type PostLedgerTransactionRequest struct {
+	TransactionID string               `json:"transactionId" validate:"required"`
+	Reason        string               `json:"reason" validate:"required"`
+	Entries       []LedgerEntryRequest `json:"entries" validate:"required,min=2,dive"`
+}
+
+type LedgerEntryResponse struct {
+	Account string `json:"account"`
+	Amount  int    `json:"amount"`
+}
+
+type LedgerTransactionResponse struct {
+	TransactionID string                `json:"transactionId"`
+	Reason        string                `json:"reason"`
+	OccurredAt    time.Time             `json:"occurredAt"`
+	Entries       []LedgerEntryResponse `json:"entries"`
+}
+
+func ledgerTransactionFromDomain(tx *ledger.Transaction) LedgerTransactionResponse {
+	entries := make([]LedgerEntryResponse, 0, len(tx.Entries()))
+	for _, entry := range tx.Entries() {
+		entries = append(entries, LedgerEntryResponse{Account: entry.Account.String(), Amount: entry.Amount})
+	}
+
+	return LedgerTransactionResponse{
+		TransactionID: tx.ID(),
+		Reason:        tx.Reason(),
+		OccurredAt:    tx.OccurredAt().UTC(),
+		Entries:       entries,
+	}
+}
+
+type AccountBalanceResponse struct {
+	Account string `json:"account"`
+	Balance int    `json:"balance"`
+}
+
+type CreditPurchaseRequest struct {
+	PurchaseID string `json:"purchaseId" validate:"required,max=255"`
+	Amount     int    `json:"amount"`
+}
+
+type DebitWalletRequest struct {
+	Reference string `json:"reference" validate:"required,max=255"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason" validate:"required,max=255"`
+}
+
+type WalletResponse struct {
+	LoginID string `json:"loginId"`
+	Balance int    `json:"balance"`
+}
+
+func walletFromDomain(w *wallet.Wallet) WalletResponse {
+	return WalletResponse{
+		LoginID: w.LoginID().ID().String(),
+		Balance: w.Balance(),
+	}
+}
@@ -19,23 +19,30 @@ func NewTokenValidator(keycloakClient *keycloak.KeycloakClient) *TokenValidation
 }
 
 func (t *TokenValidationAdapter) TokenValidation(ctx context.Context, token string) (bool, error) {
+	if _, err := t.Authenticate(ctx, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (t *TokenValidationAdapter) Authenticate(ctx context.Context, token string) (string, error) {
 	keySet := t.keycloakClient.Provider.VerifierContext(ctx, &oidc.Config{
 		SkipClientIDCheck: true,
 	})
 
 	if keySet == nil {
-		return false, fmt.Errorf("cannot verify provider access token")
+		return "", fmt.Errorf("cannot verify provider access token")
 	}
 
 	jwt, err := keySet.Verify(ctx, token)
 	if err != nil {
-		return false, fmt.Errorf("cannot verify access token: %w", err)
+		return "", fmt.Errorf("cannot verify access token: %w", err)
 	}
 
 	var claims TokenClaims
 
 	if err := jwt.Claims(&claims); err != nil {
-		return false, fmt.Errorf("cannot verify token claims: %w", err)
+		return "", fmt.Errorf("cannot verify token claims: %w", err)
 	}
-	return true, nil
+	return claims.Subject, nil
 }
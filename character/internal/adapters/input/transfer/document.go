@@ -0,0 +1,277 @@
+// Package transfer is the portable JSON format characters are exported to
+// and imported from. The REST API and the command line share it, so a
+// document exported by either can be imported by the other.
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+)
+
+const (
+	// Format tells character exports apart from other JSON documents.
+	Format = "character-export"
+	// Version is bumped whenever the document changes in a way older
+	// importers cannot read. Version 1 documents predate levels and are
+	// still read, their characters starting at the starting level.
+	Version = 2
+)
+
+var (
+	ErrInvalidDocument     = errors.New("invalid character export")
+	ErrUnsupportedDocument = errors.New("unsupported character export")
+)
+
+type Document struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Character  CharacterDocument `json:"character"`
+}
+
+// CharacterDocument is the exported character. GuildID is empty when the
+// character is not in a guild.
+type CharacterDocument struct {
+	ID        string            `json:"id"`
+	LoginID   string            `json:"loginId"`
+	Nickname  string            `json:"nickname"`
+	Class     string            `json:"class"`
+	Level     int               `json:"level"`
+	GuildID   string            `json:"guildId,omitempty"`
+	VaultID   string            `json:"vaultId"`
+	Standing  StandingDocument  `json:"standing"`
+	Inventory InventoryDocument `json:"inventory"`
+}
+
+type StandingDocument struct {
+	Status         string     `json:"status"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+}
+
+type InventoryDocument struct {
+	ID    string         `json:"id"`
+	Gold  int            `json:"gold"`
+	Items []ItemDocument `json:"items"`
+}
+
+type ItemDocument struct {
+	ID          string `json:"id"`
+	ItemID      string `json:"itemId"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
+// Export builds the document of a character.
+func Export(c *character.Character, exportedAt time.Time) Document {
+	inventory := c.Inventory()
+	items := make([]ItemDocument, 0, len(inventory.Items()))
+	for _, item := range inventory.Items() {
+		items = append(items, ItemDocument{
+			ID:          item.ID().String(),
+			ItemID:      item.ItemID().ID().String(),
+			Description: item.Describe(),
+			Quantity:    item.GetCurrentQuantity(),
+		})
+	}
+
+	standing := c.Standing()
+	standingDocument := StandingDocument{
+		Status: standing.Status.String(),
+		Reason: standing.Reason,
+	}
+	if !standing.SuspendedUntil.IsZero() {
+		until := standing.SuspendedUntil.UTC()
+		standingDocument.SuspendedUntil = &until
+	}
+
+	var guildId string
+	if c.GetCurrentGuild().ID() != uuid.Nil {
+		guildId = c.GetCurrentGuild().ID().String()
+	}
+
+	return Document{
+		Format:     Format,
+		Version:    Version,
+		ExportedAt: exportedAt.UTC(),
+		Character: CharacterDocument{
+			ID:       c.ID().String(),
+			LoginID:  c.LoginID().ID().String(),
+			Nickname: c.Nickname(),
+			Class:    c.Class().String(),
+			Level:    c.Level(),
+			GuildID:  guildId,
+			VaultID:  c.GetCurrentVaultId().ID().String(),
+			Standing: standingDocument,
+			Inventory: InventoryDocument{
+				ID:    inventory.ID().String(),
+				Gold:  inventory.GetCurrentGold(),
+				Items: items,
+			},
+		},
+	}
+}
+
+// Decode reads a document, rejecting fields it does not know so a document
+// written by a newer version is not silently truncated.
+func Decode(r io.Reader) (Document, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var document Document
+	if err := decoder.Decode(&document); err != nil {
+		return Document{}, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return document, nil
+}
+
+// Remap replaces the ids of other services the exported character points
+// to, such as its login or vault, with the ones they have in the
+// environment imported into. Ids it does not list are kept.
+type Remap map[uuid.UUID]uuid.UUID
+
+// ParseRemap reads a remap written as old id to new id.
+func ParseRemap(ids map[string]string) (Remap, error) {
+	remap := make(Remap, len(ids))
+	for _, from := range slices.Sorted(maps.Keys(ids)) {
+		fromId, err := uuid.Parse(from)
+		if err != nil {
+			return nil, fmt.Errorf("%w: remap %q: %v", ErrInvalidDocument, from, err)
+		}
+		toId, err := uuid.Parse(ids[from])
+		if err != nil {
+			return nil, fmt.Errorf("%w: remap %q: %v", ErrInvalidDocument, ids[from], err)
+		}
+		remap[fromId] = toId
+	}
+	return remap, nil
+}
+
+func (r Remap) id(value uuid.UUID) uuid.UUID {
+	if to, ok := r[value]; ok {
+		return to
+	}
+	return value
+}
+
+// Import checks the format and version of the document and turns it into
+// the state the character is imported with. The domain rules are left to
+// the import itself.
+func (d Document) Import(remap Remap) (service.CharacterImport, error) {
+	if d.Format != Format {
+		return service.CharacterImport{}, fmt.Errorf("%w: format %q", ErrUnsupportedDocument, d.Format)
+	}
+	if d.Version < 1 || d.Version > Version {
+		return service.CharacterImport{}, fmt.Errorf("%w: version %d", ErrUnsupportedDocument, d.Version)
+	}
+
+	exported := d.Character
+	loginId, err := parseID("loginId", exported.LoginID)
+	if err != nil {
+		return service.CharacterImport{}, err
+	}
+	vaultId, err := parseID("vaultId", exported.VaultID)
+	if err != nil {
+		return service.CharacterImport{}, err
+	}
+	guildId := uuid.Nil
+	if exported.GuildID != "" {
+		if guildId, err = parseID("guildId", exported.GuildID); err != nil {
+			return service.CharacterImport{}, err
+		}
+	}
+
+	// the import checks the class against the catalog of this environment
+	characterClass := class.FromID(exported.Class)
+	if characterClass == "" {
+		return service.CharacterImport{}, fmt.Errorf("%w: %w", ErrInvalidDocument, class.ErrInvalidClass)
+	}
+
+	level := exported.Level
+	if d.Version == 1 {
+		level = character.StartingLevel
+	}
+	if level < character.StartingLevel {
+		return service.CharacterImport{}, fmt.Errorf("%w: %w: %d", ErrInvalidDocument, character.ErrInvalidLevel, level)
+	}
+
+	status, err := character.ParseStatus(exported.Standing.Status)
+	if err != nil {
+		return service.CharacterImport{}, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	standing := character.Standing{Status: status, Reason: exported.Standing.Reason}
+	if exported.Standing.SuspendedUntil != nil {
+		standing.SuspendedUntil = *exported.Standing.SuspendedUntil
+	}
+
+	items := make([]service.ItemImport, 0, len(exported.Inventory.Items))
+	for i, exportedItem := range exported.Inventory.Items {
+		source, err := parseID(fmt.Sprintf("items[%d].id", i), exportedItem.ID)
+		if err != nil {
+			return service.CharacterImport{}, err
+		}
+		itemId, err := parseID(fmt.Sprintf("items[%d].itemId", i), exportedItem.ItemID)
+		if err != nil {
+			return service.CharacterImport{}, err
+		}
+		items = append(items, service.ItemImport{
+			Source:      playeritem.NewPlayerItemID(source),
+			Item:        item.NewItemID(remap.id(itemId)),
+			Description: exportedItem.Description,
+			Quantity:    exportedItem.Quantity,
+		})
+	}
+
+	return service.CharacterImport{
+		Login:    login.NewLoginID(remap.id(loginId)),
+		Nickname: exported.Nickname,
+		Class:    characterClass,
+		Level:    level,
+		Gold:     exported.Inventory.Gold,
+		Items:    items,
+		Guild:    guild.NewGuildID(remap.id(guildId)),
+		Vault:    vault.NewVaultID(remap.id(vaultId)),
+		Standing: standing,
+	}, nil
+}
+
+// Result reports the ids an import assigned, keyed by the ids of the
+// exported character.
+type Result struct {
+	CharacterID string            `json:"characterId"`
+	IDs         map[string]string `json:"ids"`
+}
+
+func NewResult(d Document, imported service.ImportedCharacter) Result {
+	ids := map[string]string{
+		d.Character.ID:           imported.Character.ID().String(),
+		d.Character.Inventory.ID: imported.Inventory.ID().String(),
+	}
+	for source, target := range imported.Items {
+		ids[source.ID().String()] = target.ID().String()
+	}
+	return Result{CharacterID: imported.Character.ID().String(), IDs: ids}
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, field, err)
+	}
+	return id, nil
+}
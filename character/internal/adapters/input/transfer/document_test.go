@@ -0,0 +1,116 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+)
+
+func newExportedCharacter(t *testing.T) *character.Character {
+	t.Helper()
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Thrall", login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	axe, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), "Axe", 1)
+	require.NoError(t, err)
+	require.NoError(t, c.PickItem(*axe))
+	require.NoError(t, c.PickGold(75))
+	require.NoError(t, c.ReachLevel(7))
+	require.NoError(t, c.UpdateGuildInfo(guild.NewGuildID(uuid.New())))
+	require.NoError(t, c.Suspend(time.Now().Add(time.Hour), "spam", time.Now()))
+	return c
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	c := newExportedCharacter(t)
+	document := Export(c, time.Now())
+
+	var buf bytes.Buffer
+	require.NoError(t, json.NewEncoder(&buf).Encode(document))
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+
+	newLogin := uuid.New()
+	imported, err := decoded.Import(Remap{c.LoginID().ID(): newLogin})
+	require.NoError(t, err)
+
+	assert.Equal(t, newLogin, imported.Login.ID(), "remapped")
+	assert.Equal(t, c.GetCurrentVaultId().ID(), imported.Vault.ID(), "kept")
+	assert.Equal(t, c.GetCurrentGuild().ID(), imported.Guild.ID())
+	assert.Equal(t, "Thrall", imported.Nickname)
+	assert.Equal(t, class.Warrior, imported.Class)
+	assert.Equal(t, 75, imported.Gold)
+	assert.Equal(t, 7, imported.Level)
+	assert.Equal(t, character.StatusSuspended, imported.Standing.Status)
+	assert.True(t, c.Standing().SuspendedUntil.Equal(imported.Standing.SuspendedUntil))
+
+	held := c.Inventory()
+	require.Len(t, imported.Items, 1)
+	axe := held.Items()[0]
+	assert.Equal(t, axe.PlayerItemID, imported.Items[0].Source)
+	assert.Equal(t, axe.ItemID().ID(), imported.Items[0].Item.ID())
+	assert.Equal(t, "Axe", imported.Items[0].Description)
+}
+
+func TestDocumentRejects(t *testing.T) {
+	valid, err := json.Marshal(Export(newExportedCharacter(t), time.Now()))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		document string
+		err      error
+	}{
+		{"not json", "{", ErrInvalidDocument},
+		{"unknown field", strings.Replace(string(valid), `"format"`, `"experience":3,"format"`, 1), ErrInvalidDocument},
+		{"other format", strings.Replace(string(valid), Format, "inventory-export", 1), ErrUnsupportedDocument},
+		{"newer version", strings.Replace(string(valid), `"version":2`, `"version":3`, 1), ErrUnsupportedDocument},
+		{"level below the starting one", strings.Replace(string(valid), `"level":7`, `"level":0`, 1), ErrInvalidDocument},
+		{"malformed id", strings.Replace(string(valid), `"vaultId":"`, `"vaultId":"x`, 1), ErrInvalidDocument},
+		{"missing class", strings.Replace(string(valid), `"WARRIOR"`, `""`, 1), ErrInvalidDocument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := Decode(strings.NewReader(tt.document))
+			if err == nil {
+				_, err = document.Import(nil)
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDocumentVersion1StartsAtTheStartingLevel(t *testing.T) {
+	valid, err := json.Marshal(Export(newExportedCharacter(t), time.Now()))
+	require.NoError(t, err)
+	older := strings.Replace(strings.Replace(string(valid), `"version":2`, `"version":1`, 1), `"level":7,`, "", 1)
+
+	document, err := Decode(strings.NewReader(older))
+	require.NoError(t, err)
+	imported, err := document.Import(nil)
+	require.NoError(t, err)
+	assert.Equal(t, character.StartingLevel, imported.Level)
+}
+
+func TestParseRemap(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+
+	remap, err := ParseRemap(map[string]string{from.String(): to.String()})
+	require.NoError(t, err)
+	assert.Equal(t, Remap{from: to}, remap)
+
+	_, err = ParseRemap(map[string]string{from.String(): "elsewhere"})
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
@@ -0,0 +1,60 @@
+package broker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+)
+
+const defaultTopicBuffer = 256
+
+// InMemoryBroker delivers messages through buffered channels, one per topic.
+// It is meant for tests and local runs; messages are lost on restart.
+type InMemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]chan broker.Message
+}
+
+func NewInMemoryBroker() *InMemoryBroker {
+	return &InMemoryBroker{
+		topics: make(map[string]chan broker.Message),
+	}
+}
+
+func (b *InMemoryBroker) Publish(ctx context.Context, topic string, msg broker.Message) error {
+	select {
+	case b.topic(topic) <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *InMemoryBroker) Subscribe(ctx context.Context, topic string, handler broker.Handler) error {
+	messages := b.topic(topic)
+	for {
+		select {
+		case msg := <-messages:
+			if err := handler(ctx, msg); err != nil && ctx.Err() == nil {
+				// back to the end of the topic; publishing from here would
+				// block on a full buffer only this loop drains
+				go func() { _ = b.Publish(ctx, topic, msg) }()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *InMemoryBroker) topic(name string) chan broker.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, ok := b.topics[name]
+	if !ok {
+		ch = make(chan broker.Message, defaultTopicBuffer)
+		b.topics[name] = ch
+	}
+	return ch
+}
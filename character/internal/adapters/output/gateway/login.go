@@ -11,25 +11,31 @@ import (
 
 	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway/dto"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 	"github.com/vterry/ddd-study/character/internal/infra/keycloak"
 )
 
 type LoginGateway struct {
 	keycloakClient *keycloak.KeycloakClient
+	logger         logger.Logger
 	Client         *http.Client
 }
 
-func NewLoginGateway(keycloakClient *keycloak.KeycloakClient) *LoginGateway {
+func NewLoginGateway(keycloakClient *keycloak.KeycloakClient, logger logger.Logger) *LoginGateway {
 	return &LoginGateway{
 		keycloakClient: keycloakClient,
+		logger:         logger,
 	}
 }
 
 func (l *LoginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID) (bool, error) {
+	log := l.logger.WithContext(ctx).With("loginId", loginId.ID().String())
+	log.Debug("Checking login against Keycloak")
 
 	// Get admin token for accessing the admin API
 	adminToken, err := l.getAdminToken(ctx)
 	if err != nil {
+		log.Error("Failed to get Keycloak admin token", "error", err)
 		return false, fmt.Errorf("failed to get admin token: %w", err)
 	}
 
@@ -45,6 +51,7 @@ func (l *LoginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID)
 
 	resp, err := l.Client.Do(req)
 	if err != nil {
+		log.Error("Failed to reach Keycloak", "error", err)
 		return false, fmt.Errorf("failed to get user info: %w", err)
 	}
 
@@ -52,6 +59,7 @@ func (l *LoginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		log.Warn("Keycloak rejected the user lookup", "status", resp.StatusCode)
 		return false, fmt.Errorf("failed to get user info: status code %d, body: %s", resp.StatusCode, string(body))
 	}
 
@@ -61,6 +69,7 @@ func (l *LoginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID)
 	}
 
 	if !user.Enabled {
+		log.Info("Login is disabled")
 		return false, nil
 	}
 
@@ -1,19 +1,25 @@
 package gateway
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 )
 
 // TODO - implement
 
 type MockVaultGateway struct {
+	logger logger.Logger
 }
 
-func NewMockVaultGateway() *MockVaultGateway {
-	return &MockVaultGateway{}
+func NewMockVaultGateway(logger logger.Logger) *MockVaultGateway {
+	return &MockVaultGateway{logger: logger}
 }
 
-func (v *MockVaultGateway) CreateVault() (vault.VaultID, error) {
-	return vault.NewVaultID(uuid.New()), nil
+func (v *MockVaultGateway) CreateVault(ctx context.Context) (vault.VaultID, error) {
+	vaultID := vault.NewVaultID(uuid.New())
+	v.logger.WithContext(ctx).Debug("Vault created", "vaultId", vaultID.ID().String())
+	return vaultID, nil
 }
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+)
+
+// Class is a row of the class catalog. StartingItems and Stats hold JSON.
+type Class struct {
+	ID            string
+	DisplayName   string
+	StartingItems []byte
+	Stats         []byte
+}
+
+type classStartingItem struct {
+	ItemID      string `json:"itemId"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
+func ClassToDAO(definition class.Definition) (Class, error) {
+	startingItems := make([]classStartingItem, 0, len(definition.StartingItems))
+	for _, startingItem := range definition.StartingItems {
+		startingItems = append(startingItems, classStartingItem{
+			ItemID:      startingItem.Item.ID().String(),
+			Description: startingItem.Description,
+			Quantity:    startingItem.Quantity,
+		})
+	}
+	encodedItems, err := json.Marshal(startingItems)
+	if err != nil {
+		return Class{}, err
+	}
+
+	stats := definition.Stats
+	if stats == nil {
+		stats = class.Stats{}
+	}
+	encodedStats, err := json.Marshal(stats)
+	if err != nil {
+		return Class{}, err
+	}
+
+	return Class{
+		ID:            definition.Class.String(),
+		DisplayName:   definition.DisplayName,
+		StartingItems: encodedItems,
+		Stats:         encodedStats,
+	}, nil
+}
+
+// DAOToClass rebuilds a stored class, validating it like a new one.
+func DAOToClass(dao Class) (class.Definition, error) {
+	var stored []classStartingItem
+	if err := json.Unmarshal(dao.StartingItems, &stored); err != nil {
+		return class.Definition{}, fmt.Errorf("%w: class %s: %w", ErrCorruptedRow, dao.ID, err)
+	}
+	startingItems := make([]class.StartingItem, 0, len(stored))
+	for _, startingItem := range stored {
+		itemId, err := uuid.Parse(startingItem.ItemID)
+		if err != nil {
+			return class.Definition{}, fmt.Errorf("%w: class %s: %w", ErrCorruptedRow, dao.ID, err)
+		}
+		startingItems = append(startingItems, class.StartingItem{
+			Item:        item.NewItemID(itemId),
+			Description: startingItem.Description,
+			Quantity:    startingItem.Quantity,
+		})
+	}
+
+	var stats class.Stats
+	if err := json.Unmarshal(dao.Stats, &stats); err != nil {
+		return class.Definition{}, fmt.Errorf("%w: class %s: %w", ErrCorruptedRow, dao.ID, err)
+	}
+
+	definition, err := class.NewDefinition(dao.ID, dao.DisplayName, startingItems, stats)
+	if err != nil {
+		return class.Definition{}, fmt.Errorf("%w: class %s: %w", ErrCorruptedRow, dao.ID, err)
+	}
+	return definition, nil
+}
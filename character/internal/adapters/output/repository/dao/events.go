@@ -0,0 +1,261 @@
+package dao
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+var ErrUnknownEvent = errors.New("unknown character event")
+
+// Event is a row of the character event log. Payload holds the JSON encoding
+// of the event fields, keyed as in the payload types below.
+type Event struct {
+	Position    int64
+	CharacterID string
+	EventType   string
+	Payload     []byte
+	OccurredAt  time.Time
+}
+
+// characterCreatedPayload has no level in the rows written before characters
+// had one, which decode at character.StartingLevel.
+type characterCreatedPayload struct {
+	LoginID     string `json:"loginId"`
+	Nickname    string `json:"nickname"`
+	Class       string `json:"class"`
+	InventoryID string `json:"inventoryId"`
+	GuildID     string `json:"guildId"`
+	VaultID     string `json:"vaultId"`
+	Level       int    `json:"level,omitempty"`
+}
+
+type itemPayload struct {
+	PlayerItemID string `json:"playerItemId"`
+	ItemID       string `json:"itemId"`
+	Description  string `json:"description"`
+	Quantity     int    `json:"quantity"`
+}
+
+// goldPayload leaves VaultID out for gold that did not go into a vault, which
+// also keeps the rows written before deposits were told apart readable.
+type goldPayload struct {
+	Amount  int    `json:"amount"`
+	VaultID string `json:"vaultId,omitempty"`
+}
+
+type guildPayload struct {
+	GuildID string `json:"guildId"`
+}
+
+type levelPayload struct {
+	Level int `json:"level"`
+}
+
+type suspendedPayload struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+type statusPayload struct {
+	Reason string `json:"reason"`
+}
+
+// EventsToDAO maps the pending events of a character to event log rows.
+func EventsToDAO(events []character.Event, occurredAt time.Time) ([]Event, error) {
+	rows := make([]Event, 0, len(events))
+	for _, event := range events {
+		row, err := EventToDAO(event, occurredAt)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, *row)
+	}
+	return rows, nil
+}
+
+func EventToDAO(event character.Event, occurredAt time.Time) (*Event, error) {
+	var payload any
+	switch e := event.(type) {
+	case character.CharacterCreated:
+		payload = characterCreatedPayload{
+			LoginID:     e.Login.ID().String(),
+			Nickname:    e.Nickname,
+			Class:       e.Class.String(),
+			InventoryID: e.Inventory.ID().String(),
+			GuildID:     e.Guild.ID().String(),
+			VaultID:     e.Vault.ID().String(),
+			Level:       e.Level,
+		}
+	case character.ItemAdded:
+		payload = itemToPayload(e.Item)
+	case character.ItemDropped:
+		payload = itemToPayload(e.Item)
+	case character.GoldAdded:
+		payload = goldPayload{Amount: e.Amount}
+	case character.GoldWithdrawn:
+		payload = goldPayload{Amount: e.Amount}
+		if !e.Vault.Equals(vault.VaultID{}) {
+			payload = goldPayload{Amount: e.Amount, VaultID: e.Vault.ID().String()}
+		}
+	case character.GuildChanged:
+		payload = guildPayload{GuildID: e.Guild.ID().String()}
+	case character.LevelReached:
+		payload = levelPayload{Level: e.Level}
+	case character.CharacterSuspended:
+		payload = suspendedPayload{Until: e.Until.UTC(), Reason: e.Reason}
+	case character.SuspensionLifted:
+		payload = statusPayload{Reason: e.Reason}
+	case character.CharacterBanned:
+		payload = statusPayload{Reason: e.Reason}
+	case character.CharacterDeleted:
+		payload = statusPayload{Reason: e.Reason}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownEvent, event)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s event: %w", event.EventType(), err)
+	}
+
+	return &Event{
+		CharacterID: event.AggregateID().ID().String(),
+		EventType:   event.EventType(),
+		Payload:     encoded,
+		OccurredAt:  occurredAt.UTC(),
+	}, nil
+}
+
+// DAOToEvent rebuilds a recorded event from its event log row.
+func DAOToEvent(dao *Event) (repository.RecordedEvent, error) {
+	event, err := decodeEvent(dao)
+	if err != nil {
+		return repository.RecordedEvent{}, fmt.Errorf("%w: event at position %d: %w", ErrCorruptedRow, dao.Position, err)
+	}
+
+	return repository.RecordedEvent{
+		Position:   dao.Position,
+		OccurredAt: dao.OccurredAt,
+		Event:      event,
+	}, nil
+}
+
+func decodeEvent(dao *Event) (character.Event, error) {
+	ids := uuidParser{}
+	characterId := character.NewCharacterID(ids.parse("character id", dao.CharacterID))
+
+	var event character.Event
+	switch dao.EventType {
+	case character.EventCharacterCreated:
+		var p characterCreatedPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		characterClass := class.FromID(p.Class)
+		if characterClass == "" {
+			return nil, class.ErrInvalidClass
+		}
+		if p.Level == 0 {
+			p.Level = character.StartingLevel
+		}
+		event = character.CharacterCreated{
+			Character: characterId,
+			Login:     login.NewLoginID(ids.parse("login id", p.LoginID)),
+			Nickname:  p.Nickname,
+			Class:     characterClass,
+			Inventory: inventory.NewInventoryID(ids.parse("inventory id", p.InventoryID)),
+			Guild:     guild.NewGuildID(ids.parse("guild id", p.GuildID)),
+			Vault:     vault.NewVaultID(ids.parse("vault id", p.VaultID)),
+			Level:     p.Level,
+		}
+	case character.EventItemAdded, character.EventItemDropped:
+		var p itemPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		playerItem := playeritem.Restore(
+			playeritem.NewPlayerItemID(ids.parse("player item id", p.PlayerItemID)),
+			item.NewItemID(ids.parse("item id", p.ItemID)),
+			p.Description,
+			p.Quantity,
+		)
+		if dao.EventType == character.EventItemAdded {
+			event = character.ItemAdded{Character: characterId, Item: playerItem}
+		} else {
+			event = character.ItemDropped{Character: characterId, Item: playerItem}
+		}
+	case character.EventGoldAdded, character.EventGoldWithdrawn:
+		var p goldPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		if dao.EventType == character.EventGoldAdded {
+			event = character.GoldAdded{Character: characterId, Amount: p.Amount}
+		} else {
+			withdrawn := character.GoldWithdrawn{Character: characterId, Amount: p.Amount}
+			if p.VaultID != "" {
+				withdrawn.Vault = vault.NewVaultID(ids.parse("vault id", p.VaultID))
+			}
+			event = withdrawn
+		}
+	case character.EventGuildChanged:
+		var p guildPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		event = character.GuildChanged{Character: characterId, Guild: guild.NewGuildID(ids.parse("guild id", p.GuildID))}
+	case character.EventLevelReached:
+		var p levelPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		event = character.LevelReached{Character: characterId, Level: p.Level}
+	case character.EventSuspended:
+		var p suspendedPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		event = character.CharacterSuspended{Character: characterId, Until: p.Until, Reason: p.Reason}
+	case character.EventSuspensionLifted, character.EventBanned, character.EventDeleted:
+		var p statusPayload
+		if err := json.Unmarshal(dao.Payload, &p); err != nil {
+			return nil, err
+		}
+		switch dao.EventType {
+		case character.EventSuspensionLifted:
+			event = character.SuspensionLifted{Character: characterId, Reason: p.Reason}
+		case character.EventBanned:
+			event = character.CharacterBanned{Character: characterId, Reason: p.Reason}
+		default:
+			event = character.CharacterDeleted{Character: characterId, Reason: p.Reason}
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, dao.EventType)
+	}
+
+	if err := errors.Join(ids.errs...); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func itemToPayload(item playeritem.PlayerItem) itemPayload {
+	return itemPayload{
+		PlayerItemID: item.ID().String(),
+		ItemID:       item.ItemID().ID().String(),
+		Description:  item.Describe(),
+		Quantity:     item.GetCurrentQuantity(),
+	}
+}
@@ -0,0 +1,39 @@
+package dao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+)
+
+type LedgerTransaction struct {
+	ID         string
+	Reason     string
+	OccurredAt time.Time
+	Entries    []LedgerEntry
+}
+
+type LedgerEntry struct {
+	AccountID string
+	Amount    int
+}
+
+// DAOToLedgerTransaction rebuilds a stored transaction, checking it is still
+// balanced.
+func DAOToLedgerTransaction(dao LedgerTransaction) (*ledger.Transaction, error) {
+	entries := make([]ledger.Entry, 0, len(dao.Entries))
+	for _, entry := range dao.Entries {
+		account, err := ledger.ParseAccountID(entry.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ledger transaction %s: %w", ErrCorruptedRow, dao.ID, err)
+		}
+		entries = append(entries, ledger.Entry{Account: account, Amount: entry.Amount})
+	}
+
+	tx, err := ledger.NewTransaction(dao.ID, dao.Reason, dao.OccurredAt, entries...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ledger transaction %s: %w", ErrCorruptedRow, dao.ID, err)
+	}
+	return tx, nil
+}
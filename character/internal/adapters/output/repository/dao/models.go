@@ -1,25 +1,45 @@
 package dao
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
 	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
 	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
 )
 
+var ErrCorruptedRow = errors.New("stored row cannot be mapped to the domain")
+
 type Character struct {
 	CharacterID string
 	LoginID     string
 	Nickname    string
-	Class       string
-	InventoryID string //understand how to properly set up the relationship
-	GuildID     string
-	VaultID     string
+	// NicknameSkeleton is unique among the stored characters.
+	NicknameSkeleton string
+	Class            string
+	InventoryID      string
+	GuildID          string
+	VaultID          string
+	Level            int
+	Status           string
+	// SuspendedUntil is nil unless the character is suspended.
+	SuspendedUntil *time.Time
+	StatusReason   string
+	Version        int
 }
 
 type Inventory struct {
 	InventoryID string
 	GoldAmount  int
-	Items       map[playeritem.PlayerItemID]playeritem.PlayerItem //understand how to properly set up the relationship - One to Many
+	Items       []PlayerItem
 }
 
 type PlayerItem struct {
@@ -30,24 +50,121 @@ type PlayerItem struct {
 }
 
 func InventorytoDAO(inventory inventory.Inventory) *Inventory {
+	items := make([]PlayerItem, 0, len(inventory.Items()))
+	for _, item := range inventory.Items() {
+		items = append(items, PlayerItem{
+			PlayerItemId: item.ID().String(),
+			ItemID:       item.ItemID().ID().String(),
+			Description:  item.Describe(),
+			Quantity:     item.GetCurrentQuantity(),
+		})
+	}
+
 	return &Inventory{
 		InventoryID: inventory.ID().String(),
 		GoldAmount:  inventory.GetCurrentGold(),
+		Items:       items,
 	}
 }
 
 func CharacterToDAO(character character.Character) *Character {
-	return &Character{
-		CharacterID: character.ID().String(),
-		LoginID:     character.LoginID().ID().String(),
-		Nickname:    character.Nickname(),
-		Class:       character.Class().String(),
-		InventoryID: character.Inventory().ID().String(),
-		GuildID:     character.GetCurrentGuild().ID().String(),
-		VaultID:     character.GetCurrentVaultId().ID().String(),
+	standing := character.Standing()
+	dao := &Character{
+		CharacterID:      character.ID().String(),
+		LoginID:          character.LoginID().ID().String(),
+		Nickname:         character.Nickname(),
+		NicknameSkeleton: character.NicknameSkeleton(),
+		Class:            character.Class().String(),
+		InventoryID:      character.Inventory().ID().String(),
+		GuildID:          character.GetCurrentGuild().ID().String(),
+		VaultID:          character.GetCurrentVaultId().ID().String(),
+		Level:            character.Level(),
+		Status:           standing.Status.String(),
+		StatusReason:     standing.Reason,
+		Version:          character.Version(),
 	}
+	if !standing.SuspendedUntil.IsZero() {
+		until := standing.SuspendedUntil.UTC()
+		dao.SuspendedUntil = &until
+	}
+	return dao
 }
 
-func DAOToCharacter(dao *Character) *character.Character {
-	return &character.Character{}
+// DAOToCharacter rebuilds the aggregate from its stored rows.
+func DAOToCharacter(dao *Character, daoInventory *Inventory) (*character.Character, error) {
+	ids := uuidParser{}
+	characterId := ids.parse("character id", dao.CharacterID)
+	loginId := ids.parse("login id", dao.LoginID)
+	guildId := ids.parse("guild id", dao.GuildID)
+	vaultId := ids.parse("vault id", dao.VaultID)
+	restoredInventory := restoreInventory(daoInventory, &ids)
+
+	if err := errors.Join(ids.errs...); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedRow, err)
+	}
+
+	// the class is not checked against the catalog: a class removed from it
+	// still names the class of the characters created with it
+	characterClass := class.FromID(dao.Class)
+	if characterClass == "" {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedRow, class.ErrInvalidClass)
+	}
+
+	status, err := character.ParseStatus(dao.Status)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedRow, err)
+	}
+	standing := character.Standing{Status: status, Reason: dao.StatusReason}
+	if dao.SuspendedUntil != nil {
+		standing.SuspendedUntil = dao.SuspendedUntil.UTC()
+	}
+
+	return character.Restore(
+		character.NewCharacterID(characterId),
+		login.NewLoginID(loginId),
+		dao.Nickname,
+		characterClass,
+		*restoredInventory,
+		guild.NewGuildID(guildId),
+		vault.NewVaultID(vaultId),
+		dao.Level,
+		standing,
+		dao.Version,
+	), nil
+}
+
+// DAOToInventory rebuilds an inventory from its stored rows.
+func DAOToInventory(daoInventory *Inventory) (*inventory.Inventory, error) {
+	ids := uuidParser{}
+	restored := restoreInventory(daoInventory, &ids)
+
+	if err := errors.Join(ids.errs...); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedRow, err)
+	}
+	return restored, nil
+}
+
+func restoreInventory(daoInventory *Inventory, ids *uuidParser) *inventory.Inventory {
+	inventoryId := ids.parse("inventory id", daoInventory.InventoryID)
+
+	items := make([]playeritem.PlayerItem, 0, len(daoInventory.Items))
+	for _, i := range daoInventory.Items {
+		playerItemId := ids.parse("player item id", i.PlayerItemId)
+		itemId := ids.parse("item id", i.ItemID)
+		items = append(items, playeritem.Restore(playeritem.NewPlayerItemID(playerItemId), item.NewItemID(itemId), i.Description, i.Quantity))
+	}
+
+	return inventory.Restore(inventory.NewInventoryID(inventoryId), daoInventory.GoldAmount, items)
+}
+
+type uuidParser struct {
+	errs []error
+}
+
+func (p *uuidParser) parse(field, value string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s %q: %w", field, value, err))
+	}
+	return id
 }
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// InventoryPersistence selects how the repositories store inventories.
+type InventoryPersistence struct {
+	// EventSourced rebuilds inventories from the latest snapshot and the
+	// inventory events recorded after it. The inventory tables are still
+	// written, in the same transaction, so persistence can be switched back.
+	EventSourced bool
+	// SnapshotEvery is the number of inventory events after which Save and
+	// Update write a new snapshot. Only used when EventSourced is set.
+	SnapshotEvery int
+}
+
+// InventorySnapshot is the state of an inventory once every event up to
+// Position was applied.
+type InventorySnapshot struct {
+	CharacterID string
+	Position    int64
+	Inventory   Inventory
+}
+
+// EncodeSnapshotItems encodes the items of a snapshot with the keys of the
+// item event payloads.
+func EncodeSnapshotItems(items []PlayerItem) ([]byte, error) {
+	payload := make([]itemPayload, 0, len(items))
+	for _, i := range items {
+		payload = append(payload, itemPayload{
+			PlayerItemID: i.PlayerItemId,
+			ItemID:       i.ItemID,
+			Description:  i.Description,
+			Quantity:     i.Quantity,
+		})
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode snapshot items: %w", err)
+	}
+	return encoded, nil
+}
+
+func DecodeSnapshotItems(data []byte) ([]PlayerItem, error) {
+	var payload []itemPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: snapshot items: %w", ErrCorruptedRow, err)
+	}
+
+	items := make([]PlayerItem, 0, len(payload))
+	for _, p := range payload {
+		items = append(items, PlayerItem{
+			PlayerItemId: p.PlayerItemID,
+			ItemID:       p.ItemID,
+			Description:  p.Description,
+			Quantity:     p.Quantity,
+		})
+	}
+	return items, nil
+}
+
+// ReplayInventory applies the recorded events to the snapshot state and
+// returns the resulting inventory. A history the domain rejects means the
+// store is corrupted.
+func ReplayInventory(snapshot *Inventory, recorded []repository.RecordedEvent) (*Inventory, error) {
+	restored, err := DAOToInventory(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]character.Event, 0, len(recorded))
+	for _, r := range recorded {
+		events = append(events, r.Event)
+	}
+	if err := character.ReplayInventory(restored, events); err != nil {
+		return nil, fmt.Errorf("%w: inventory %s: %w", ErrCorruptedRow, snapshot.InventoryID, err)
+	}
+
+	return InventorytoDAO(*restored), nil
+}
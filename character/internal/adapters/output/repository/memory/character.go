@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+var ErrCharacterAlreadySaved = errors.New("character is already saved")
+
+// storedCharacter is the snapshot kept for a character. Aggregates are copied
+// into and out of it, so callers never share state with the store.
+type storedCharacter struct {
+	character dao.Character
+	inventory dao.Inventory
+}
+
+// CharacterRepository keeps characters and their event log in memory. It
+// implements repository.CharacterRepository, repository.CharacterEventLog,
+// repository.InventoryHistory, repository.CharacterSuspensions,
+// repository.CharacterGold and repository.ProcessedMessageRepository.
+type CharacterRepository struct {
+	mu          sync.RWMutex
+	characters  map[string]storedCharacter
+	processed   map[string]struct{}
+	events      []repository.RecordedEvent
+	snapshots   map[string]dao.InventorySnapshot
+	inventories dao.InventoryPersistence
+	clock       clock.Clock
+}
+
+func NewCharacterRepository(clock clock.Clock, inventories dao.InventoryPersistence) *CharacterRepository {
+	return &CharacterRepository{
+		characters:  make(map[string]storedCharacter),
+		processed:   make(map[string]struct{}),
+		snapshots:   make(map[string]dao.InventorySnapshot),
+		inventories: inventories,
+		clock:       clock,
+	}
+}
+
+func (c *CharacterRepository) Save(ctx context.Context, character character.Character) error {
+	stored := c.store(character)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.characters[stored.character.CharacterID]; ok {
+		return fmt.Errorf("%w: %s", ErrCharacterAlreadySaved, stored.character.CharacterID)
+	}
+	for _, other := range c.characters {
+		if other.character.NicknameSkeleton == stored.character.NicknameSkeleton {
+			return nicknameTaken(stored.character.Nickname)
+		}
+	}
+	c.characters[stored.character.CharacterID] = stored
+	c.append(character.PendingEvents())
+	c.snapshotIfDue(character)
+	return nil
+}
+
+func (c *CharacterRepository) FindCharacterById(ctx context.Context, characterId character.CharacterID) (*character.Character, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stored, ok := c.characters[characterId.ID().String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId.ID())
+	}
+
+	if !c.inventories.EventSourced {
+		return dao.DAOToCharacter(&stored.character, &stored.inventory)
+	}
+
+	latest, ok := c.snapshots[stored.character.CharacterID]
+	if !ok {
+		latest.Inventory = dao.Inventory{InventoryID: stored.inventory.InventoryID}
+	}
+	replayed, err := dao.ReplayInventory(&latest.Inventory, c.inventoryEvents(characterId, latest.Position))
+	if err != nil {
+		return nil, err
+	}
+	return dao.DAOToCharacter(&stored.character, replayed)
+}
+
+func (c *CharacterRepository) Update(ctx context.Context, character character.Character) error {
+	updated := c.store(character)
+	id := updated.character.CharacterID
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.characters[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, id)
+	}
+	messageID, fromMessage := repository.MessageIDFromContext(ctx)
+	if _, ok := c.processed[messageID]; fromMessage && ok {
+		return fmt.Errorf("%w: %s", repository.ErrMessageAlreadyProcessed, messageID)
+	}
+	if current.character.Version != updated.character.Version {
+		return fmt.Errorf("%w: %s", repository.ErrConcurrentUpdate, id)
+	}
+
+	if fromMessage {
+		c.processed[messageID] = struct{}{}
+	}
+	updated.character.Version++
+	c.characters[id] = updated
+	c.append(character.PendingEvents())
+	c.snapshotIfDue(character)
+	return nil
+}
+
+func (c *CharacterRepository) IsProcessed(ctx context.Context, messageID string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.processed[messageID]
+	return ok, nil
+}
+
+func (c *CharacterRepository) ReadEvents(ctx context.Context, after int64, limit int) ([]repository.RecordedEvent, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// positions start at 1 and have no gaps, so they index the log directly
+	if after < 0 {
+		after = 0
+	}
+	if after >= int64(len(c.events)) {
+		return nil, nil
+	}
+	end := min(int(after)+limit, len(c.events))
+	return append([]repository.RecordedEvent(nil), c.events[after:end]...), nil
+}
+
+func (c *CharacterRepository) LastPosition(ctx context.Context) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return int64(len(c.events)), nil
+}
+
+// append adds events to the log. The caller must hold the write lock.
+func (c *CharacterRepository) append(events []character.Event) {
+	now := c.clock.Now()
+	for _, event := range events {
+		c.events = append(c.events, repository.RecordedEvent{
+			Position:   int64(len(c.events)) + 1,
+			OccurredAt: now,
+			Event:      event,
+		})
+	}
+}
+
+func (c *CharacterRepository) CharactersWithGold(ctx context.Context) ([]character.CharacterID, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var ids []character.CharacterID
+	for _, characterId := range slices.Sorted(maps.Keys(c.characters)) {
+		if c.characters[characterId].inventory.GoldAmount == 0 {
+			continue
+		}
+		parsed, err := uuid.Parse(characterId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: character id %q: %w", dao.ErrCorruptedRow, characterId, err)
+		}
+		ids = append(ids, character.NewCharacterID(parsed))
+	}
+	return ids, nil
+}
+
+func (c *CharacterRepository) ExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]character.CharacterID, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var expired []dao.Character
+	for _, stored := range c.characters {
+		until := stored.character.SuspendedUntil
+		if stored.character.Status == character.StatusSuspended.String() && until != nil && !now.Before(*until) {
+			expired = append(expired, stored.character)
+		}
+	}
+	slices.SortFunc(expired, func(a, b dao.Character) int {
+		if n := a.SuspendedUntil.Compare(*b.SuspendedUntil); n != 0 {
+			return n
+		}
+		return strings.Compare(a.CharacterID, b.CharacterID)
+	})
+
+	ids := make([]character.CharacterID, 0, min(limit, len(expired)))
+	for _, stored := range expired[:min(limit, len(expired))] {
+		parsed, err := uuid.Parse(stored.CharacterID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: character id %q: %w", dao.ErrCorruptedRow, stored.CharacterID, err)
+		}
+		ids = append(ids, character.NewCharacterID(parsed))
+	}
+	return ids, nil
+}
+
+func (c *CharacterRepository) InventoryHistory(ctx context.Context, characterId character.CharacterID, filter repository.InventoryHistoryFilter) ([]repository.RecordedEvent, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.characters[characterId.ID().String()]; !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId.ID())
+	}
+
+	var history []repository.RecordedEvent
+	for _, recorded := range c.inventoryEvents(characterId, 0) {
+		if filter.Matches(recorded) {
+			history = append(history, recorded)
+		}
+	}
+	return history, nil
+}
+
+// inventoryEvents returns the inventory events of the character recorded
+// after position. The caller must hold the lock.
+func (c *CharacterRepository) inventoryEvents(characterId character.CharacterID, after int64) []repository.RecordedEvent {
+	var found []repository.RecordedEvent
+	for _, recorded := range c.events[min(int(after), len(c.events)):] {
+		if recorded.Event.AggregateID().ID() == characterId.ID() && character.IsInventoryEvent(recorded.Event) {
+			found = append(found, recorded)
+		}
+	}
+	return found
+}
+
+// snapshotIfDue stores the inventory of the character once SnapshotEvery
+// inventory events were appended after its latest snapshot. The caller must
+// hold the write lock and have appended the pending events.
+func (c *CharacterRepository) snapshotIfDue(character character.Character) {
+	if !c.inventories.EventSourced {
+		return
+	}
+
+	id := character.ID().String()
+	latest := c.snapshots[id]
+	if len(c.inventoryEvents(character.CharacterID, latest.Position)) < c.inventories.SnapshotEvery {
+		return
+	}
+
+	c.snapshots[id] = dao.InventorySnapshot{
+		CharacterID: id,
+		Position:    int64(len(c.events)),
+		Inventory:   *dao.InventorytoDAO(character.Inventory()),
+	}
+}
+
+func nicknameTaken(nickname string) error {
+	return fmt.Errorf("%w: %s", character.ErrNicknameTaken, nickname)
+}
+
+// store copies the aggregate into its stored form. Event-sourced inventories
+// are rebuilt from the events, but their state is kept as well, as the MySQL
+// repository keeps it in the inventory tables.
+func (c *CharacterRepository) store(character character.Character) storedCharacter {
+	return storedCharacter{
+		character: *dao.CharacterToDAO(character),
+		inventory: *dao.InventorytoDAO(character.Inventory()),
+	}
+}
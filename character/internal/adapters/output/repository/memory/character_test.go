@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/repositorytest"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// eventSourced snapshots often, so the contracts load through snapshots as
+// well as through plain replays.
+var eventSourced = dao.InventoryPersistence{EventSourced: true, SnapshotEvery: 2}
+
+func TestCharacterRepositoryContract(t *testing.T) {
+	repositorytest.CharacterRepositoryContract(t, func(t *testing.T) repository.CharacterRepository {
+		return NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+	})
+}
+
+func TestCharacterEventLogContract(t *testing.T) {
+	repositorytest.CharacterEventLogContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterEventLog) {
+		repo := NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestCharacterSuspensionsContract(t *testing.T) {
+	repositorytest.CharacterSuspensionsContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterSuspensions) {
+		repo := NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestCharacterGoldContract(t *testing.T) {
+	repositorytest.CharacterGoldContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterGold) {
+		repo := NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestEventSourcedCharacterRepositoryContract(t *testing.T) {
+	repositorytest.CharacterRepositoryContract(t, func(t *testing.T) repository.CharacterRepository {
+		return NewCharacterRepository(clock.System{}, eventSourced)
+	})
+}
+
+func TestInventoryHistoryContract(t *testing.T) {
+	for name, inventories := range map[string]dao.InventoryPersistence{"state": {}, "event sourced": eventSourced} {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.InventoryHistoryContract(t, func(t *testing.T) (repository.CharacterRepository, repository.InventoryHistory) {
+				repo := NewCharacterRepository(clock.System{}, inventories)
+				return repo, repo
+			})
+		})
+	}
+}
+
+func TestCharacterViewRepositoryContract(t *testing.T) {
+	repositorytest.CharacterViewRepositoryContract(t, func(t *testing.T) repository.CharacterViewRepository {
+		return NewCharacterViewRepository()
+	})
+}
+
+func TestLedgerRepositoryContract(t *testing.T) {
+	repositorytest.LedgerRepositoryContract(t, func(t *testing.T) repository.LedgerRepository {
+		return NewLedgerRepository()
+	})
+}
+
+func TestWalletRepositoryContract(t *testing.T) {
+	repositorytest.WalletRepositoryContract(t, func(t *testing.T) repository.WalletRepository {
+		return NewWalletRepository()
+	})
+}
+
+func TestRateLimitRepositoryContract(t *testing.T) {
+	repositorytest.RateLimitRepositoryContract(t, func(t *testing.T) repository.RateLimitRepository {
+		return NewRateLimitRepository()
+	})
+}
+
+func TestClassRepositoryContract(t *testing.T) {
+	repositorytest.ClassRepositoryContract(t, func(t *testing.T) repository.ClassRepository {
+		return NewClassRepository(class.Defaults().List()...)
+	})
+}
+
+func TestCharacterRepositoryDoesNotAlias(t *testing.T) {
+	repo := NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+	ctx := context.Background()
+
+	saved, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Arthas", login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, *saved))
+
+	// mutating the saved aggregate after Save must not reach the store
+	sword, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), "Sword", 1)
+	require.NoError(t, err)
+	require.NoError(t, saved.PickItem(*sword))
+	require.NoError(t, saved.PickGold(10))
+
+	first, err := repo.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	assert.Empty(t, first.OpenInventory())
+
+	// nor must mutating a loaded aggregate reach the next load
+	require.NoError(t, first.PickItem(*sword))
+
+	second, err := repo.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	assert.Empty(t, second.OpenInventory())
+	inventory := second.Inventory()
+	assert.Zero(t, inventory.GetCurrentGold())
+}
+
+func TestSaveRejectsExistingCharacter(t *testing.T) {
+	repo := NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Arthas", login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), *c))
+
+	assert.ErrorIs(t, repo.Save(context.Background(), *c), ErrCharacterAlreadySaved)
+}
+
+func TestEventSourcedInventorySnapshots(t *testing.T) {
+	repo := NewCharacterRepository(clock.System{}, eventSourced)
+	ctx := context.Background()
+
+	saved, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Arthas", login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	require.NoError(t, saved.PickGold(10))
+	require.NoError(t, repo.Save(ctx, *saved))
+	assert.Empty(t, repo.snapshots, "one inventory event is below the snapshot interval")
+
+	loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	require.NoError(t, loaded.PickGold(5))
+	require.NoError(t, repo.Update(ctx, *loaded))
+
+	latest, ok := repo.snapshots[saved.ID().String()]
+	require.True(t, ok)
+	assert.Equal(t, int64(3), latest.Position)
+	assert.Equal(t, 15, latest.Inventory.GoldAmount)
+	assert.Empty(t, repo.characters[saved.ID().String()].inventory.Items, "the state is only kept in the events")
+
+	reloaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	inventory := reloaded.Inventory()
+	assert.Equal(t, 15, inventory.GetCurrentGold())
+}
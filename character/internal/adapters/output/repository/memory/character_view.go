@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type CharacterViewRepository struct {
+	mu         sync.RWMutex
+	views      map[uuid.UUID]repository.CharacterView
+	checkpoint int64
+}
+
+func NewCharacterViewRepository() *CharacterViewRepository {
+	return &CharacterViewRepository{
+		views: make(map[uuid.UUID]repository.CharacterView),
+	}
+}
+
+func (c *CharacterViewRepository) FindCharacterView(ctx context.Context, characterId uuid.UUID) (*repository.CharacterView, error) {
+	c.mu.RLock()
+	view, ok := c.views[characterId]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId)
+	}
+	view.Items = slices.Clone(view.Items)
+	return &view, nil
+}
+
+func (c *CharacterViewRepository) SaveCharacterView(ctx context.Context, view repository.CharacterView) error {
+	view.Items = slices.Clone(view.Items)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.views[view.CharacterID] = view
+	c.checkpoint = view.Position
+	return nil
+}
+
+func (c *CharacterViewRepository) SearchCharacterViews(ctx context.Context, search repository.CharacterSearch) ([]repository.CharacterView, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	prefix := strings.ToLower(search.NicknamePrefix)
+	var found []repository.CharacterView
+	for _, view := range c.views {
+		switch {
+		case !strings.HasPrefix(strings.ToLower(view.Nickname), prefix):
+		case search.Class != "" && view.Class != search.Class:
+		case search.GuildID != uuid.Nil && view.GuildID != search.GuildID:
+		case search.MinLevel != 0 && view.Level < search.MinLevel:
+		case search.MaxLevel != 0 && view.Level > search.MaxLevel:
+		default:
+			view.Items = nil
+			found = append(found, view)
+		}
+	}
+
+	compare := func(view repository.CharacterView, key repository.CharacterSearchKey) int {
+		var order int
+		if search.Sort == repository.SortByGold {
+			order = cmp.Compare(view.Gold, key.Gold)
+		} else {
+			order = strings.Compare(strings.ToLower(view.Nickname), strings.ToLower(key.Nickname))
+		}
+		if order == 0 {
+			order = strings.Compare(view.CharacterID.String(), key.CharacterID.String())
+		}
+		if search.Descending {
+			return -order
+		}
+		return order
+	}
+
+	slices.SortFunc(found, func(a, b repository.CharacterView) int {
+		return compare(a, repository.CharacterSearchKey{Nickname: b.Nickname, Gold: b.Gold, CharacterID: b.CharacterID})
+	})
+	if search.After != nil {
+		found = slices.DeleteFunc(found, func(view repository.CharacterView) bool {
+			return compare(view, *search.After) <= 0
+		})
+	}
+	if len(found) > search.Limit {
+		found = found[:search.Limit]
+	}
+	return found, nil
+}
+
+func (c *CharacterViewRepository) Checkpoint(ctx context.Context) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.checkpoint, nil
+}
+
+func (c *CharacterViewRepository) SaveCheckpoint(ctx context.Context, position int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkpoint = position
+	return nil
+}
+
+func (c *CharacterViewRepository) Reset(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.views)
+	c.checkpoint = 0
+	return nil
+}
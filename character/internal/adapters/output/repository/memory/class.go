@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+)
+
+// ClassRepository keeps the class catalog in memory, starting from the
+// definitions it is built with.
+type ClassRepository struct {
+	mu      sync.RWMutex
+	classes class.Definitions
+}
+
+func NewClassRepository(definitions ...class.Definition) *ClassRepository {
+	r := &ClassRepository{classes: class.NewDefinitions()}
+	for _, definition := range definitions {
+		r.classes[definition.Class] = cloneDefinition(definition)
+	}
+	return r
+}
+
+func (r *ClassRepository) ListClasses(ctx context.Context) ([]class.Definition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := r.classes.List()
+	for i, definition := range definitions {
+		definitions[i] = cloneDefinition(definition)
+	}
+	return definitions, nil
+}
+
+func (r *ClassRepository) SaveClass(ctx context.Context, definition class.Definition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.classes[definition.Class] = cloneDefinition(definition)
+	return nil
+}
+
+// cloneDefinition keeps callers from changing a stored definition through
+// its slice or map.
+func cloneDefinition(definition class.Definition) class.Definition {
+	definition.StartingItems = slices.Clone(definition.StartingItems)
+	definition.Stats = maps.Clone(definition.Stats)
+	return definition
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// IdempotencyRepository drops the expired records whenever a key is
+// reserved, so it only grows with the keys still in use.
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]repository.IdempotencyRecord
+	clock   clock.Clock
+}
+
+func NewIdempotencyRepository(clock clock.Clock) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		records: make(map[string]repository.IdempotencyRecord),
+		clock:   clock,
+	}
+}
+
+func (i *IdempotencyRepository) Find(ctx context.Context, key string) (*repository.IdempotencyRecord, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	record, ok := i.records[key]
+	if !ok || !record.ExpiresAt.After(i.clock.Now()) {
+		return nil, repository.ErrIdempotencyKeyNotFound
+	}
+
+	record.Body = bytes.Clone(record.Body)
+	return &record, nil
+}
+
+func (i *IdempotencyRepository) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.clock.Now()
+	for stored, record := range i.records {
+		if !record.ExpiresAt.After(now) {
+			delete(i.records, stored)
+		}
+	}
+
+	if _, ok := i.records[key]; ok {
+		return repository.ErrIdempotencyKeyInUse
+	}
+
+	i.records[key] = repository.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   expiresAt,
+	}
+	return nil
+}
+
+func (i *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	record, ok := i.records[key]
+	if !ok {
+		return nil
+	}
+
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = bytes.Clone(body)
+	record.Completed = true
+	i.records[key] = record
+	return nil
+}
+
+func (i *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.records, key)
+	return nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+func TestIdempotencyRepositoryPurgesExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	clock := &manualClock{now: time.Now()}
+	keys := NewIdempotencyRepository(clock)
+
+	require.NoError(t, keys.Reserve(ctx, "expiring", "fingerprint", clock.now.Add(time.Minute)))
+	require.NoError(t, keys.Reserve(ctx, "lasting", "fingerprint", clock.now.Add(time.Hour)))
+	assert.ErrorIs(t, keys.Reserve(ctx, "expiring", "fingerprint", clock.now.Add(time.Minute)), repository.ErrIdempotencyKeyInUse)
+
+	clock.now = clock.now.Add(time.Minute)
+	_, err := keys.Find(ctx, "expiring")
+	assert.ErrorIs(t, err, repository.ErrIdempotencyKeyNotFound, "expiry follows the clock")
+
+	require.NoError(t, keys.Reserve(ctx, "other", "fingerprint", clock.now.Add(time.Hour)))
+	assert.NotContains(t, keys.records, "expiring", "expired keys are purged")
+	assert.Contains(t, keys.records, "lasting")
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type LedgerRepository struct {
+	mu           sync.RWMutex
+	transactions map[string]ledger.Transaction
+	balances     map[ledger.AccountID]int
+	checkpoint   int64
+}
+
+func NewLedgerRepository() *LedgerRepository {
+	return &LedgerRepository{
+		transactions: make(map[string]ledger.Transaction),
+		balances:     make(map[ledger.AccountID]int),
+	}
+}
+
+func (l *LedgerRepository) Post(ctx context.Context, tx ledger.Transaction) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if posted, ok := l.transactions[tx.ID()]; ok {
+		if !posted.SameAs(tx) {
+			return fmt.Errorf("%w: %s", repository.ErrTransactionConflict, tx.ID())
+		}
+		return nil
+	}
+
+	l.transactions[tx.ID()] = tx
+	for _, entry := range tx.Entries() {
+		l.balances[entry.Account] += entry.Amount
+	}
+	return nil
+}
+
+func (l *LedgerRepository) Balance(ctx context.Context, account ledger.AccountID) (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.balances[account], nil
+}
+
+func (l *LedgerRepository) Balances(ctx context.Context, kind ledger.AccountKind) ([]repository.AccountBalance, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var balances []repository.AccountBalance
+	for account, balance := range l.balances {
+		if account.Kind() == kind {
+			balances = append(balances, repository.AccountBalance{Account: account, Balance: balance})
+		}
+	}
+	slices.SortFunc(balances, func(a, b repository.AccountBalance) int {
+		return strings.Compare(a.Account.String(), b.Account.String())
+	})
+	return balances, nil
+}
+
+func (l *LedgerRepository) Checkpoint(ctx context.Context) (int64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.checkpoint, nil
+}
+
+func (l *LedgerRepository) SaveCheckpoint(ctx context.Context, position int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.checkpoint = position
+	return nil
+}
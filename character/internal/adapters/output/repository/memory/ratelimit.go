@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+)
+
+// sweepInterval bounds how often the buckets that filled up again are
+// dropped, so that one-off callers do not accumulate.
+const sweepInterval = time.Minute
+
+type rateLimitBucket struct {
+	bucket    ratelimit.Bucket
+	expiresAt time.Time
+}
+
+type RateLimitRepository struct {
+	mu        sync.Mutex
+	buckets   map[string]rateLimitBucket
+	nextSweep time.Time
+}
+
+func NewRateLimitRepository() *RateLimitRepository {
+	return &RateLimitRepository{
+		buckets: make(map[string]rateLimitBucket),
+	}
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.After(r.nextSweep) {
+		for k, b := range r.buckets {
+			if !b.expiresAt.After(now) {
+				delete(r.buckets, k)
+			}
+		}
+		r.nextSweep = now.Add(sweepInterval)
+	}
+
+	bucket, decision := limit.Take(r.buckets[key].bucket, now)
+	r.buckets[key] = rateLimitBucket{bucket: bucket, expiresAt: now.Add(decision.Reset)}
+	return decision, nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type storedWallet struct {
+	balance   int
+	version   int
+	movements map[string]wallet.Movement
+}
+
+type WalletRepository struct {
+	mu      sync.RWMutex
+	wallets map[uuid.UUID]storedWallet
+}
+
+func NewWalletRepository() *WalletRepository {
+	return &WalletRepository{
+		wallets: make(map[uuid.UUID]storedWallet),
+	}
+}
+
+func (r *WalletRepository) FindWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error) {
+	r.mu.RLock()
+	stored, ok := r.wallets[loginId.ID()]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrWalletNotFound, loginId.ID())
+	}
+	return wallet.Restore(loginId, stored.balance, stored.version), nil
+}
+
+func (r *WalletRepository) SaveWallet(ctx context.Context, w wallet.Wallet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := w.LoginID().ID()
+	stored, ok := r.wallets[id]
+	if ok != (w.Version() > 0) || stored.version != w.Version() {
+		return fmt.Errorf("%w: %s", repository.ErrConcurrentWalletUpdate, id)
+	}
+
+	movements := make(map[string]wallet.Movement, len(stored.movements)+len(w.PendingMovements()))
+	for reference, movement := range stored.movements {
+		movements[reference] = movement
+	}
+	for _, movement := range w.PendingMovements() {
+		if _, ok := movements[movement.Reference]; ok {
+			return fmt.Errorf("%w: %s", wallet.ErrDuplicateReference, movement.Reference)
+		}
+		movements[movement.Reference] = movement
+	}
+
+	r.wallets[id] = storedWallet{
+		balance:   w.Balance(),
+		version:   w.Version() + 1,
+		movements: movements,
+	}
+	return nil
+}
+
+func (r *WalletRepository) FindMovement(ctx context.Context, loginId login.LoginID, reference string) (*wallet.Movement, error) {
+	r.mu.RLock()
+	movement, ok := r.wallets[loginId.ID()].movements[reference]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrMovementNotFound, reference)
+	}
+	return &movement, nil
+}
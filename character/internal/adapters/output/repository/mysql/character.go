@@ -3,19 +3,32 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
 )
 
+// CharacterRepository stores characters and appends their events to the
+// CHARACTER_EVENTS table. It implements repository.CharacterRepository,
+// repository.CharacterEventLog, repository.InventoryHistory,
+// repository.CharacterSuspensions, repository.CharacterGold and
+// repository.ProcessedMessageRepository.
 type CharacterRepository struct {
-	db *sql.DB
+	db          *sql.DB
+	inventories dao.InventoryPersistence
 }
 
-func NewCharacterRepository(db *sql.DB) *CharacterRepository {
+func NewCharacterRepository(db *sql.DB, inventories dao.InventoryPersistence) *CharacterRepository {
 	return &CharacterRepository{
-		db: db,
+		db:          db,
+		inventories: inventories,
 	}
 }
 
@@ -36,12 +49,23 @@ func (c *CharacterRepository) Save(ctx context.Context, character character.Char
 		return fmt.Errorf("error saving inventory: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, CreateNewCharacterQuery, daoCharacter.CharacterID, daoCharacter.LoginID, daoCharacter.Nickname, daoCharacter.Class, daoCharacter.InventoryID, daoCharacter.GuildID, daoCharacter.VaultID)
+	_, err = tx.ExecContext(ctx, CreateNewCharacterQuery, daoCharacter.CharacterID, daoCharacter.LoginID, daoCharacter.Nickname, daoCharacter.NicknameSkeleton, daoCharacter.Class, daoCharacter.InventoryID, daoCharacter.GuildID, daoCharacter.VaultID, daoCharacter.Level, daoCharacter.Status, daoCharacter.SuspendedUntil, daoCharacter.StatusReason, daoCharacter.Version)
 
+	if isNicknameTaken(err) {
+		return nicknameTaken(daoCharacter.Nickname)
+	}
 	if err != nil {
 		return fmt.Errorf("error saving character: %w", err)
 	}
 
+	if err := insertItems(ctx, tx, daoInventory); err != nil {
+		return err
+	}
+
+	if err := c.appendEvents(ctx, tx, character); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing saving transaction: %w", err)
 	}
@@ -50,9 +74,360 @@ func (c *CharacterRepository) Save(ctx context.Context, character character.Char
 }
 
 func (c *CharacterRepository) FindCharacterById(ctx context.Context, characterId character.CharacterID) (*character.Character, error) {
-	return nil, nil
+	var (
+		daoCharacter dao.Character
+		daoInventory dao.Inventory
+	)
+
+	err := c.db.QueryRowContext(ctx, FindCharacterByIdQuery, characterId.ID().String()).Scan(
+		&daoCharacter.CharacterID, &daoCharacter.LoginID, &daoCharacter.Nickname, &daoCharacter.Class,
+		&daoCharacter.InventoryID, &daoCharacter.GuildID, &daoCharacter.VaultID, &daoCharacter.Level,
+		&daoCharacter.Status, &daoCharacter.SuspendedUntil, &daoCharacter.StatusReason, &daoCharacter.Version,
+		&daoInventory.GoldAmount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId.ID())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading character: %w", err)
+	}
+	daoInventory.InventoryID = daoCharacter.InventoryID
+
+	if c.inventories.EventSourced {
+		replayed, err := c.replayInventory(ctx, daoCharacter.CharacterID, daoInventory.InventoryID)
+		if err != nil {
+			return nil, err
+		}
+		return dao.DAOToCharacter(&daoCharacter, replayed)
+	}
+
+	rows, err := c.db.QueryContext(ctx, FindPlayerItemsQuery, daoInventory.InventoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading player items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item dao.PlayerItem
+		if err := rows.Scan(&item.PlayerItemId, &item.ItemID, &item.Description, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("error reading player item: %w", err)
+		}
+		daoInventory.Items = append(daoInventory.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading player items: %w", err)
+	}
+
+	return dao.DAOToCharacter(&daoCharacter, &daoInventory)
 }
 
 func (c *CharacterRepository) Update(ctx context.Context, character character.Character) error {
+
+	daoInventory := dao.InventorytoDAO(character.Inventory())
+	daoCharacter := dao.CharacterToDAO(character)
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting update transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordMessage(ctx, tx); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, UpdateCharacterQuery, daoCharacter.Nickname, daoCharacter.Class, daoCharacter.GuildID, daoCharacter.VaultID, daoCharacter.Level, daoCharacter.Status, daoCharacter.SuspendedUntil, daoCharacter.StatusReason, daoCharacter.CharacterID, daoCharacter.Version)
+	if err != nil {
+		return fmt.Errorf("error updating character: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating character: %w", err)
+	}
+	if updated == 0 {
+		return c.missingOrStale(ctx, tx, daoCharacter.CharacterID)
+	}
+
+	// the inventory tables are kept up to date for event-sourced inventories
+	// too, so that persistence can be switched back to the state tables
+	if _, err := tx.ExecContext(ctx, UpdateInventoryQuery, daoInventory.GoldAmount, daoInventory.InventoryID); err != nil {
+		return fmt.Errorf("error updating inventory: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, DeletePlayerItemsQuery, daoInventory.InventoryID); err != nil {
+		return fmt.Errorf("error replacing player items: %w", err)
+	}
+
+	if err := insertItems(ctx, tx, daoInventory); err != nil {
+		return err
+	}
+
+	if err := c.appendEvents(ctx, tx, character); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing update transaction: %w", err)
+	}
+
 	return nil
 }
+
+func (c *CharacterRepository) IsProcessed(ctx context.Context, messageID string) (bool, error) {
+	var count int
+	if err := c.db.QueryRowContext(ctx, IsMessageProcessedQuery, messageID).Scan(&count); err != nil {
+		return false, fmt.Errorf("error checking processed message: %w", err)
+	}
+	return count > 0, nil
+}
+
+// recordMessage records the message id carried by ctx, if any. A concurrent
+// transaction recording the same id holds its row lock until it ends, so
+// only one of them applies the message.
+func recordMessage(ctx context.Context, tx *sql.Tx) error {
+	messageID, ok := repository.MessageIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, MarkMessageProcessedQuery, messageID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error marking message as processed: %w", err)
+	}
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error marking message as processed: %w", err)
+	}
+	if recorded == 0 {
+		return fmt.Errorf("%w: %s", repository.ErrMessageAlreadyProcessed, messageID)
+	}
+	return nil
+}
+
+func (c *CharacterRepository) CharactersWithGold(ctx context.Context) ([]character.CharacterID, error) {
+	rows, err := c.db.QueryContext(ctx, CharactersWithGoldQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error reading characters with gold: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []character.CharacterID
+	for rows.Next() {
+		var characterId string
+		if err := rows.Scan(&characterId); err != nil {
+			return nil, fmt.Errorf("error reading character with gold: %w", err)
+		}
+		parsed, err := uuid.Parse(characterId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: character id %q: %w", dao.ErrCorruptedRow, characterId, err)
+		}
+		ids = append(ids, character.NewCharacterID(parsed))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading characters with gold: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (c *CharacterRepository) ExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]character.CharacterID, error) {
+	rows, err := c.db.QueryContext(ctx, ExpiredSuspensionsQuery, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error reading expired suspensions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []character.CharacterID
+	for rows.Next() {
+		var characterId string
+		if err := rows.Scan(&characterId); err != nil {
+			return nil, fmt.Errorf("error reading expired suspension: %w", err)
+		}
+		parsed, err := uuid.Parse(characterId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: character id %q: %w", dao.ErrCorruptedRow, characterId, err)
+		}
+		ids = append(ids, character.NewCharacterID(parsed))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading expired suspensions: %w", err)
+	}
+
+	return ids, nil
+}
+
+// missingOrStale tells apart the two reasons an update touches no row.
+func (c *CharacterRepository) missingOrStale(ctx context.Context, tx *sql.Tx, characterId string) error {
+	var count int
+	if err := tx.QueryRowContext(ctx, CharacterExistsQuery, characterId).Scan(&count); err != nil {
+		return fmt.Errorf("error checking character: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId)
+	}
+	return fmt.Errorf("%w: %s", repository.ErrConcurrentUpdate, characterId)
+}
+
+func insertItems(ctx context.Context, tx *sql.Tx, inventory *dao.Inventory) error {
+	for _, item := range inventory.Items {
+		_, err := tx.ExecContext(ctx, CreateNewPlayerItemQuery, item.PlayerItemId, item.ItemID, item.Description, item.Quantity, inventory.InventoryID)
+		if err != nil {
+			return fmt.Errorf("error saving player item: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *CharacterRepository) ReadEvents(ctx context.Context, after int64, limit int) ([]repository.RecordedEvent, error) {
+	return c.queryEvents(ctx, ReadCharacterEventsQuery, after, limit)
+}
+
+func (c *CharacterRepository) LastPosition(ctx context.Context) (int64, error) {
+	var position int64
+	if err := c.db.QueryRowContext(ctx, LastEventPositionQuery).Scan(&position); err != nil {
+		return 0, fmt.Errorf("error reading last event position: %w", err)
+	}
+	return position, nil
+}
+
+// appendEvents appends the pending events of the character and, for
+// event-sourced inventories, snapshots the inventory when one is due.
+func (c *CharacterRepository) appendEvents(ctx context.Context, tx *sql.Tx, character character.Character) error {
+	rows, err := dao.EventsToDAO(character.PendingEvents(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	var position int64
+	for _, row := range rows {
+		// JSON columns reject binary strings, so the payload goes as text
+		result, err := tx.ExecContext(ctx, AppendCharacterEventQuery, row.CharacterID, row.EventType, string(row.Payload), row.OccurredAt)
+		if err != nil {
+			return fmt.Errorf("error appending %s event: %w", row.EventType, err)
+		}
+		if position, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error appending %s event: %w", row.EventType, err)
+		}
+	}
+
+	if !c.inventories.EventSourced || position == 0 {
+		return nil
+	}
+	return c.snapshotIfDue(ctx, tx, character, position)
+}
+
+// snapshotIfDue stores the inventory once SnapshotEvery inventory events were
+// appended after the latest snapshot. position is the last event appended in
+// tx, which the snapshot state includes.
+func (c *CharacterRepository) snapshotIfDue(ctx context.Context, tx *sql.Tx, character character.Character, position int64) error {
+	characterId := character.ID().String()
+
+	var pending int
+	if err := tx.QueryRowContext(ctx, CountUnsnapshottedEventsQuery, characterId, characterId).Scan(&pending); err != nil {
+		return fmt.Errorf("error counting inventory events: %w", err)
+	}
+	if pending < c.inventories.SnapshotEvery {
+		return nil
+	}
+
+	daoInventory := dao.InventorytoDAO(character.Inventory())
+	items, err := dao.EncodeSnapshotItems(daoInventory.Items)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, SaveInventorySnapshotQuery, characterId, position, daoInventory.GoldAmount, string(items), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error saving inventory snapshot: %w", err)
+	}
+	return nil
+}
+
+// replayInventory rebuilds the inventory from its latest snapshot and the
+// inventory events recorded after it.
+func (c *CharacterRepository) replayInventory(ctx context.Context, characterId, inventoryId string) (*dao.Inventory, error) {
+	snapshot := dao.Inventory{InventoryID: inventoryId}
+	var (
+		position int64
+		items    []byte
+	)
+
+	err := c.db.QueryRowContext(ctx, FindLatestInventorySnapshotQuery, characterId).Scan(&position, &snapshot.GoldAmount, &items)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("error loading inventory snapshot: %w", err)
+	default:
+		if snapshot.Items, err = dao.DecodeSnapshotItems(items); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := c.queryEvents(ctx, ReadInventoryEventsQuery, characterId, position)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.ReplayInventory(&snapshot, events)
+}
+
+func (c *CharacterRepository) InventoryHistory(ctx context.Context, characterId character.CharacterID, filter repository.InventoryHistoryFilter) ([]repository.RecordedEvent, error) {
+	id := characterId.ID().String()
+
+	var count int
+	if err := c.db.QueryRowContext(ctx, CharacterExistsQuery, id).Scan(&count); err != nil {
+		return nil, fmt.Errorf("error checking character: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, id)
+	}
+
+	from := sql.NullTime{Time: filter.From.UTC(), Valid: !filter.From.IsZero()}
+	to := sql.NullTime{Time: filter.To.UTC(), Valid: !filter.To.IsZero()}
+	item := ""
+	if filter.ItemID != uuid.Nil {
+		item = filter.ItemID.String()
+	}
+
+	return c.queryEvents(ctx, InventoryHistoryQuery, id, from, from, to, to, item, item, item)
+}
+
+// queryEvents runs a query selecting event log rows and decodes them.
+func (c *CharacterRepository) queryEvents(ctx context.Context, query string, args ...any) ([]repository.RecordedEvent, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading character events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []repository.RecordedEvent
+	for rows.Next() {
+		var row dao.Event
+		if err := rows.Scan(&row.Position, &row.CharacterID, &row.EventType, &row.Payload, &row.OccurredAt); err != nil {
+			return nil, fmt.Errorf("error reading character event: %w", err)
+		}
+		event, err := dao.DAOToEvent(&row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading character events: %w", err)
+	}
+
+	return events, nil
+}
+
+// nicknameSkeletonKey is the unique index on CHARACTERS.NICKNAME_SKELETON.
+const nicknameSkeletonKey = "UQ_CHARACTERS_NICKNAME_SKELETON"
+
+func isNicknameTaken(err error) bool {
+	var mysqlErr *driver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry && strings.Contains(mysqlErr.Message, nicknameSkeletonKey)
+}
+
+func nicknameTaken(nickname string) error {
+	return fmt.Errorf("%w: %s", character.ErrNicknameTaken, nickname)
+}
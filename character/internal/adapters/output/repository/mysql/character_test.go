@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/repositorytest"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"github.com/vterry/ddd-study/character/internal/infra/db"
+)
+
+// TestCharacterRepositoryContract runs against the database named by
+// MYSQL_TEST_DSN, e.g. "character:characterPW@tcp(127.0.0.1:3306)/character-db".
+func TestCharacterRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterRepositoryContract(t, func(t *testing.T) repository.CharacterRepository {
+		return NewCharacterRepository(conn, dao.InventoryPersistence{})
+	})
+}
+
+func TestCharacterEventLogContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterEventLogContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterEventLog) {
+		repo := NewCharacterRepository(conn, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestCharacterSuspensionsContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterSuspensionsContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterSuspensions) {
+		repo := NewCharacterRepository(conn, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestCharacterGoldContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterGoldContract(t, func(t *testing.T) (repository.CharacterRepository, repository.CharacterGold) {
+		repo := NewCharacterRepository(conn, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestEventSourcedCharacterRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterRepositoryContract(t, func(t *testing.T) repository.CharacterRepository {
+		return NewCharacterRepository(conn, dao.InventoryPersistence{EventSourced: true, SnapshotEvery: 2})
+	})
+}
+
+func TestInventoryPersistenceSwitchContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.InventoryPersistenceSwitchContract(t,
+		NewCharacterRepository(conn, dao.InventoryPersistence{EventSourced: true, SnapshotEvery: 2}),
+		NewCharacterRepository(conn, dao.InventoryPersistence{}),
+	)
+}
+
+func TestInventoryHistoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.InventoryHistoryContract(t, func(t *testing.T) (repository.CharacterRepository, repository.InventoryHistory) {
+		repo := NewCharacterRepository(conn, dao.InventoryPersistence{})
+		return repo, repo
+	})
+}
+
+func TestCharacterViewRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.CharacterViewRepositoryContract(t, func(t *testing.T) repository.CharacterViewRepository {
+		return NewCharacterViewRepository(conn)
+	})
+}
+
+func TestLedgerRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.LedgerRepositoryContract(t, func(t *testing.T) repository.LedgerRepository {
+		return NewLedgerRepository(conn)
+	})
+}
+
+func TestWalletRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.WalletRepositoryContract(t, func(t *testing.T) repository.WalletRepository {
+		return NewWalletRepository(conn)
+	})
+}
+
+func TestClassRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.ClassRepositoryContract(t, func(t *testing.T) repository.ClassRepository {
+		return NewClassRepository(conn)
+	})
+}
+
+func TestRateLimitRepositoryContract(t *testing.T) {
+	conn := testDB(t)
+
+	repositorytest.RateLimitRepositoryContract(t, func(t *testing.T) repository.RateLimitRepository {
+		return NewRateLimitRepository(conn)
+	})
+}
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	cfg, err := mysqlDriver.ParseDSN(dsn)
+	require.NoError(t, err)
+	cfg.ParseTime = true
+
+	require.NoError(t, db.MigrateUp(*cfg))
+
+	conn, err := db.NewMySQLStorage(*cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+)
+
+// ClassRepository reads and writes the class catalog in CHARACTER_CLASSES.
+type ClassRepository struct {
+	db *sql.DB
+}
+
+func NewClassRepository(db *sql.DB) *ClassRepository {
+	return &ClassRepository{
+		db: db,
+	}
+}
+
+func (r *ClassRepository) ListClasses(ctx context.Context) ([]class.Definition, error) {
+	rows, err := r.db.QueryContext(ctx, ListClassesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error loading classes: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []class.Definition
+	for rows.Next() {
+		var row dao.Class
+		if err := rows.Scan(&row.ID, &row.DisplayName, &row.StartingItems, &row.Stats); err != nil {
+			return nil, fmt.Errorf("error loading classes: %w", err)
+		}
+		definition, err := dao.DAOToClass(row)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error loading classes: %w", err)
+	}
+	return definitions, nil
+}
+
+func (r *ClassRepository) SaveClass(ctx context.Context, definition class.Definition) error {
+	row, err := dao.ClassToDAO(definition)
+	if err != nil {
+		return fmt.Errorf("error encoding class: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, SaveClassQuery, row.ID, row.DisplayName, row.StartingItems, row.Stats, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error saving class: %w", err)
+	}
+	return nil
+}
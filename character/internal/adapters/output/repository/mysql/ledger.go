@@ -0,0 +1,162 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// goldLedgerProjection names the checkpoint row of the ledger poster in
+// PROJECTION_CHECKPOINTS.
+const goldLedgerProjection = "gold_ledger"
+
+// LedgerRepository keeps the entries of every transaction and, in
+// LEDGER_BALANCES, their running sum per account.
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+func (l *LedgerRepository) Post(ctx context.Context, tx ledger.Transaction) error {
+	sqlTx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting ledger transaction: %w", err)
+	}
+	defer sqlTx.Rollback()
+
+	// the primary key makes a concurrent post of the same id wait for this
+	// one and then fail as a duplicate
+	_, err = sqlTx.ExecContext(ctx, InsertLedgerTransactionQuery, tx.ID(), tx.Reason(), tx.OccurredAt().UTC())
+	var mysqlErr *driver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		sqlTx.Rollback()
+		return l.checkPosted(ctx, tx)
+	}
+	if err != nil {
+		return fmt.Errorf("error posting ledger transaction: %w", err)
+	}
+
+	for _, entry := range tx.Entries() {
+		account := entry.Account.String()
+		if _, err := sqlTx.ExecContext(ctx, InsertLedgerEntryQuery, tx.ID(), account, entry.Amount); err != nil {
+			return fmt.Errorf("error posting ledger entry: %w", err)
+		}
+		if _, err := sqlTx.ExecContext(ctx, MoveLedgerBalanceQuery, account, string(entry.Account.Kind()), entry.Amount); err != nil {
+			return fmt.Errorf("error moving ledger balance: %w", err)
+		}
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("error committing ledger transaction: %w", err)
+	}
+	return nil
+}
+
+// checkPosted compares tx with the stored transaction of the same id.
+func (l *LedgerRepository) checkPosted(ctx context.Context, tx ledger.Transaction) error {
+	posted, err := l.findTransaction(ctx, tx.ID())
+	if err != nil {
+		return err
+	}
+	if !posted.SameAs(tx) {
+		return fmt.Errorf("%w: %s", repository.ErrTransactionConflict, tx.ID())
+	}
+	return nil
+}
+
+func (l *LedgerRepository) findTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
+	var stored dao.LedgerTransaction
+	stored.ID = id
+	if err := l.db.QueryRowContext(ctx, FindLedgerTransactionQuery, id).Scan(&stored.Reason, &stored.OccurredAt); err != nil {
+		return nil, fmt.Errorf("error loading ledger transaction: %w", err)
+	}
+
+	rows, err := l.db.QueryContext(ctx, FindLedgerEntriesQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry dao.LedgerEntry
+		if err := rows.Scan(&entry.AccountID, &entry.Amount); err != nil {
+			return nil, fmt.Errorf("error reading ledger entry: %w", err)
+		}
+		stored.Entries = append(stored.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ledger entries: %w", err)
+	}
+
+	return dao.DAOToLedgerTransaction(stored)
+}
+
+func (l *LedgerRepository) Balance(ctx context.Context, account ledger.AccountID) (int, error) {
+	var balance int
+	err := l.db.QueryRowContext(ctx, FindLedgerBalanceQuery, account.String()).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading ledger balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (l *LedgerRepository) Balances(ctx context.Context, kind ledger.AccountKind) ([]repository.AccountBalance, error) {
+	rows, err := l.db.QueryContext(ctx, FindLedgerBalancesQuery, string(kind))
+	if err != nil {
+		return nil, fmt.Errorf("error listing ledger balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []repository.AccountBalance
+	for rows.Next() {
+		var (
+			accountId string
+			balance   int
+		)
+		if err := rows.Scan(&accountId, &balance); err != nil {
+			return nil, fmt.Errorf("error reading ledger balance: %w", err)
+		}
+		account, err := ledger.ParseAccountID(accountId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ledger balance: %w", dao.ErrCorruptedRow, err)
+		}
+		balances = append(balances, repository.AccountBalance{Account: account, Balance: balance})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ledger balances: %w", err)
+	}
+	return balances, nil
+}
+
+func (l *LedgerRepository) Checkpoint(ctx context.Context) (int64, error) {
+	var position int64
+	err := l.db.QueryRowContext(ctx, FindCheckpointQuery, goldLedgerProjection).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading ledger checkpoint: %w", err)
+	}
+	return position, nil
+}
+
+func (l *LedgerRepository) SaveCheckpoint(ctx context.Context, position int64) error {
+	if _, err := l.db.ExecContext(ctx, SaveCheckpointQuery, goldLedgerProjection, position); err != nil {
+		return fmt.Errorf("error saving ledger checkpoint: %w", err)
+	}
+	return nil
+}
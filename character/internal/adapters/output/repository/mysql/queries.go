@@ -1,6 +1,89 @@
 package mysql
 
 var (
-	CreateNewInventoryQuery = "INSERT INTO INVENTORIES (INVENTORY_ID, GOLD_AMOUNT) VALUES (?, ?)"
-	CreateNewCharacterQuery = "INSERT INTO CHARACTERS (CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	CreateNewInventoryQuery  = "INSERT INTO INVENTORIES (INVENTORY_ID, GOLD_AMOUNT) VALUES (?, ?)"
+	CreateNewCharacterQuery  = "INSERT INTO CHARACTERS (CHARACTER_ID, LOGIN_ID, NICKNAME, NICKNAME_SKELETON, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, STATUS, SUSPENDED_UNTIL, STATUS_REASON, VERSION) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	CreateNewPlayerItemQuery = "INSERT INTO PLAYER_ITEMS (PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY, INVENTORY_ID) VALUES (?, ?, ?, ?, ?)"
+	FindCharacterByIdQuery   = "SELECT c.CHARACTER_ID, c.LOGIN_ID, c.NICKNAME, c.CLASS, c.INVENTORY_ID, c.GUILD_ID, c.VAULT_ID, c.LEVEL, c.STATUS, c.SUSPENDED_UNTIL, c.STATUS_REASON, c.VERSION, i.GOLD_AMOUNT FROM CHARACTERS c JOIN INVENTORIES i ON i.INVENTORY_ID = c.INVENTORY_ID WHERE c.CHARACTER_ID = ?"
+	FindPlayerItemsQuery     = "SELECT PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY FROM PLAYER_ITEMS WHERE INVENTORY_ID = ?"
+	CharacterExistsQuery     = "SELECT COUNT(1) FROM CHARACTERS WHERE CHARACTER_ID = ?"
+	UpdateCharacterQuery     = "UPDATE CHARACTERS SET NICKNAME = ?, CLASS = ?, GUILD_ID = ?, VAULT_ID = ?, LEVEL = ?, STATUS = ?, SUSPENDED_UNTIL = ?, STATUS_REASON = ?, VERSION = VERSION + 1 WHERE CHARACTER_ID = ? AND VERSION = ?"
+	UpdateInventoryQuery     = "UPDATE INVENTORIES SET GOLD_AMOUNT = ? WHERE INVENTORY_ID = ?"
+	DeletePlayerItemsQuery   = "DELETE FROM PLAYER_ITEMS WHERE INVENTORY_ID = ?"
+	CharactersWithGoldQuery  = "SELECT c.CHARACTER_ID FROM CHARACTERS c JOIN INVENTORIES i ON i.INVENTORY_ID = c.INVENTORY_ID WHERE i.GOLD_AMOUNT <> 0 ORDER BY c.CHARACTER_ID"
+	ExpiredSuspensionsQuery  = "SELECT CHARACTER_ID FROM CHARACTERS WHERE STATUS = 'SUSPENDED' AND SUSPENDED_UNTIL <= ? ORDER BY SUSPENDED_UNTIL, CHARACTER_ID LIMIT ?"
+)
+
+var (
+	AppendCharacterEventQuery = "INSERT INTO CHARACTER_EVENTS (CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT) VALUES (?, ?, ?, ?)"
+	ReadCharacterEventsQuery  = "SELECT POSITION, CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT FROM CHARACTER_EVENTS WHERE POSITION > ? ORDER BY POSITION LIMIT ?"
+	LastEventPositionQuery    = "SELECT COALESCE(MAX(POSITION), 0) FROM CHARACTER_EVENTS"
+)
+
+// inventoryEventTypes lists the events that change an inventory.
+const inventoryEventTypes = "('ItemAdded', 'ItemDropped', 'GoldAdded', 'GoldWithdrawn')"
+
+var (
+	ReadInventoryEventsQuery         = "SELECT POSITION, CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT FROM CHARACTER_EVENTS WHERE CHARACTER_ID = ? AND EVENT_TYPE IN " + inventoryEventTypes + " AND POSITION > ? ORDER BY POSITION"
+	InventoryHistoryQuery            = "SELECT POSITION, CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT FROM CHARACTER_EVENTS WHERE CHARACTER_ID = ? AND EVENT_TYPE IN " + inventoryEventTypes + " AND (? IS NULL OR OCCURRED_AT >= ?) AND (? IS NULL OR OCCURRED_AT < ?) AND (? = '' OR PAYLOAD->>'$.playerItemId' = ? OR PAYLOAD->>'$.itemId' = ?) ORDER BY POSITION"
+	CountUnsnapshottedEventsQuery    = "SELECT COUNT(1) FROM CHARACTER_EVENTS WHERE CHARACTER_ID = ? AND EVENT_TYPE IN " + inventoryEventTypes + " AND POSITION > (SELECT COALESCE(MAX(POSITION), 0) FROM INVENTORY_SNAPSHOTS WHERE CHARACTER_ID = ?)"
+	FindLatestInventorySnapshotQuery = "SELECT POSITION, GOLD_AMOUNT, ITEMS FROM INVENTORY_SNAPSHOTS WHERE CHARACTER_ID = ? ORDER BY POSITION DESC LIMIT 1"
+	SaveInventorySnapshotQuery       = "INSERT INTO INVENTORY_SNAPSHOTS (CHARACTER_ID, POSITION, GOLD_AMOUNT, ITEMS, CREATED_AT) VALUES (?, ?, ?, ?, ?)"
+)
+
+var (
+	FindCharacterViewQuery       = "SELECT CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION FROM CHARACTER_VIEWS WHERE CHARACTER_ID = ?"
+	SearchCharacterViewsQuery    = "SELECT CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION FROM CHARACTER_VIEWS"
+	FindItemViewsQuery           = "SELECT PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY FROM CHARACTER_ITEM_VIEWS WHERE CHARACTER_ID = ? ORDER BY PLAYER_ITEM_ID"
+	UpsertCharacterViewQuery     = "INSERT INTO CHARACTER_VIEWS (CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE LOGIN_ID = VALUES(LOGIN_ID), NICKNAME = VALUES(NICKNAME), CLASS = VALUES(CLASS), INVENTORY_ID = VALUES(INVENTORY_ID), GUILD_ID = VALUES(GUILD_ID), VAULT_ID = VALUES(VAULT_ID), LEVEL = VALUES(LEVEL), GOLD_AMOUNT = VALUES(GOLD_AMOUNT), POSITION = VALUES(POSITION)"
+	InsertItemViewQuery          = "INSERT INTO CHARACTER_ITEM_VIEWS (CHARACTER_ID, PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY) VALUES (?, ?, ?, ?, ?)"
+	DeleteItemViewsQuery         = "DELETE FROM CHARACTER_ITEM_VIEWS WHERE CHARACTER_ID = ?"
+	DeleteAllItemViewsQuery      = "DELETE FROM CHARACTER_ITEM_VIEWS"
+	DeleteAllCharacterViewsQuery = "DELETE FROM CHARACTER_VIEWS"
+	FindCheckpointQuery          = "SELECT POSITION FROM PROJECTION_CHECKPOINTS WHERE PROJECTION = ?"
+	SaveCheckpointQuery          = "INSERT INTO PROJECTION_CHECKPOINTS (PROJECTION, POSITION) VALUES (?, ?) ON DUPLICATE KEY UPDATE POSITION = VALUES(POSITION)"
+	DeleteCheckpointQuery        = "DELETE FROM PROJECTION_CHECKPOINTS WHERE PROJECTION = ?"
+)
+
+var (
+	FindIdempotencyKeyQuery          = "SELECT IDEMPOTENCY_KEY, FINGERPRINT, STATUS_CODE, CONTENT_TYPE, RESPONSE_BODY, COMPLETED, EXPIRES_AT FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = ? AND EXPIRES_AT > ?"
+	DeleteExpiredIdempotencyKeyQuery = "DELETE FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = ? AND EXPIRES_AT <= ?"
+	ReserveIdempotencyKeyQuery       = "INSERT INTO IDEMPOTENCY_KEYS (IDEMPOTENCY_KEY, FINGERPRINT, STATUS_CODE, COMPLETED, EXPIRES_AT) VALUES (?, ?, 0, FALSE, ?)"
+	CompleteIdempotencyKeyQuery      = "UPDATE IDEMPOTENCY_KEYS SET STATUS_CODE = ?, CONTENT_TYPE = ?, RESPONSE_BODY = ?, COMPLETED = TRUE WHERE IDEMPOTENCY_KEY = ?"
+	ReleaseIdempotencyKeyQuery       = "DELETE FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = ?"
+)
+
+var (
+	InsertLedgerTransactionQuery = "INSERT INTO LEDGER_TRANSACTIONS (TRANSACTION_ID, REASON, OCCURRED_AT) VALUES (?, ?, ?)"
+	InsertLedgerEntryQuery       = "INSERT INTO LEDGER_ENTRIES (TRANSACTION_ID, ACCOUNT_ID, AMOUNT) VALUES (?, ?, ?)"
+	MoveLedgerBalanceQuery       = "INSERT INTO LEDGER_BALANCES (ACCOUNT_ID, ACCOUNT_KIND, BALANCE) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE BALANCE = BALANCE + VALUES(BALANCE)"
+	FindLedgerTransactionQuery   = "SELECT REASON, OCCURRED_AT FROM LEDGER_TRANSACTIONS WHERE TRANSACTION_ID = ?"
+	FindLedgerEntriesQuery       = "SELECT ACCOUNT_ID, AMOUNT FROM LEDGER_ENTRIES WHERE TRANSACTION_ID = ?"
+	FindLedgerBalanceQuery       = "SELECT BALANCE FROM LEDGER_BALANCES WHERE ACCOUNT_ID = ?"
+	FindLedgerBalancesQuery      = "SELECT ACCOUNT_ID, BALANCE FROM LEDGER_BALANCES WHERE ACCOUNT_KIND = ? ORDER BY ACCOUNT_ID"
+)
+
+var (
+	FindWalletQuery           = "SELECT BALANCE, VERSION FROM WALLETS WHERE LOGIN_ID = ?"
+	CreateWalletQuery         = "INSERT INTO WALLETS (LOGIN_ID, BALANCE, VERSION) VALUES (?, ?, 1)"
+	UpdateWalletQuery         = "UPDATE WALLETS SET BALANCE = ?, VERSION = VERSION + 1 WHERE LOGIN_ID = ? AND VERSION = ?"
+	InsertWalletMovementQuery = "INSERT INTO WALLET_MOVEMENTS (LOGIN_ID, REFERENCE, AMOUNT, SOURCE, CREATED_AT) VALUES (?, ?, ?, ?, ?)"
+	FindWalletMovementQuery   = "SELECT AMOUNT, SOURCE FROM WALLET_MOVEMENTS WHERE LOGIN_ID = ? AND REFERENCE = ?"
+)
+
+var (
+	CreateRateLimitBucketQuery         = "INSERT IGNORE INTO RATE_LIMIT_BUCKETS (BUCKET_KEY, TOKENS, UPDATED_AT, EXPIRES_AT) VALUES (?, 0, NULL, ?)"
+	LockRateLimitBucketQuery           = "SELECT TOKENS, UPDATED_AT, EXPIRES_AT FROM RATE_LIMIT_BUCKETS WHERE BUCKET_KEY = ? FOR UPDATE"
+	UpdateRateLimitBucketQuery         = "UPDATE RATE_LIMIT_BUCKETS SET TOKENS = ?, UPDATED_AT = ?, EXPIRES_AT = ? WHERE BUCKET_KEY = ?"
+	DeleteExpiredRateLimitBucketsQuery = "DELETE FROM RATE_LIMIT_BUCKETS WHERE EXPIRES_AT <= ? LIMIT 1000"
+)
+
+var (
+	IsMessageProcessedQuery   = "SELECT COUNT(1) FROM PROCESSED_MESSAGES WHERE MESSAGE_ID = ?"
+	MarkMessageProcessedQuery = "INSERT IGNORE INTO PROCESSED_MESSAGES (MESSAGE_ID, PROCESSED_AT) VALUES (?, ?)"
+)
+
+var (
+	ListClassesQuery = "SELECT CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS FROM CHARACTER_CLASSES ORDER BY CLASS_ID"
+	SaveClassQuery   = "INSERT INTO CHARACTER_CLASSES (CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS, UPDATED_AT) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE DISPLAY_NAME = VALUES(DISPLAY_NAME), STARTING_ITEMS = VALUES(STARTING_ITEMS), BASE_STATS = VALUES(BASE_STATS), UPDATED_AT = VALUES(UPDATED_AT)"
 )
@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+)
+
+// rateLimitPurgeInterval bounds how often each replica deletes the buckets
+// that filled up again. A deleted bucket behaves as a full one.
+const rateLimitPurgeInterval = time.Minute
+
+// RateLimitRepository shares the token buckets between every replica using
+// the database. A bucket row is locked while a token is taken from it.
+type RateLimitRepository struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	nextPurge time.Time
+}
+
+func NewRateLimitRepository(db *sql.DB) *RateLimitRepository {
+	return &RateLimitRepository{
+		db: db,
+	}
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	now = now.UTC()
+	if err := r.purge(ctx, now); err != nil {
+		return ratelimit.Decision{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("error starting rate limit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the first request of a key creates its row, so that concurrent first
+	// requests all lock the same row below
+	if _, err := tx.ExecContext(ctx, CreateRateLimitBucketQuery, key, now); err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("error creating rate limit bucket: %w", err)
+	}
+
+	var (
+		bucket    ratelimit.Bucket
+		updatedAt sql.NullTime
+		expiresAt time.Time
+	)
+	row := tx.QueryRowContext(ctx, LockRateLimitBucketQuery, key)
+	if err := row.Scan(&bucket.Tokens, &updatedAt, &expiresAt); err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("error loading rate limit bucket: %w", err)
+	}
+	if updatedAt.Valid && expiresAt.After(now) {
+		bucket.UpdatedAt = updatedAt.Time
+	}
+
+	bucket, decision := limit.Take(bucket, now)
+	if _, err := tx.ExecContext(ctx, UpdateRateLimitBucketQuery, bucket.Tokens, bucket.UpdatedAt, now.Add(decision.Reset), key); err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("error updating rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("error committing rate limit transaction: %w", err)
+	}
+	return decision, nil
+}
+
+func (r *RateLimitRepository) purge(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	due := now.After(r.nextPurge)
+	if due {
+		r.nextPurge = now.Add(rateLimitPurgeInterval)
+	}
+	r.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, DeleteExpiredRateLimitBucketsQuery, now); err != nil {
+		return fmt.Errorf("error purging rate limit buckets: %w", err)
+	}
+	return nil
+}
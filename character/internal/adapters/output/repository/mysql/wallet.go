@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type WalletRepository struct {
+	db *sql.DB
+}
+
+func NewWalletRepository(db *sql.DB) *WalletRepository {
+	return &WalletRepository{
+		db: db,
+	}
+}
+
+func (r *WalletRepository) FindWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error) {
+	var balance, version int
+	err := r.db.QueryRowContext(ctx, FindWalletQuery, loginId.ID().String()).Scan(&balance, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrWalletNotFound, loginId.ID())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading wallet: %w", err)
+	}
+	return wallet.Restore(loginId, balance, version), nil
+}
+
+func (r *WalletRepository) SaveWallet(ctx context.Context, w wallet.Wallet) error {
+	id := w.LoginID().ID().String()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting wallet transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if w.Version() == 0 {
+		_, err := tx.ExecContext(ctx, CreateWalletQuery, id, w.Balance())
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("%w: %s", repository.ErrConcurrentWalletUpdate, id)
+		}
+		if err != nil {
+			return fmt.Errorf("error creating wallet: %w", err)
+		}
+	} else {
+		result, err := tx.ExecContext(ctx, UpdateWalletQuery, w.Balance(), id, w.Version())
+		if err != nil {
+			return fmt.Errorf("error updating wallet: %w", err)
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error updating wallet: %w", err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: %s", repository.ErrConcurrentWalletUpdate, id)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, movement := range w.PendingMovements() {
+		_, err := tx.ExecContext(ctx, InsertWalletMovementQuery, id, movement.Reference, movement.Amount, movement.Source, now)
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("%w: %s", wallet.ErrDuplicateReference, movement.Reference)
+		}
+		if err != nil {
+			return fmt.Errorf("error saving cash movement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing wallet transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *WalletRepository) FindMovement(ctx context.Context, loginId login.LoginID, reference string) (*wallet.Movement, error) {
+	movement := wallet.Movement{Reference: reference}
+	err := r.db.QueryRowContext(ctx, FindWalletMovementQuery, loginId.ID().String(), reference).Scan(&movement.Amount, &movement.Source)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrMovementNotFound, reference)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading cash movement: %w", err)
+	}
+	return &movement, nil
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *driver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// characterViewsProjection names the checkpoint row of the character read
+// model in PROJECTION_CHECKPOINTS.
+const characterViewsProjection = "character_views"
+
+type CharacterViewRepository struct {
+	db *sql.DB
+}
+
+func NewCharacterViewRepository(db *sql.DB) *CharacterViewRepository {
+	return &CharacterViewRepository{
+		db: db,
+	}
+}
+
+func (c *CharacterViewRepository) FindCharacterView(ctx context.Context, characterId uuid.UUID) (*repository.CharacterView, error) {
+	ids := uuidParser{}
+	view, err := scanCharacterView(c.db.QueryRowContext(ctx, FindCharacterViewQuery, characterId.String()), &ids)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCharacterNotFound, characterId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading character view: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, FindItemViewsQuery, characterId.String())
+	if err != nil {
+		return nil, fmt.Errorf("error loading item views: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item                 repository.ItemView
+			playerItemId, itemId string
+		)
+		if err := rows.Scan(&playerItemId, &itemId, &item.Description, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("error reading item view: %w", err)
+		}
+		item.PlayerItemID = ids.parse(playerItemId)
+		item.ItemID = ids.parse(itemId)
+		view.Items = append(view.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading item views: %w", err)
+	}
+
+	if err := errors.Join(ids.errs...); err != nil {
+		return nil, fmt.Errorf("%w: character view %s: %w", dao.ErrCorruptedRow, characterId, err)
+	}
+	return view, nil
+}
+
+func (c *CharacterViewRepository) SaveCharacterView(ctx context.Context, view repository.CharacterView) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting view transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	characterId := view.CharacterID.String()
+	_, err = tx.ExecContext(ctx, UpsertCharacterViewQuery,
+		characterId, view.LoginID.String(), view.Nickname, view.Class, view.InventoryID.String(),
+		view.GuildID.String(), view.VaultID.String(), view.Level, view.Gold, view.Position,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving character view: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, DeleteItemViewsQuery, characterId); err != nil {
+		return fmt.Errorf("error replacing item views: %w", err)
+	}
+	for _, item := range view.Items {
+		_, err := tx.ExecContext(ctx, InsertItemViewQuery, characterId, item.PlayerItemID.String(), item.ItemID.String(), item.Description, item.Quantity)
+		if err != nil {
+			return fmt.Errorf("error saving item view: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, SaveCheckpointQuery, characterViewsProjection, view.Position); err != nil {
+		return fmt.Errorf("error saving projection checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing view transaction: %w", err)
+	}
+	return nil
+}
+
+func (c *CharacterViewRepository) SearchCharacterViews(ctx context.Context, search repository.CharacterSearch) ([]repository.CharacterView, error) {
+	query, args := searchCharacterViewsQuery(search)
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching character views: %w", err)
+	}
+	defer rows.Close()
+
+	ids := uuidParser{}
+	var views []repository.CharacterView
+	for rows.Next() {
+		view, err := scanCharacterView(rows, &ids)
+		if err != nil {
+			return nil, fmt.Errorf("error reading character view: %w", err)
+		}
+		views = append(views, *view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading character views: %w", err)
+	}
+
+	if err := errors.Join(ids.errs...); err != nil {
+		return nil, fmt.Errorf("%w: character views: %w", dao.ErrCorruptedRow, err)
+	}
+	return views, nil
+}
+
+// searchCharacterViewsQuery pages through the views with the keyset of the
+// sort, so every page costs the same however deep it is.
+func searchCharacterViewsQuery(search repository.CharacterSearch) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	// arg appends value and returns its placeholder
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if search.NicknamePrefix != "" {
+		conditions = append(conditions, "NICKNAME_KEY LIKE "+arg(likeEscaper.Replace(strings.ToLower(search.NicknamePrefix))+"%")+" ESCAPE '!'")
+	}
+	if search.Class != "" {
+		conditions = append(conditions, "CLASS = "+arg(search.Class))
+	}
+	if search.GuildID != uuid.Nil {
+		conditions = append(conditions, "GUILD_ID = "+arg(search.GuildID.String()))
+	}
+	if search.MinLevel != 0 {
+		conditions = append(conditions, "LEVEL >= "+arg(search.MinLevel))
+	}
+	if search.MaxLevel != 0 {
+		conditions = append(conditions, "LEVEL <= "+arg(search.MaxLevel))
+	}
+
+	column, direction, after := "NICKNAME_KEY", "ASC", ">"
+	if search.Sort == repository.SortByGold {
+		column = "GOLD_AMOUNT"
+	}
+	if search.Descending {
+		direction, after = "DESC", "<"
+	}
+	if search.After != nil {
+		var key any = strings.ToLower(search.After.Nickname)
+		if search.Sort == repository.SortByGold {
+			key = search.After.Gold
+		}
+		keyArg := arg(key)
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s %[3]s OR (%[1]s = %[3]s AND CHARACTER_ID %[2]s %[4]s))", column, after, keyArg, arg(search.After.CharacterID.String())))
+	}
+
+	query := SearchCharacterViewsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %[1]s %[2]s, CHARACTER_ID %[2]s LIMIT %[3]s", column, direction, arg(search.Limit))
+	return query, args
+}
+
+// likeEscaper escapes the LIKE wildcards with the ! escape character.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func scanCharacterView(row interface{ Scan(dest ...any) error }, ids *uuidParser) (*repository.CharacterView, error) {
+	var (
+		view                                       repository.CharacterView
+		id, loginId, inventoryId, guildId, vaultId string
+	)
+	err := row.Scan(&id, &loginId, &view.Nickname, &view.Class, &inventoryId, &guildId, &vaultId, &view.Level, &view.Gold, &view.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	view.CharacterID = ids.parse(id)
+	view.LoginID = ids.parse(loginId)
+	view.InventoryID = ids.parse(inventoryId)
+	view.GuildID = ids.parse(guildId)
+	view.VaultID = ids.parse(vaultId)
+	return &view, nil
+}
+
+func (c *CharacterViewRepository) Checkpoint(ctx context.Context) (int64, error) {
+	var position int64
+	err := c.db.QueryRowContext(ctx, FindCheckpointQuery, characterViewsProjection).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading projection checkpoint: %w", err)
+	}
+	return position, nil
+}
+
+func (c *CharacterViewRepository) SaveCheckpoint(ctx context.Context, position int64) error {
+	if _, err := c.db.ExecContext(ctx, SaveCheckpointQuery, characterViewsProjection, position); err != nil {
+		return fmt.Errorf("error saving projection checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (c *CharacterViewRepository) Reset(ctx context.Context) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting reset transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{DeleteAllItemViewsQuery, DeleteAllCharacterViewsQuery} {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("error dropping character views: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, DeleteCheckpointQuery, characterViewsProjection); err != nil {
+		return fmt.Errorf("error dropping projection checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing reset transaction: %w", err)
+	}
+	return nil
+}
+
+type uuidParser struct {
+	errs []error
+}
+
+func (p *uuidParser) parse(value string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%q: %w", value, err))
+	}
+	return id
+}
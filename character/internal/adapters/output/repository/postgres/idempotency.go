@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// IdempotencyRepository tells expired keys apart with clock, the one the
+// middleware computes the expiry with.
+type IdempotencyRepository struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+func NewIdempotencyRepository(db *sql.DB, clock clock.Clock) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (i *IdempotencyRepository) Find(ctx context.Context, key string) (*repository.IdempotencyRecord, error) {
+	var (
+		record      repository.IdempotencyRecord
+		contentType sql.NullString
+		body        []byte
+	)
+
+	row := i.db.QueryRowContext(ctx, FindIdempotencyKeyQuery, key, i.clock.Now().UTC())
+	err := row.Scan(&record.Key, &record.Fingerprint, &record.StatusCode, &contentType, &body, &record.Completed, &record.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding idempotency key: %w", err)
+	}
+
+	record.ContentType = contentType.String
+	record.Body = body
+	return &record, nil
+}
+
+func (i *IdempotencyRepository) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) error {
+	if _, err := i.db.ExecContext(ctx, DeleteExpiredIdempotencyKeyQuery, key, i.clock.Now().UTC()); err != nil {
+		return fmt.Errorf("error purging expired idempotency key: %w", err)
+	}
+
+	_, err := i.db.ExecContext(ctx, ReserveIdempotencyKeyQuery, key, fingerprint, expiresAt.UTC())
+	if isUniqueViolation(err) {
+		return repository.ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		return fmt.Errorf("error reserving idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	if _, err := i.db.ExecContext(ctx, CompleteIdempotencyKeyQuery, statusCode, contentType, body, key); err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (i *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	if _, err := i.db.ExecContext(ctx, ReleaseIdempotencyKeyQuery, key); err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+	return nil
+}
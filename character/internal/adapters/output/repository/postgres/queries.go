@@ -0,0 +1,78 @@
+package postgres
+
+var (
+	CreateNewInventoryQuery  = "INSERT INTO INVENTORIES (INVENTORY_ID, GOLD_AMOUNT) VALUES ($1, $2)"
+	CreateNewCharacterQuery  = "INSERT INTO CHARACTERS (CHARACTER_ID, LOGIN_ID, NICKNAME, NICKNAME_SKELETON, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, STATUS, SUSPENDED_UNTIL, STATUS_REASON, VERSION) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
+	CreateNewPlayerItemQuery = "INSERT INTO PLAYER_ITEMS (PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY, INVENTORY_ID) VALUES ($1, $2, $3, $4, $5)"
+	FindCharacterByIdQuery   = "SELECT c.CHARACTER_ID, c.LOGIN_ID, c.NICKNAME, c.CLASS, c.INVENTORY_ID, c.GUILD_ID, c.VAULT_ID, c.LEVEL, c.STATUS, c.SUSPENDED_UNTIL, c.STATUS_REASON, c.VERSION, i.GOLD_AMOUNT FROM CHARACTERS c JOIN INVENTORIES i ON i.INVENTORY_ID = c.INVENTORY_ID WHERE c.CHARACTER_ID = $1"
+	FindPlayerItemsQuery     = "SELECT PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY FROM PLAYER_ITEMS WHERE INVENTORY_ID = $1"
+	CharacterExistsQuery     = "SELECT COUNT(1) FROM CHARACTERS WHERE CHARACTER_ID = $1"
+	UpdateCharacterQuery     = "UPDATE CHARACTERS SET NICKNAME = $1, CLASS = $2, GUILD_ID = $3, VAULT_ID = $4, LEVEL = $5, STATUS = $6, SUSPENDED_UNTIL = $7, STATUS_REASON = $8, VERSION = VERSION + 1 WHERE CHARACTER_ID = $9 AND VERSION = $10"
+	UpdateInventoryQuery     = "UPDATE INVENTORIES SET GOLD_AMOUNT = $1 WHERE INVENTORY_ID = $2"
+	DeletePlayerItemsQuery   = "DELETE FROM PLAYER_ITEMS WHERE INVENTORY_ID = $1"
+	CharactersWithGoldQuery  = "SELECT c.CHARACTER_ID FROM CHARACTERS c JOIN INVENTORIES i ON i.INVENTORY_ID = c.INVENTORY_ID WHERE i.GOLD_AMOUNT <> 0 ORDER BY c.CHARACTER_ID"
+	ExpiredSuspensionsQuery  = "SELECT CHARACTER_ID FROM CHARACTERS WHERE STATUS = 'SUSPENDED' AND SUSPENDED_UNTIL <= $1 ORDER BY SUSPENDED_UNTIL, CHARACTER_ID LIMIT $2"
+)
+
+var (
+	AppendCharacterEventQuery = "INSERT INTO CHARACTER_EVENTS (CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT) VALUES ($1, $2, $3, $4)"
+	ReadCharacterEventsQuery  = "SELECT POSITION, CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT FROM CHARACTER_EVENTS WHERE POSITION > $1 ORDER BY POSITION LIMIT $2"
+	LastEventPositionQuery    = "SELECT COALESCE(MAX(POSITION), 0) FROM CHARACTER_EVENTS"
+)
+
+// inventoryEventTypes lists the events that change an inventory.
+const inventoryEventTypes = "('ItemAdded', 'ItemDropped', 'GoldAdded', 'GoldWithdrawn')"
+
+var (
+	InventoryHistoryQuery = "SELECT POSITION, CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT FROM CHARACTER_EVENTS WHERE CHARACTER_ID = $1 AND EVENT_TYPE IN " + inventoryEventTypes + " AND ($2::TIMESTAMPTZ IS NULL OR OCCURRED_AT >= $2) AND ($3::TIMESTAMPTZ IS NULL OR OCCURRED_AT < $3) AND ($4 = '' OR PAYLOAD->>'playerItemId' = $4 OR PAYLOAD->>'itemId' = $4) ORDER BY POSITION"
+)
+
+var (
+	FindCharacterViewQuery       = "SELECT CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION FROM CHARACTER_VIEWS WHERE CHARACTER_ID = $1"
+	SearchCharacterViewsQuery    = "SELECT CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION FROM CHARACTER_VIEWS"
+	FindItemViewsQuery           = "SELECT PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY FROM CHARACTER_ITEM_VIEWS WHERE CHARACTER_ID = $1 ORDER BY PLAYER_ITEM_ID"
+	UpsertCharacterViewQuery     = "INSERT INTO CHARACTER_VIEWS (CHARACTER_ID, LOGIN_ID, NICKNAME, CLASS, INVENTORY_ID, GUILD_ID, VAULT_ID, LEVEL, GOLD_AMOUNT, POSITION) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT (CHARACTER_ID) DO UPDATE SET LOGIN_ID = EXCLUDED.LOGIN_ID, NICKNAME = EXCLUDED.NICKNAME, CLASS = EXCLUDED.CLASS, INVENTORY_ID = EXCLUDED.INVENTORY_ID, GUILD_ID = EXCLUDED.GUILD_ID, VAULT_ID = EXCLUDED.VAULT_ID, LEVEL = EXCLUDED.LEVEL, GOLD_AMOUNT = EXCLUDED.GOLD_AMOUNT, POSITION = EXCLUDED.POSITION"
+	InsertItemViewQuery          = "INSERT INTO CHARACTER_ITEM_VIEWS (CHARACTER_ID, PLAYER_ITEM_ID, ITEM_ID, DESCRIPTION, QUANTITY) VALUES ($1, $2, $3, $4, $5)"
+	DeleteItemViewsQuery         = "DELETE FROM CHARACTER_ITEM_VIEWS WHERE CHARACTER_ID = $1"
+	DeleteAllItemViewsQuery      = "DELETE FROM CHARACTER_ITEM_VIEWS"
+	DeleteAllCharacterViewsQuery = "DELETE FROM CHARACTER_VIEWS"
+	FindCheckpointQuery          = "SELECT POSITION FROM PROJECTION_CHECKPOINTS WHERE PROJECTION = $1"
+	SaveCheckpointQuery          = "INSERT INTO PROJECTION_CHECKPOINTS (PROJECTION, POSITION) VALUES ($1, $2) ON CONFLICT (PROJECTION) DO UPDATE SET POSITION = EXCLUDED.POSITION"
+	DeleteCheckpointQuery        = "DELETE FROM PROJECTION_CHECKPOINTS WHERE PROJECTION = $1"
+)
+
+var (
+	FindIdempotencyKeyQuery          = "SELECT IDEMPOTENCY_KEY, FINGERPRINT, STATUS_CODE, CONTENT_TYPE, RESPONSE_BODY, COMPLETED, EXPIRES_AT FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = $1 AND EXPIRES_AT > $2"
+	DeleteExpiredIdempotencyKeyQuery = "DELETE FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = $1 AND EXPIRES_AT <= $2"
+	ReserveIdempotencyKeyQuery       = "INSERT INTO IDEMPOTENCY_KEYS (IDEMPOTENCY_KEY, FINGERPRINT, STATUS_CODE, COMPLETED, EXPIRES_AT) VALUES ($1, $2, 0, FALSE, $3)"
+	CompleteIdempotencyKeyQuery      = "UPDATE IDEMPOTENCY_KEYS SET STATUS_CODE = $1, CONTENT_TYPE = $2, RESPONSE_BODY = $3, COMPLETED = TRUE WHERE IDEMPOTENCY_KEY = $4"
+	ReleaseIdempotencyKeyQuery       = "DELETE FROM IDEMPOTENCY_KEYS WHERE IDEMPOTENCY_KEY = $1"
+)
+
+var (
+	InsertLedgerTransactionQuery = "INSERT INTO LEDGER_TRANSACTIONS (TRANSACTION_ID, REASON, OCCURRED_AT) VALUES ($1, $2, $3)"
+	InsertLedgerEntryQuery       = "INSERT INTO LEDGER_ENTRIES (TRANSACTION_ID, ACCOUNT_ID, AMOUNT) VALUES ($1, $2, $3)"
+	MoveLedgerBalanceQuery       = "INSERT INTO LEDGER_BALANCES (ACCOUNT_ID, ACCOUNT_KIND, BALANCE) VALUES ($1, $2, $3) ON CONFLICT (ACCOUNT_ID) DO UPDATE SET BALANCE = LEDGER_BALANCES.BALANCE + EXCLUDED.BALANCE"
+	FindLedgerTransactionQuery   = "SELECT REASON, OCCURRED_AT FROM LEDGER_TRANSACTIONS WHERE TRANSACTION_ID = $1"
+	FindLedgerEntriesQuery       = "SELECT ACCOUNT_ID, AMOUNT FROM LEDGER_ENTRIES WHERE TRANSACTION_ID = $1"
+	FindLedgerBalanceQuery       = "SELECT BALANCE FROM LEDGER_BALANCES WHERE ACCOUNT_ID = $1"
+	FindLedgerBalancesQuery      = "SELECT ACCOUNT_ID, BALANCE FROM LEDGER_BALANCES WHERE ACCOUNT_KIND = $1 ORDER BY ACCOUNT_ID"
+)
+
+var (
+	FindWalletQuery           = "SELECT BALANCE, VERSION FROM WALLETS WHERE LOGIN_ID = $1"
+	CreateWalletQuery         = "INSERT INTO WALLETS (LOGIN_ID, BALANCE, VERSION) VALUES ($1, $2, 1)"
+	UpdateWalletQuery         = "UPDATE WALLETS SET BALANCE = $1, VERSION = VERSION + 1 WHERE LOGIN_ID = $2 AND VERSION = $3"
+	InsertWalletMovementQuery = "INSERT INTO WALLET_MOVEMENTS (LOGIN_ID, REFERENCE, AMOUNT, SOURCE, CREATED_AT) VALUES ($1, $2, $3, $4, $5)"
+	FindWalletMovementQuery   = "SELECT AMOUNT, SOURCE FROM WALLET_MOVEMENTS WHERE LOGIN_ID = $1 AND REFERENCE = $2"
+)
+
+var (
+	IsMessageProcessedQuery   = "SELECT COUNT(1) FROM PROCESSED_MESSAGES WHERE MESSAGE_ID = $1"
+	MarkMessageProcessedQuery = "INSERT INTO PROCESSED_MESSAGES (MESSAGE_ID, PROCESSED_AT) VALUES ($1, $2) ON CONFLICT (MESSAGE_ID) DO NOTHING"
+)
+
+var (
+	ListClassesQuery = "SELECT CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS FROM CHARACTER_CLASSES ORDER BY CLASS_ID"
+	SaveClassQuery   = "INSERT INTO CHARACTER_CLASSES (CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS, UPDATED_AT) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (CLASS_ID) DO UPDATE SET DISPLAY_NAME = EXCLUDED.DISPLAY_NAME, STARTING_ITEMS = EXCLUDED.STARTING_ITEMS, BASE_STATS = EXCLUDED.BASE_STATS, UPDATED_AT = EXCLUDED.UPDATED_AT"
+)
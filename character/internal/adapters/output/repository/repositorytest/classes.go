@@ -0,0 +1,84 @@
+package repositorytest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// ClassRepositoryContract runs the class catalog contract against the
+// repository built by newClasses. Every subtest defines classes of its own
+// and ignores the others, so the storage may be shared and seeded.
+func ClassRepositoryContract(t *testing.T, newClasses func(t *testing.T) repository.ClassRepository) {
+	t.Run("save and list", func(t *testing.T) {
+		classes := newClasses(t)
+		ctx := context.Background()
+
+		saved := newClassDefinition(t, "Paladin", 1)
+		require.NoError(t, classes.SaveClass(ctx, saved))
+
+		listed, err := classes.ListClasses(ctx)
+		require.NoError(t, err)
+		loaded, ok := findClass(listed, saved.Class)
+		require.True(t, ok)
+		assert.Equal(t, saved, loaded)
+	})
+
+	t.Run("save replaces the definition", func(t *testing.T) {
+		classes := newClasses(t)
+		ctx := context.Background()
+
+		first := newClassDefinition(t, "Paladin", 1)
+		require.NoError(t, classes.SaveClass(ctx, first))
+		replaced, err := class.NewDefinition(first.Class.String(), "Holy Knight", nil, class.Stats{"faith": 9})
+		require.NoError(t, err)
+		require.NoError(t, classes.SaveClass(ctx, replaced))
+
+		listed, err := classes.ListClasses(ctx)
+		require.NoError(t, err)
+		loaded, ok := findClass(listed, first.Class)
+		require.True(t, ok)
+		assert.Equal(t, "Holy Knight", loaded.DisplayName)
+		assert.Empty(t, loaded.StartingItems)
+		assert.Equal(t, class.Stats{"faith": 9}, loaded.Stats)
+	})
+
+	t.Run("list is ordered by class id", func(t *testing.T) {
+		classes := newClasses(t)
+
+		listed, err := classes.ListClasses(context.Background())
+		require.NoError(t, err)
+		for i := 1; i < len(listed); i++ {
+			assert.Less(t, listed[i-1].Class.String(), listed[i].Class.String())
+		}
+	})
+}
+
+// newClassDefinition defines a class with a unique id, holding the given
+// quantity of a starting item.
+func newClassDefinition(t *testing.T, displayName string, quantity int) class.Definition {
+	t.Helper()
+	id := "TEST_" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:16])
+	definition, err := class.NewDefinition(id, displayName,
+		[]class.StartingItem{{Item: item.NewItemID(uuid.New()), Description: "Mace", Quantity: quantity}},
+		class.Stats{"strength": 6, "faith": 7},
+	)
+	require.NoError(t, err)
+	return definition
+}
+
+func findClass(definitions []class.Definition, id class.Class) (class.Definition, bool) {
+	for _, definition := range definitions {
+		if definition.Class == id {
+			return definition, true
+		}
+	}
+	return class.Definition{}, false
+}
@@ -0,0 +1,288 @@
+// Package repositorytest holds the behaviour every implementation of the
+// character repository, its event log, its inventory history and its read
+// model must show, so each adapter runs the same suite against its own
+// storage.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+const (
+	concurrentWriters = 8
+	maxAttempts       = 100
+)
+
+// CharacterRepositoryContract runs the contract against the repository built
+// by newRepository, which is called once per subtest. Characters are created
+// with fresh ids, so subtests may share the same storage.
+func CharacterRepositoryContract(t *testing.T, newRepository func(t *testing.T) repository.CharacterRepository) {
+	t.Run("save and load", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, saved.PickGold(150))
+		require.NoError(t, saved.PickItem(newItem(t, "Sword", 1)))
+		require.NoError(t, saved.PickItem(newItem(t, "Potion", 3)))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assertSameCharacter(t, saved, loaded)
+		assert.Equal(t, saved.Version(), loaded.Version())
+	})
+
+	t.Run("save rejects a taken nickname", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		// the same nickname with a Cyrillic "а" and in upper case
+		lookalike := strings.ToUpper(strings.Replace(saved.Nickname(), "a", "\u0430", 1))
+		rules, err := character.NewNicknameRules("Latin", "Cyrillic")
+		require.NoError(t, err)
+		impostor, err := character.CreateNewCharacter(class.Defaults(), rules, lookalike, login.NewLoginID(uuid.New()), class.Mage, vault.NewVaultID(uuid.New()))
+		require.NoError(t, err)
+		err = repo.Save(ctx, *impostor)
+		assert.ErrorIs(t, err, character.ErrNicknameTaken)
+
+		_, err = repo.FindCharacterById(ctx, impostor.CharacterID)
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound, "nothing of the impostor is saved")
+	})
+
+	t.Run("load missing character", func(t *testing.T) {
+		repo := newRepository(t)
+
+		_, err := repo.FindCharacterById(context.Background(), character.NewCharacterID(uuid.New()))
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound)
+	})
+
+	t.Run("update persists changes", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		sword := newItem(t, "Sword", 1)
+		require.NoError(t, saved.PickItem(sword))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, loaded.PickGold(40))
+		require.NoError(t, loaded.DropItem(sword))
+		require.NoError(t, loaded.PickItem(newItem(t, "Shield", 1)))
+		require.NoError(t, loaded.UpdateGuildInfo(guild.NewGuildID(uuid.New())))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		reloaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assertSameCharacter(t, loaded, reloaded)
+		assert.Equal(t, loaded.Version()+1, reloaded.Version(), "every update bumps the version")
+	})
+
+	t.Run("update persists the standing", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		now := time.Now().UTC().Truncate(time.Second)
+		require.NoError(t, loaded.Suspend(now.Add(time.Hour), "gold duping", now))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		suspended, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assertSameCharacter(t, loaded, suspended)
+		require.NoError(t, suspended.Ban("gold duping again"))
+		require.NoError(t, repo.Update(ctx, *suspended))
+
+		banned, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assertSameCharacter(t, suspended, banned)
+		assert.ErrorIs(t, banned.PickGold(10), character.ErrCharacterNotActive)
+	})
+
+	t.Run("update missing character", func(t *testing.T) {
+		repo := newRepository(t)
+
+		err := repo.Update(context.Background(), *newCharacter(t))
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound)
+	})
+
+	t.Run("stale update is rejected", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		first, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		second, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+
+		require.NoError(t, first.PickGold(10))
+		require.NoError(t, repo.Update(ctx, *first))
+
+		require.NoError(t, second.PickGold(20))
+		assert.ErrorIs(t, repo.Update(ctx, *second), repository.ErrConcurrentUpdate)
+
+		reloaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, gold(reloaded), "the stale write must not be applied")
+	})
+
+	t.Run("update applies a message once", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := repository.WithMessageID(context.Background(), "message-"+uuid.NewString())
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		first, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, first.PickGold(10))
+		require.NoError(t, repo.Update(ctx, *first))
+
+		redelivered, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, redelivered.PickGold(10))
+		assert.ErrorIs(t, repo.Update(ctx, *redelivered), repository.ErrMessageAlreadyProcessed)
+
+		reloaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, gold(reloaded), "the redelivered message must not be applied")
+	})
+
+	t.Run("concurrent updates do not lose writes", func(t *testing.T) {
+		repo := newRepository(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for range concurrentWriters {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- addGold(ctx, repo, saved.CharacterID, 1)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		reloaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assert.Equal(t, concurrentWriters, gold(reloaded))
+	})
+}
+
+// addGold retries the read-modify-write cycle until it wins the race.
+func addGold(ctx context.Context, repo repository.CharacterRepository, id character.CharacterID, amount int) error {
+	for range maxAttempts {
+		c, err := repo.FindCharacterById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := c.PickGold(amount); err != nil {
+			return err
+		}
+
+		err = repo.Update(ctx, *c)
+		if !errors.Is(err, repository.ErrConcurrentUpdate) {
+			return err
+		}
+	}
+	return repository.ErrConcurrentUpdate
+}
+
+// InventoryPersistenceSwitchContract checks that characters written while
+// inventories are event sourced read the same once persistence is switched
+// back to the inventory state. Both repositories must share one storage.
+func InventoryPersistenceSwitchContract(t *testing.T, eventSourced, state repository.CharacterRepository) {
+	ctx := context.Background()
+
+	saved := newCharacter(t)
+	require.NoError(t, saved.PickGold(150))
+	require.NoError(t, eventSourced.Save(ctx, *saved))
+
+	updated, err := eventSourced.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	require.NoError(t, updated.PickItem(newItem(t, "Sword", 1)))
+	require.NoError(t, updated.DropGold(50))
+	require.NoError(t, eventSourced.Update(ctx, *updated))
+
+	loaded, err := state.FindCharacterById(ctx, saved.CharacterID)
+	require.NoError(t, err)
+	assertSameCharacter(t, updated, loaded)
+}
+
+func newCharacter(t *testing.T) *character.Character {
+	t.Helper()
+	// nicknames are unique, and the storage may be shared
+	nickname := "Arthas" + uuid.NewString()[:8]
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), nickname, login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	return c
+}
+
+func newItem(t *testing.T, description string, quantity int) playeritem.PlayerItem {
+	t.Helper()
+	i, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), description, quantity)
+	require.NoError(t, err)
+	return *i
+}
+
+func assertSameCharacter(t *testing.T, expected, actual *character.Character) {
+	t.Helper()
+	expectedInventory, actualInventory := expected.Inventory(), actual.Inventory()
+
+	assert.Equal(t, expected.ID(), actual.ID(), "character id")
+	assert.Equal(t, expected.LoginID().ID(), actual.LoginID().ID(), "login id")
+	assert.Equal(t, expected.Nickname(), actual.Nickname())
+	assert.Equal(t, expected.Class(), actual.Class())
+	assert.Equal(t, expected.GetCurrentGuild().ID(), actual.GetCurrentGuild().ID(), "guild id")
+	assert.Equal(t, expected.GetCurrentVaultId().ID(), actual.GetCurrentVaultId().ID(), "vault id")
+	assert.Equal(t, expectedInventory.ID(), actualInventory.ID(), "inventory id")
+	assert.Equal(t, expectedInventory.GetCurrentGold(), actualInventory.GetCurrentGold())
+	assert.Equal(t, expectedInventory.Items(), actualInventory.Items())
+
+	expectedStanding, actualStanding := expected.Standing(), actual.Standing()
+	assert.Equal(t, expectedStanding.Status, actualStanding.Status, "status")
+	assert.Equal(t, expectedStanding.Reason, actualStanding.Reason, "status reason")
+	assert.True(t, expectedStanding.SuspendedUntil.Equal(actualStanding.SuspendedUntil), "suspended until %s, got %s", expectedStanding.SuspendedUntil, actualStanding.SuspendedUntil)
+}
+
+func gold(c *character.Character) int {
+	inventory := c.Inventory()
+	return inventory.GetCurrentGold()
+}
@@ -0,0 +1,130 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// CharacterEventLogContract checks that the events recorded by the aggregate
+// reach the event log through Save and Update. newStore returns the
+// repository and the event log reading the same storage.
+func CharacterEventLogContract(t *testing.T, newStore func(t *testing.T) (repository.CharacterRepository, repository.CharacterEventLog)) {
+	t.Run("save appends the pending events", func(t *testing.T) {
+		repo, log := newStore(t)
+		ctx := context.Background()
+		before := lastPosition(t, log)
+
+		saved := newCharacter(t)
+		require.NoError(t, saved.PickGold(150))
+		require.NoError(t, saved.PickItem(newItem(t, "Sword", 1)))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		recorded := eventsOf(t, log, before, saved.CharacterID)
+		assert.Equal(t, saved.PendingEvents(), events(recorded))
+		for i, event := range recorded {
+			assert.Greater(t, event.Position, before, "positions only grow")
+			assert.False(t, event.OccurredAt.IsZero())
+			if i > 0 {
+				assert.Greater(t, event.Position, recorded[i-1].Position)
+			}
+		}
+		assert.GreaterOrEqual(t, lastPosition(t, log), recorded[len(recorded)-1].Position)
+	})
+
+	t.Run("update appends only the new events", func(t *testing.T) {
+		repo, log := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		sword := newItem(t, "Sword", 1)
+		require.NoError(t, saved.PickItem(sword))
+		require.NoError(t, repo.Save(ctx, *saved))
+		before := lastPosition(t, log)
+
+		loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.PendingEvents(), "loading records no event")
+		require.NoError(t, loaded.DropItem(sword))
+		require.NoError(t, loaded.PickGold(30))
+		require.NoError(t, loaded.DepositGold(20, loaded.GetCurrentVaultId()))
+		require.NoError(t, loaded.DropGold(5))
+		require.NoError(t, loaded.UpdateGuildInfo(guild.NewGuildID(uuid.New())))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		assert.Equal(t, loaded.PendingEvents(), events(eventsOf(t, log, before, saved.CharacterID)), "deposits keep their vault")
+	})
+
+	t.Run("rejected update appends nothing", func(t *testing.T) {
+		repo, log := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+		stale, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, addGold(ctx, repo, saved.CharacterID, 5))
+		before := lastPosition(t, log)
+
+		require.NoError(t, stale.PickGold(20))
+		require.ErrorIs(t, repo.Update(ctx, *stale), repository.ErrConcurrentUpdate)
+
+		assert.Empty(t, eventsOf(t, log, before, saved.CharacterID))
+	})
+
+	t.Run("read honours the limit", func(t *testing.T) {
+		repo, log := newStore(t)
+		ctx := context.Background()
+		before := lastPosition(t, log)
+
+		saved := newCharacter(t)
+		require.NoError(t, saved.PickGold(1))
+		require.NoError(t, saved.PickGold(2))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		page, err := log.ReadEvents(ctx, before, 2)
+		require.NoError(t, err)
+		assert.Len(t, page, 2)
+	})
+}
+
+// eventsOf reads the whole log after position and keeps the events of id, as
+// other tests may be writing to the same storage.
+func eventsOf(t *testing.T, log repository.CharacterEventLog, after int64, id character.CharacterID) []repository.RecordedEvent {
+	t.Helper()
+	var found []repository.RecordedEvent
+	for {
+		page, err := log.ReadEvents(context.Background(), after, 100)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			return found
+		}
+		for _, event := range page {
+			if event.Event.AggregateID().ID() == id.ID() {
+				found = append(found, event)
+			}
+		}
+		after = page[len(page)-1].Position
+	}
+}
+
+func events(recorded []repository.RecordedEvent) []character.Event {
+	var list []character.Event
+	for _, r := range recorded {
+		list = append(list, r.Event)
+	}
+	return list
+}
+
+func lastPosition(t *testing.T, log repository.CharacterEventLog) int64 {
+	t.Helper()
+	position, err := log.LastPosition(context.Background())
+	require.NoError(t, err)
+	return position
+}
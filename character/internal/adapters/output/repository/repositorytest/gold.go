@@ -0,0 +1,40 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// CharacterGoldContract runs the contract against the repository and gold
+// finder built by newRepository. Other characters may share the storage, so
+// it only asserts on the characters it saves itself.
+func CharacterGoldContract(t *testing.T, newRepository func(t *testing.T) (repository.CharacterRepository, repository.CharacterGold)) {
+	t.Run("characters with gold", func(t *testing.T) {
+		repo, gold := newRepository(t)
+		ctx := context.Background()
+
+		rich := newCharacter(t)
+		require.NoError(t, rich.PickGold(40))
+		require.NoError(t, repo.Save(ctx, *rich))
+		spent := newCharacter(t)
+		require.NoError(t, spent.PickGold(10))
+		require.NoError(t, repo.Save(ctx, *spent))
+		poor := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *poor))
+
+		loaded, err := repo.FindCharacterById(ctx, spent.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, loaded.DropGold(10))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		holding, err := gold.CharactersWithGold(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, holding, rich.CharacterID)
+		assert.NotContains(t, holding, spent.CharacterID, "gold spent since is not listed")
+		assert.NotContains(t, holding, poor.CharacterID)
+	})
+}
@@ -0,0 +1,117 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// InventoryHistoryContract checks the audit trail of the inventories.
+// newStore returns the repository and the history reading the same storage.
+func InventoryHistoryContract(t *testing.T, newStore func(t *testing.T) (repository.CharacterRepository, repository.InventoryHistory)) {
+	t.Run("history lists the inventory events oldest first", func(t *testing.T) {
+		repo, history := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		sword := newItem(t, "Sword", 1)
+		require.NoError(t, saved.PickGold(50))
+		require.NoError(t, saved.PickItem(sword))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		loaded, err := repo.FindCharacterById(ctx, saved.CharacterID)
+		require.NoError(t, err)
+		require.NoError(t, loaded.DropItem(sword))
+		require.NoError(t, loaded.DropGold(20))
+		require.NoError(t, loaded.UpdateGuildInfo(guild.NewGuildID(uuid.New())))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		entries, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []character.Event{
+			character.GoldAdded{Character: saved.CharacterID, Amount: 50},
+			character.ItemAdded{Character: saved.CharacterID, Item: sword},
+			character.ItemDropped{Character: saved.CharacterID, Item: sword},
+			character.GoldWithdrawn{Character: saved.CharacterID, Amount: 20},
+		}, events(entries))
+	})
+
+	t.Run("history filters by item", func(t *testing.T) {
+		repo, history := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		sword := newItem(t, "Sword", 1)
+		potion := newItem(t, "Potion", 2)
+		require.NoError(t, saved.PickItem(sword))
+		require.NoError(t, saved.PickItem(potion))
+		require.NoError(t, saved.PickGold(5))
+		require.NoError(t, saved.DropItem(sword))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		expected := []character.Event{
+			character.ItemAdded{Character: saved.CharacterID, Item: sword},
+			character.ItemDropped{Character: saved.CharacterID, Item: sword},
+		}
+
+		byPlayerItem, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{ItemID: sword.ID()})
+		require.NoError(t, err)
+		assert.Equal(t, expected, events(byPlayerItem))
+
+		byItem, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{ItemID: sword.ItemID().ID()})
+		require.NoError(t, err)
+		assert.Equal(t, expected, events(byItem))
+	})
+
+	t.Run("history filters by time range", func(t *testing.T) {
+		repo, history := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, saved.PickGold(5))
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		all, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{})
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		at := all[0].OccurredAt
+
+		inside, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{From: at, To: at.Add(time.Second)})
+		require.NoError(t, err)
+		assert.Len(t, inside, 1, "From is inclusive")
+
+		before, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{To: at})
+		require.NoError(t, err)
+		assert.Empty(t, before, "To is exclusive")
+
+		after, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{From: at.Add(time.Second)})
+		require.NoError(t, err)
+		assert.Empty(t, after)
+	})
+
+	t.Run("character without inventory events", func(t *testing.T) {
+		repo, history := newStore(t)
+		ctx := context.Background()
+
+		saved := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *saved))
+
+		entries, err := history.InventoryHistory(ctx, saved.CharacterID, repository.InventoryHistoryFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("history of missing character", func(t *testing.T) {
+		_, history := newStore(t)
+
+		_, err := history.InventoryHistory(context.Background(), character.NewCharacterID(uuid.New()), repository.InventoryHistoryFilter{})
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound)
+	})
+}
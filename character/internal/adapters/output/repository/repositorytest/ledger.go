@@ -0,0 +1,131 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// LedgerRepositoryContract runs the gold ledger contract against the
+// repository built by newLedger. Every subtest uses fresh accounts, so the
+// storage may be shared.
+func LedgerRepositoryContract(t *testing.T, newLedger func(t *testing.T) repository.LedgerRepository) {
+	t.Run("post moves the balances", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+		character := ledger.CharacterAccount(uuid.New())
+		vault := ledger.VaultAccount(uuid.New())
+
+		require.NoError(t, l.Post(ctx, *transfer(t, ledger.WorldSource, character, 100)))
+		require.NoError(t, l.Post(ctx, *transfer(t, character, vault, 30)))
+
+		assert.Equal(t, 70, balance(t, l, character))
+		assert.Equal(t, 30, balance(t, l, vault))
+	})
+
+	t.Run("post splits between accounts", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+		vault := ledger.VaultAccount(uuid.New())
+		guild := ledger.GuildAccount(uuid.New())
+
+		tx, err := ledger.NewTransaction(uuid.NewString(), "guild tax", time.Now(),
+			ledger.Entry{Account: vault, Amount: -10},
+			ledger.Entry{Account: guild, Amount: 9},
+			ledger.Entry{Account: ledger.WorldSink, Amount: 1},
+		)
+		require.NoError(t, err)
+		require.NoError(t, l.Post(ctx, *tx))
+
+		assert.Equal(t, -10, balance(t, l, vault), "balances may go negative")
+		assert.Equal(t, 9, balance(t, l, guild))
+	})
+
+	t.Run("posting the same transaction again does nothing", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+		character := ledger.CharacterAccount(uuid.New())
+
+		tx := transfer(t, ledger.WorldSource, character, 40)
+		require.NoError(t, l.Post(ctx, *tx))
+
+		retried, err := ledger.Transfer(tx.ID(), tx.Reason(), tx.OccurredAt().Add(time.Minute), ledger.WorldSource, character, 40)
+		require.NoError(t, err)
+		require.NoError(t, l.Post(ctx, *retried))
+
+		assert.Equal(t, 40, balance(t, l, character))
+	})
+
+	t.Run("reusing a transaction id for other entries is rejected", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+		character := ledger.CharacterAccount(uuid.New())
+
+		tx := transfer(t, ledger.WorldSource, character, 40)
+		require.NoError(t, l.Post(ctx, *tx))
+
+		other, err := ledger.Transfer(tx.ID(), tx.Reason(), tx.OccurredAt(), ledger.WorldSource, character, 41)
+		require.NoError(t, err)
+		assert.ErrorIs(t, l.Post(ctx, *other), repository.ErrTransactionConflict)
+
+		assert.Equal(t, 40, balance(t, l, character))
+	})
+
+	t.Run("unknown account has no balance", func(t *testing.T) {
+		l := newLedger(t)
+
+		assert.Zero(t, balance(t, l, ledger.GuildAccount(uuid.New())))
+	})
+
+	t.Run("balances lists the accounts of a kind", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+		first := ledger.CharacterAccount(uuid.New())
+		second := ledger.CharacterAccount(uuid.New())
+
+		require.NoError(t, l.Post(ctx, *transfer(t, ledger.WorldSource, first, 5)))
+		require.NoError(t, l.Post(ctx, *transfer(t, ledger.WorldSource, second, 7)))
+
+		balances, err := l.Balances(ctx, ledger.KindCharacter)
+		require.NoError(t, err)
+		assert.Contains(t, balances, repository.AccountBalance{Account: first, Balance: 5})
+		assert.Contains(t, balances, repository.AccountBalance{Account: second, Balance: 7})
+		for i, b := range balances {
+			assert.Equal(t, ledger.KindCharacter, b.Account.Kind())
+			if i > 0 {
+				assert.Less(t, balances[i-1].Account.String(), b.Account.String())
+			}
+		}
+	})
+
+	t.Run("checkpoint", func(t *testing.T) {
+		l := newLedger(t)
+		ctx := context.Background()
+
+		require.NoError(t, l.SaveCheckpoint(ctx, 12))
+
+		checkpoint, err := l.Checkpoint(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), checkpoint)
+	})
+}
+
+func transfer(t *testing.T, from, to ledger.AccountID, amount int) *ledger.Transaction {
+	t.Helper()
+	tx, err := ledger.Transfer(uuid.NewString(), "test", time.Now().UTC().Truncate(time.Second), from, to, amount)
+	require.NoError(t, err)
+	return tx
+}
+
+func balance(t *testing.T, l repository.LedgerRepository, account ledger.AccountID) int {
+	t.Helper()
+	balance, err := l.Balance(context.Background(), account)
+	require.NoError(t, err)
+	return balance
+}
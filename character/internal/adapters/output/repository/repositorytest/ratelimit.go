@@ -0,0 +1,99 @@
+package repositorytest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// RateLimitRepositoryContract runs the rate limiter store contract against
+// the repository built by newLimits. Every subtest uses fresh keys, so the
+// storage may be shared.
+func RateLimitRepositoryContract(t *testing.T, newLimits func(t *testing.T) repository.RateLimitRepository) {
+	limit := ratelimit.Limit{Requests: 3, Per: time.Minute}
+	// the stores may keep time with microsecond precision only
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	t.Run("takes up to the limit", func(t *testing.T) {
+		limits := newLimits(t)
+		key := uuid.NewString()
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			decision := take(t, limits, key, limit, now)
+			assert.True(t, decision.Allowed)
+			assert.Equal(t, remaining, decision.Remaining)
+		}
+
+		decision := take(t, limits, key, limit, now)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, 20*time.Second, decision.RetryAfter)
+		assert.Equal(t, time.Minute, decision.Reset)
+	})
+
+	t.Run("tokens are refilled over time", func(t *testing.T) {
+		limits := newLimits(t)
+		key := uuid.NewString()
+
+		for range 3 {
+			take(t, limits, key, limit, now)
+		}
+		assert.False(t, take(t, limits, key, limit, now.Add(10*time.Second)).Allowed)
+		assert.True(t, take(t, limits, key, limit, now.Add(20*time.Second)).Allowed)
+
+		decision := take(t, limits, key, limit, now.Add(time.Hour))
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 2, decision.Remaining, "an idle bucket is full again")
+	})
+
+	t.Run("keys have their own buckets", func(t *testing.T) {
+		limits := newLimits(t)
+		first, second := uuid.NewString(), uuid.NewString()
+
+		for range 3 {
+			take(t, limits, first, limit, now)
+		}
+		assert.False(t, take(t, limits, first, limit, now).Allowed)
+		assert.True(t, take(t, limits, second, limit, now).Allowed)
+	})
+
+	t.Run("concurrent takes never exceed the limit", func(t *testing.T) {
+		limits := newLimits(t)
+		key := uuid.NewString()
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			allowed int
+		)
+		for range 12 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				decision, err := limits.Take(context.Background(), key, limit, now)
+				assert.NoError(t, err)
+				if decision.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, limit.Requests, allowed)
+	})
+}
+
+func take(t *testing.T, limits repository.RateLimitRepository, key string, limit ratelimit.Limit, now time.Time) ratelimit.Decision {
+	t.Helper()
+	decision, err := limits.Take(context.Background(), key, limit, now)
+	require.NoError(t, err)
+	return decision
+}
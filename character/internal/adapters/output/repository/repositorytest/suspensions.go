@@ -0,0 +1,66 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// CharacterSuspensionsContract runs the contract against the repository and
+// suspension finder built by newRepository. Other characters may share the
+// storage, so it only asserts on the characters it suspends itself.
+func CharacterSuspensionsContract(t *testing.T, newRepository func(t *testing.T) (repository.CharacterRepository, repository.CharacterSuspensions)) {
+	t.Run("expired suspensions", func(t *testing.T) {
+		repo, suspensions := newRepository(t)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		suspend := func(until time.Time) character.CharacterID {
+			t.Helper()
+			c := newCharacter(t)
+			require.NoError(t, c.Suspend(until, "botting", now))
+			require.NoError(t, repo.Save(ctx, *c))
+			return c.CharacterID
+		}
+		endsFirst := suspend(now.Add(time.Hour))
+		endsSecond := suspend(now.Add(2 * time.Hour))
+		endsLater := suspend(now.Add(4 * time.Hour))
+		active := newCharacter(t)
+		require.NoError(t, repo.Save(ctx, *active))
+
+		expired, err := suspensions.ExpiredSuspensions(ctx, now.Add(2*time.Hour), 1000)
+		require.NoError(t, err)
+		assert.Contains(t, expired, endsFirst)
+		assert.Contains(t, expired, endsSecond, "a suspension ending now has expired")
+		assert.NotContains(t, expired, endsLater)
+		assert.NotContains(t, expired, active.CharacterID)
+		assert.Less(t, indexOf(expired, endsFirst), indexOf(expired, endsSecond), "the longest ended come first")
+
+		limited, err := suspensions.ExpiredSuspensions(ctx, now.Add(2*time.Hour), 1)
+		require.NoError(t, err)
+		assert.Len(t, limited, 1)
+
+		loaded, err := repo.FindCharacterById(ctx, endsFirst)
+		require.NoError(t, err)
+		require.NoError(t, loaded.LiftSuspension(character.ReasonSuspensionExpired))
+		require.NoError(t, repo.Update(ctx, *loaded))
+
+		expired, err = suspensions.ExpiredSuspensions(ctx, now.Add(2*time.Hour), 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, expired, endsFirst, "lifted suspensions are not listed")
+	})
+}
+
+func indexOf(ids []character.CharacterID, id character.CharacterID) int {
+	for i, candidate := range ids {
+		if candidate.Equals(id) {
+			return i
+		}
+	}
+	return -1
+}
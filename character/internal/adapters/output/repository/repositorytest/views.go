@@ -0,0 +1,204 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// CharacterViewRepositoryContract runs the read model contract against the
+// repository built by newViews. Reset clears the whole read model, so the
+// subtests must not run in parallel with other users of the storage.
+func CharacterViewRepositoryContract(t *testing.T, newViews func(t *testing.T) repository.CharacterViewRepository) {
+	t.Run("save and load", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+
+		view := newView(10)
+		require.NoError(t, views.SaveCharacterView(ctx, view))
+
+		loaded, err := views.FindCharacterView(ctx, view.CharacterID)
+		require.NoError(t, err)
+		assert.Equal(t, view, *loaded)
+
+		checkpoint, err := views.Checkpoint(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), checkpoint, "saving a view moves the checkpoint")
+	})
+
+	t.Run("load missing view", func(t *testing.T) {
+		views := newViews(t)
+
+		_, err := views.FindCharacterView(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound)
+	})
+
+	t.Run("save replaces the view", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+
+		view := newView(20)
+		require.NoError(t, views.SaveCharacterView(ctx, view))
+
+		view.Gold = 5
+		view.Nickname = "Jaina"
+		view.Items = view.Items[1:]
+		view.Position = 21
+		require.NoError(t, views.SaveCharacterView(ctx, view))
+
+		loaded, err := views.FindCharacterView(ctx, view.CharacterID)
+		require.NoError(t, err)
+		assert.Equal(t, view, *loaded)
+	})
+
+	t.Run("checkpoint without view", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+
+		require.NoError(t, views.SaveCheckpoint(ctx, 30))
+
+		checkpoint, err := views.Checkpoint(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), checkpoint)
+	})
+
+	t.Run("reset drops views and checkpoint", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+
+		view := newView(40)
+		require.NoError(t, views.SaveCharacterView(ctx, view))
+		require.NoError(t, views.Reset(ctx))
+
+		_, err := views.FindCharacterView(ctx, view.CharacterID)
+		assert.ErrorIs(t, err, repository.ErrCharacterNotFound)
+
+		checkpoint, err := views.Checkpoint(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, checkpoint)
+	})
+
+	t.Run("search filters", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+		require.NoError(t, views.Reset(ctx))
+
+		guild := uuid.New()
+		arthas := saveSearchView(t, views, "Arthas", "WARRIOR", guild, 10, 1)
+		arwen := saveSearchView(t, views, "arwen", "RANGER", guild, 20, 5)
+		jaina := saveSearchView(t, views, "Jaina", "MAGE", guild, 30, 10)
+		saveSearchView(t, views, "Artemis", "RANGER", uuid.New(), 40, 20)
+		saveSearchView(t, views, "Ar_n", "WARRIOR", uuid.New(), 50, 1)
+
+		found := searchViews(t, views, repository.CharacterSearch{NicknamePrefix: "AR", GuildID: guild, Limit: 10})
+		assert.Equal(t, []uuid.UUID{arthas.CharacterID, arwen.CharacterID}, ids(found), "prefixes match case-insensitively")
+		assert.Empty(t, found[0].Items, "items are not loaded")
+		assert.Equal(t, arthas.LoginID, found[0].LoginID)
+
+		found = searchViews(t, views, repository.CharacterSearch{Class: "RANGER", GuildID: guild, Limit: 10})
+		assert.Equal(t, []uuid.UUID{arwen.CharacterID}, ids(found))
+
+		found = searchViews(t, views, repository.CharacterSearch{NicknamePrefix: "ar_", Limit: 10})
+		assert.Len(t, found, 1, "wildcards in the prefix are literal")
+
+		found = searchViews(t, views, repository.CharacterSearch{MinLevel: 5, MaxLevel: 10, Limit: 10})
+		assert.Equal(t, []uuid.UUID{arwen.CharacterID, jaina.CharacterID}, ids(found), "both bounds are included")
+		assert.Equal(t, 5, found[0].Level)
+
+		found = searchViews(t, views, repository.CharacterSearch{NicknamePrefix: "ar", MinLevel: 5, Limit: 10})
+		assert.Len(t, found, 2, "a missing bound is open")
+	})
+
+	t.Run("search pages through the sort", func(t *testing.T) {
+		views := newViews(t)
+		ctx := context.Background()
+		require.NoError(t, views.Reset(ctx))
+
+		guild := uuid.New()
+		saveSearchView(t, views, "Thrall", "WARRIOR", guild, 10, 1)
+		saveSearchView(t, views, "anduin", "MAGE", guild, 20, 1)
+		saveSearchView(t, views, "Sylvanas", "RANGER", guild, 30, 1)
+		saveSearchView(t, views, "Illidan", "RANGER", guild, 30, 1)
+		saveSearchView(t, views, "Uther", "WARRIOR", guild, 5, 1)
+
+		pages := func(search repository.CharacterSearch) []string {
+			var nicknames []string
+			for {
+				page := search
+				page.Limit = 2
+				found := searchViews(t, views, page)
+				for _, view := range found {
+					nicknames = append(nicknames, view.Nickname)
+				}
+				if len(found) < page.Limit {
+					return nicknames
+				}
+				last := found[len(found)-1]
+				search.After = &repository.CharacterSearchKey{Nickname: last.Nickname, Gold: last.Gold, CharacterID: last.CharacterID}
+			}
+		}
+
+		assert.Equal(t, []string{"anduin", "Illidan", "Sylvanas", "Thrall", "Uther"}, pages(repository.CharacterSearch{Sort: repository.SortByNickname}))
+		assert.Equal(t, []string{"Uther", "Thrall", "Sylvanas", "Illidan", "anduin"}, pages(repository.CharacterSearch{Sort: repository.SortByNickname, Descending: true}))
+
+		byGold := pages(repository.CharacterSearch{Sort: repository.SortByGold, Descending: true})
+		require.Len(t, byGold, 5)
+		assert.ElementsMatch(t, []string{"Sylvanas", "Illidan"}, byGold[:2], "equal gold is ordered by id")
+		assert.Equal(t, []string{"anduin", "Thrall", "Uther"}, byGold[2:])
+	})
+}
+
+func saveSearchView(t *testing.T, views repository.CharacterViewRepository, nickname, class string, guild uuid.UUID, gold, level int) repository.CharacterView {
+	t.Helper()
+	view := newView(1)
+	view.Nickname = nickname
+	view.Class = class
+	view.GuildID = guild
+	view.Gold = gold
+	view.Level = level
+	require.NoError(t, views.SaveCharacterView(context.Background(), view))
+	return view
+}
+
+func searchViews(t *testing.T, views repository.CharacterViewRepository, search repository.CharacterSearch) []repository.CharacterView {
+	t.Helper()
+	found, err := views.SearchCharacterViews(context.Background(), search)
+	require.NoError(t, err)
+	return found
+}
+
+func ids(views []repository.CharacterView) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(views))
+	for _, view := range views {
+		ids = append(ids, view.CharacterID)
+	}
+	return ids
+}
+
+func newView(position int64) repository.CharacterView {
+	items := []repository.ItemView{
+		{PlayerItemID: uuid.New(), ItemID: uuid.New(), Description: "Sword", Quantity: 1},
+		{PlayerItemID: uuid.New(), ItemID: uuid.New(), Description: "Potion", Quantity: 3},
+	}
+	if items[1].PlayerItemID.String() < items[0].PlayerItemID.String() {
+		items[0], items[1] = items[1], items[0]
+	}
+
+	return repository.CharacterView{
+		CharacterID: uuid.New(),
+		LoginID:     uuid.New(),
+		Nickname:    "Arthas",
+		Class:       "WARRIOR",
+		Level:       1,
+		InventoryID: uuid.New(),
+		GuildID:     uuid.Nil,
+		VaultID:     uuid.New(),
+		Gold:        150,
+		Items:       items,
+		Position:    position,
+	}
+}
@@ -0,0 +1,144 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// WalletRepositoryContract runs the wallet contract against the repository
+// built by newWallets. Every subtest uses a fresh login, so the storage may
+// be shared.
+func WalletRepositoryContract(t *testing.T, newWallets func(t *testing.T) repository.WalletRepository) {
+	t.Run("save and load", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 500, wallet.SourcePurchase))
+		require.NoError(t, saved.Debit("order-1", 120, "cosmetic"))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		loaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		assert.Equal(t, saved.LoginID(), loaded.LoginID())
+		assert.Equal(t, 380, loaded.Balance())
+		assert.Equal(t, 1, loaded.Version())
+		assert.Empty(t, loaded.PendingMovements(), "loading makes no movement")
+
+		movement, err := wallets.FindMovement(ctx, saved.LoginID(), "order-1")
+		require.NoError(t, err)
+		assert.Equal(t, wallet.Movement{Reference: "order-1", Amount: -120, Source: "cosmetic"}, *movement)
+	})
+
+	t.Run("load missing wallet", func(t *testing.T) {
+		wallets := newWallets(t)
+
+		_, err := wallets.FindWallet(context.Background(), login.NewLoginID(uuid.New()))
+		assert.ErrorIs(t, err, repository.ErrWalletNotFound)
+	})
+
+	t.Run("find missing movement", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 5, wallet.SourcePurchase))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		_, err := wallets.FindMovement(ctx, saved.LoginID(), "purchase-2")
+		assert.ErrorIs(t, err, repository.ErrMovementNotFound)
+		_, err = wallets.FindMovement(ctx, login.NewLoginID(uuid.New()), "purchase-1")
+		assert.ErrorIs(t, err, repository.ErrMovementNotFound, "references belong to one wallet")
+	})
+
+	t.Run("update keeps the earlier movements", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 50, wallet.SourcePurchase))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		loaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		require.NoError(t, loaded.Debit("order-1", 20, "cosmetic"))
+		require.NoError(t, wallets.SaveWallet(ctx, *loaded))
+
+		reloaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		assert.Equal(t, 30, reloaded.Balance())
+		assert.Equal(t, 2, reloaded.Version())
+
+		_, err = wallets.FindMovement(ctx, saved.LoginID(), "purchase-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("stale wallet is rejected", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 50, wallet.SourcePurchase))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		first, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		second, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+
+		require.NoError(t, first.Debit("order-1", 50, "cosmetic"))
+		require.NoError(t, wallets.SaveWallet(ctx, *first))
+		require.NoError(t, second.Debit("order-2", 50, "cosmetic"))
+		assert.ErrorIs(t, wallets.SaveWallet(ctx, *second), repository.ErrConcurrentWalletUpdate, "the cash cannot be spent twice")
+
+		reloaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		assert.Zero(t, reloaded.Balance())
+	})
+
+	t.Run("new wallet saved twice is rejected", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 5, wallet.SourcePurchase))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		again, err := wallet.NewWallet(saved.LoginID())
+		require.NoError(t, err)
+		require.NoError(t, again.Credit("purchase-2", 5, wallet.SourcePurchase))
+		assert.ErrorIs(t, wallets.SaveWallet(ctx, *again), repository.ErrConcurrentWalletUpdate)
+	})
+
+	t.Run("reused reference is rejected", func(t *testing.T) {
+		wallets := newWallets(t)
+		ctx := context.Background()
+
+		saved := newWallet(t)
+		require.NoError(t, saved.Credit("purchase-1", 50, wallet.SourcePurchase))
+		require.NoError(t, wallets.SaveWallet(ctx, *saved))
+
+		loaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		require.NoError(t, loaded.Credit("purchase-1", 50, wallet.SourcePurchase))
+		assert.ErrorIs(t, wallets.SaveWallet(ctx, *loaded), wallet.ErrDuplicateReference)
+
+		reloaded, err := wallets.FindWallet(ctx, saved.LoginID())
+		require.NoError(t, err)
+		assert.Equal(t, 50, reloaded.Balance(), "the rejected save changed nothing")
+	})
+}
+
+func newWallet(t *testing.T) *wallet.Wallet {
+	t.Helper()
+	w, err := wallet.NewWallet(login.NewLoginID(uuid.New()))
+	require.NoError(t, err)
+	return w
+}
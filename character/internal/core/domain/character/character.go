@@ -20,8 +20,14 @@ var (
 	ErrCannotDropItem     = errors.New("error while dropping item")
 	ErrCannotWithdrawGold = errors.New("error while withdrawing gold")
 	ErrCannotJoinGuild    = errors.New("character is already member of a guild")
+	ErrCannotChangeGuild  = errors.New("error while changing guild")
+	ErrCannotReachLevel   = errors.New("error while reaching level")
+	ErrInvalidLevel       = errors.New("level must be above the current one")
 )
 
+// StartingLevel is the level of a new character.
+const StartingLevel = 1
+
 type CharacterID struct {
 	base.BaseID[uuid.UUID]
 }
@@ -34,6 +40,10 @@ type Character struct {
 	inventory inventory.Inventory
 	guild     guild.GuildID
 	vault     vault.VaultID
+	level     int
+	standing  Standing
+	version   int
+	events    []Event
 }
 
 func NewCharacterID(value uuid.UUID) CharacterID {
@@ -42,11 +52,16 @@ func NewCharacterID(value uuid.UUID) CharacterID {
 	}
 }
 
-func CreateNewCharacter(nickname string, loginId login.LoginID, class class.Class, vaultId vault.VaultID) (*Character, error) {
+// CreateNewCharacter creates a character of a class the catalog defines,
+// holding the starting items of the class. The nickname is kept in its NFKC
+// form.
+func CreateNewCharacter(classes class.Catalog, nicknames NicknameRules, nickname string, loginId login.LoginID, class class.Class, vaultId vault.VaultID) (*Character, error) {
+	nickname = NormalizeNickname(nickname)
 
-	if err := ValidateNewCharacter(nickname, loginId, class); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrCreatePlayer, err)
+	if err := ValidateNewCharacter(classes, nicknames, nickname, loginId, class); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreatePlayer, err)
 	}
+	definition, _ := classes.Definition(class)
 
 	player := &Character{
 		CharacterID: NewCharacterID(uuid.New()),
@@ -56,11 +71,50 @@ func CreateNewCharacter(nickname string, loginId login.LoginID, class class.Clas
 		inventory:   *inventory.NewInventory(),
 		guild:       guild.NewGuildID(uuid.Nil),
 		vault:       vaultId,
+		level:       StartingLevel,
+	}
+
+	player.record(CharacterCreated{
+		Character: player.CharacterID,
+		Login:     player.loginID,
+		Nickname:  player.nickname,
+		Class:     player.class,
+		Inventory: player.inventory.InventoryID,
+		Guild:     player.guild,
+		Vault:     player.vault,
+		Level:     player.level,
+	})
+
+	for _, startingItem := range definition.StartingItems {
+		item, err := playeritem.NewPlayerItem(startingItem.Item, startingItem.Description, startingItem.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCreatePlayer, err)
+		}
+		if err := player.PickItem(*item); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCreatePlayer, err)
+		}
 	}
 
 	return player, nil
 }
 
+// Restore rebuilds a character from persisted state. version is the one
+// stored alongside it, which repositories use to detect concurrent updates.
+func Restore(id CharacterID, loginId login.LoginID, nickname string, class class.Class, inventory inventory.Inventory, guild guild.GuildID, vault vault.VaultID, level int, standing Standing, version int) *Character {
+	return &Character{
+		CharacterID: id,
+		loginID:     loginId,
+		nickname:    nickname,
+		class:       class,
+		inventory:   inventory,
+		guild:       guild,
+		vault:       vault,
+		level:       level,
+		standing:    standing,
+		version:     version,
+	}
+}
+
 func (c *Character) LoginID() login.LoginID {
 	return c.loginID
 }
@@ -69,6 +123,11 @@ func (c *Character) Nickname() string {
 	return c.nickname
 }
 
+// NicknameSkeleton is the form of the nickname no other character may share.
+func (c *Character) NicknameSkeleton() string {
+	return NicknameSkeleton(c.nickname)
+}
+
 func (c *Character) Class() class.Class {
 	return c.class
 }
@@ -85,39 +144,117 @@ func (c *Character) GetCurrentVaultId() vault.VaultID {
 	return c.vault
 }
 
-func (c *Character) UpdateGuildInfo(guildId guild.GuildID) {
+func (c *Character) Level() int {
+	return c.level
+}
+
+// Version is the persisted version the character was loaded at.
+func (c *Character) Version() int {
+	return c.version
+}
+
+// PendingEvents returns the events recorded since the character was created
+// or loaded, oldest first.
+func (c *Character) PendingEvents() []Event {
+	return c.events
+}
+
+func (c *Character) UpdateGuildInfo(guildId guild.GuildID) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotChangeGuild, err)
+	}
+	if c.guild.ID() == guildId.ID() {
+		return nil
+	}
 	c.guild = guildId
+	c.record(GuildChanged{Character: c.CharacterID, Guild: guildId})
+	return nil
+}
+
+// ReachLevel raises the character to level. Levels are never lost, so level
+// must be above the current one. No game operation raises levels yet: only
+// imports call it, so characters created here stay at StartingLevel.
+func (c *Character) ReachLevel(level int) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotReachLevel, err)
+	}
+	if level <= c.level {
+		return fmt.Errorf("%w: %w: %d, currently %d", ErrCannotReachLevel, ErrInvalidLevel, level, c.level)
+	}
+	c.level = level
+	c.record(LevelReached{Character: c.CharacterID, Level: level})
+	return nil
 }
 
 func (c *Character) PickItem(playeritem playeritem.PlayerItem) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotPickItem, err)
+	}
 	if err := c.inventory.AddItem(playeritem); err != nil {
-		return fmt.Errorf("%w: %v", ErrCannotPickItem, err)
+		return fmt.Errorf("%w: %w", ErrCannotPickItem, err)
 	}
+	c.record(ItemAdded{Character: c.CharacterID, Item: playeritem})
 	return nil
 }
 
 func (c *Character) DropItem(playeritem playeritem.PlayerItem) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotDropItem, err)
+	}
+	dropped, _ := c.inventory.FindItem(playeritem.PlayerItemID)
 	if err := c.inventory.DropItem(playeritem); err != nil {
-		return fmt.Errorf("%w: %v", ErrCannotDropItem, err)
+		return fmt.Errorf("%w: %w", ErrCannotDropItem, err)
 	}
+	c.record(ItemDropped{Character: c.CharacterID, Item: dropped})
 	return nil
 }
 
 func (c *Character) PickGold(amount int) error {
-	return c.inventory.AddGold(amount)
+	if err := ValidateActive(c); err != nil {
+		return err
+	}
+	if err := c.inventory.AddGold(amount); err != nil {
+		return err
+	}
+	c.record(GoldAdded{Character: c.CharacterID, Amount: amount})
+	return nil
 }
 
 func (c *Character) DropGold(amount int) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotWithdrawGold, err)
+	}
 	if err := c.inventory.WithdrawGold(amount); err != nil {
-		return fmt.Errorf("%w: %v", ErrCannotWithdrawGold, err)
+		return fmt.Errorf("%w: %w", ErrCannotWithdrawGold, err)
 	}
+	c.record(GoldWithdrawn{Character: c.CharacterID, Amount: amount})
 	return nil
 }
 
+// DepositGold moves gold from the inventory into the vault of the character.
+func (c *Character) DepositGold(amount int, vaultId vault.VaultID) error {
+	if err := ValidateActive(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotWithdrawGold, err)
+	}
+	if err := c.inventory.WithdrawGold(amount); err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotWithdrawGold, err)
+	}
+	c.record(GoldWithdrawn{Character: c.CharacterID, Amount: amount, Vault: vaultId})
+	return nil
+}
+
+func (c *Character) FindItem(playerItemID playeritem.PlayerItemID) (playeritem.PlayerItem, bool) {
+	return c.inventory.FindItem(playerItemID)
+}
+
 func (c *Character) OpenInventory() []playeritem.PlayerItemID {
 	return c.inventory.ShowItems()
 }
 
+func (c *Character) record(event Event) {
+	c.events = append(c.events, event)
+}
+
 func (id CharacterID) Equals(other CharacterID) bool {
 	return id.BaseID.Equals(other.BaseID)
 }
@@ -3,6 +3,7 @@ package character
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -10,7 +11,9 @@ import (
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/specifications"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
 	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
 )
 
@@ -18,7 +21,7 @@ import (
 func setupTestCharacter(t *testing.T) *Character {
 	validLogin := login.NewLoginID(uuid.New())
 
-	character, err := CreateNewCharacter("TestPlayer", validLogin, class.Warrior, vault.NewVaultID(uuid.New()))
+	character, err := CreateNewCharacter(class.Defaults(), DefaultNicknameRules(), "TestPlayer", validLogin, class.Warrior, vault.NewVaultID(uuid.New()))
 	assert.NoError(t, err)
 	assert.NotNil(t, character)
 
@@ -73,11 +76,20 @@ func TestCreateNewCharacter(t *testing.T) {
 			wantErr:   true,
 			errString: "error while creating player",
 		},
+		{
+			name:      "class missing from the catalog",
+			nickname:  "TestPlayer",
+			loginId:   validLogin,
+			class:     class.Class("PALADIN"),
+			vaultId:   vault.NewVaultID(uuid.New()),
+			wantErr:   true,
+			errString: "invalid class",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			character, err := CreateNewCharacter(tt.nickname, tt.loginId, tt.class, tt.vaultId)
+			character, err := CreateNewCharacter(class.Defaults(), DefaultNicknameRules(), tt.nickname, tt.loginId, tt.class, tt.vaultId)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errString)
@@ -105,7 +117,7 @@ func TestCharacterGuildOperations(t *testing.T) {
 
 	t.Run("update guild info", func(t *testing.T) {
 		newGuildId := guild.NewGuildID(uuid.New())
-		character.UpdateGuildInfo(newGuildId)
+		assert.NoError(t, character.UpdateGuildInfo(newGuildId))
 		assert.Equal(t, newGuildId, character.GetCurrentGuild())
 	})
 }
@@ -189,12 +201,249 @@ func TestCharacterInventoryOperations(t *testing.T) {
 	})
 }
 
+func TestCharacterRecordsEvents(t *testing.T) {
+	character := setupTestCharacter(t)
+	testItem := setupTestItem(t, "TestItem")
+	guildId := guild.NewGuildID(uuid.New())
+
+	assert.NoError(t, character.PickItem(*testItem))
+	assert.NoError(t, character.PickGold(100))
+	assert.NoError(t, character.DropGold(40))
+	assert.NoError(t, character.DepositGold(25, character.GetCurrentVaultId()))
+	assert.Error(t, character.DropGold(500), "failed operations record nothing")
+	assert.NoError(t, character.DropItem(playeritem.Restore(testItem.PlayerItemID, item.NewItemID(uuid.Nil), "", 0)))
+	assert.NoError(t, character.UpdateGuildInfo(guildId))
+	assert.NoError(t, character.UpdateGuildInfo(guildId))
+	assert.NoError(t, character.ReachLevel(3))
+
+	events := character.PendingEvents()
+	assert.Equal(t, []Event{
+		CharacterCreated{
+			Character: character.CharacterID,
+			Login:     character.LoginID(),
+			Nickname:  "TestPlayer",
+			Class:     class.Warrior,
+			Inventory: character.Inventory().InventoryID,
+			Guild:     guild.NewGuildID(uuid.Nil),
+			Vault:     character.GetCurrentVaultId(),
+			Level:     StartingLevel,
+		},
+		ItemAdded{Character: character.CharacterID, Item: *testItem},
+		GoldAdded{Character: character.CharacterID, Amount: 100},
+		GoldWithdrawn{Character: character.CharacterID, Amount: 40},
+		GoldWithdrawn{Character: character.CharacterID, Amount: 25, Vault: character.GetCurrentVaultId()},
+		ItemDropped{Character: character.CharacterID, Item: *testItem},
+		GuildChanged{Character: character.CharacterID, Guild: guildId},
+		LevelReached{Character: character.CharacterID, Level: 3},
+	}, events, "the dropped item is the one held, and an unchanged guild records nothing")
+
+	restored := Restore(character.CharacterID, character.LoginID(), character.Nickname(), character.Class(), character.Inventory(), guildId, character.GetCurrentVaultId(), character.Level(), Standing{}, 1)
+	assert.Empty(t, restored.PendingEvents())
+}
+
+func TestReplayInventory(t *testing.T) {
+	character := setupTestCharacter(t)
+	sword := setupTestItem(t, "Sword")
+	potion := setupTestItem(t, "Potion")
+
+	assert.NoError(t, character.PickItem(*sword))
+	assert.NoError(t, character.PickItem(*potion))
+	assert.NoError(t, character.PickGold(100))
+	assert.NoError(t, character.DropGold(30))
+	assert.NoError(t, character.DropItem(*sword))
+
+	replayed := inventory.Restore(character.Inventory().InventoryID, 0, nil)
+	assert.NoError(t, ReplayInventory(replayed, character.PendingEvents()))
+	assert.Equal(t, character.Inventory(), *replayed)
+
+	overdrawn := inventory.Restore(character.Inventory().InventoryID, 0, nil)
+	err := ReplayInventory(overdrawn, []Event{GoldWithdrawn{Character: character.CharacterID, Amount: 5}})
+	assert.ErrorIs(t, err, inventory.ErrNotEnoughGold)
+}
+
 func TestCharacterVaultOperations(t *testing.T) {
 	vaultId := vault.NewVaultID(uuid.New())
 	validLogin := login.NewLoginID(uuid.New())
-	character, _ := CreateNewCharacter("TestPlayer", validLogin, class.Warrior, vaultId)
+	character, _ := CreateNewCharacter(class.Defaults(), DefaultNicknameRules(), "TestPlayer", validLogin, class.Warrior, vaultId)
 
 	t.Run("get vault id", func(t *testing.T) {
 		assert.Equal(t, vaultId, character.GetCurrentVaultId())
 	})
 }
+
+func TestCreateNewCharacterWithStartingItems(t *testing.T) {
+	mace := item.NewItemID(uuid.New())
+	paladin, err := class.NewDefinition("PALADIN", "Paladin",
+		[]class.StartingItem{{Item: mace, Description: "Mace", Quantity: 1}, {Item: item.NewItemID(uuid.New()), Description: "Potion", Quantity: 3}},
+		class.Stats{"faith": 7},
+	)
+	assert.NoError(t, err)
+
+	character, err := CreateNewCharacter(class.NewDefinitions(paladin), DefaultNicknameRules(), "TestPlayer", login.NewLoginID(uuid.New()), paladin.Class, vault.NewVaultID(uuid.New()))
+	assert.NoError(t, err)
+
+	held := map[string]playeritem.PlayerItem{}
+	for _, item := range character.inventory.Items() {
+		held[item.Describe()] = item
+	}
+	assert.Len(t, held, 2)
+	maceItem, potion := held["Mace"], held["Potion"]
+	assert.Equal(t, mace, maceItem.ItemID())
+	assert.Equal(t, 3, potion.GetCurrentQuantity())
+}
+
+func TestValidateNewCharacterUnknownClass(t *testing.T) {
+	err := ValidateNewCharacter(class.Defaults(), DefaultNicknameRules(), "TestPlayer", login.NewLoginID(uuid.New()), class.Class("PALADIN"))
+
+	violations := specifications.Violations(err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "class", violations[0].Field)
+	assert.Equal(t, RuleCatalog, violations[0].Rule)
+	assert.ErrorIs(t, err, class.ErrInvalidClass)
+}
+
+func TestValidateNewCharacterViolations(t *testing.T) {
+	err := ValidateNewCharacter(class.Defaults(), DefaultNicknameRules(), "ab", login.LoginID{}, class.Mage)
+
+	violations := specifications.Violations(err)
+	assert.Len(t, violations, 2)
+
+	assert.Equal(t, "nickname", violations[0].Field)
+	assert.Equal(t, RuleLength, violations[0].Rule)
+	assert.Equal(t, map[string]any{"min": MinNicknameLength, "max": MaxNicknameLength}, violations[0].Params)
+	assert.ErrorIs(t, violations[0], ErrInvalidNicknameSize)
+
+	assert.Equal(t, "loginId", violations[1].Field)
+	assert.Equal(t, RuleRequired, violations[1].Rule)
+	assert.ErrorIs(t, err, ErrInvalidLoginId)
+}
+
+func TestCharacterStatusTransitions(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	until := now.Add(24 * time.Hour)
+
+	suspend := func(c *Character) error { return c.Suspend(until, "cheating", now) }
+	lift := func(c *Character) error { return c.LiftSuspension("appeal accepted") }
+	ban := func(c *Character) error { return c.Ban("cheating") }
+	remove := func(c *Character) error { return c.Delete("account closed") }
+
+	tests := []struct {
+		name    string
+		before  []func(*Character) error
+		change  func(*Character) error
+		want    Status
+		wantErr error
+	}{
+		{name: "suspend an active character", change: suspend, want: StatusSuspended},
+		{name: "suspend again to extend", before: []func(*Character) error{suspend}, change: suspend, want: StatusSuspended},
+		{name: "lift a suspension", before: []func(*Character) error{suspend}, change: lift, want: StatusActive},
+		{name: "lift without a suspension", change: lift, want: StatusActive, wantErr: ErrInvalidStatusTransition},
+		{name: "ban an active character", change: ban, want: StatusBanned},
+		{name: "ban a suspended character", before: []func(*Character) error{suspend}, change: ban, want: StatusBanned},
+		{name: "suspend a banned character", before: []func(*Character) error{ban}, change: suspend, want: StatusBanned, wantErr: ErrInvalidStatusTransition},
+		{name: "delete a banned character", before: []func(*Character) error{ban}, change: remove, want: StatusDeleted},
+		{name: "delete twice", before: []func(*Character) error{remove}, change: remove, want: StatusDeleted, wantErr: ErrInvalidStatusTransition},
+		{name: "ban a deleted character", before: []func(*Character) error{remove}, change: ban, want: StatusDeleted, wantErr: ErrInvalidStatusTransition},
+		{name: "suspend without a reason", change: func(c *Character) error { return c.Suspend(until, "  ", now) }, want: StatusActive, wantErr: ErrMissingStatusReason},
+		{name: "suspend into the past", change: func(c *Character) error { return c.Suspend(now, "cheating", now) }, want: StatusActive, wantErr: ErrInvalidSuspensionEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			character := setupTestCharacter(t)
+			for _, before := range tt.before {
+				assert.NoError(t, before(character))
+			}
+			recorded := len(character.PendingEvents())
+
+			err := tt.change(character)
+			assert.Equal(t, tt.want, character.Standing().Status)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, character.PendingEvents(), recorded, "failed transitions record nothing")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, character.PendingEvents(), recorded+1)
+		})
+	}
+}
+
+func TestCharacterStatusRecordsEvents(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	character := setupTestCharacter(t)
+
+	assert.NoError(t, character.Suspend(now.Add(time.Hour), " botting ", now))
+	assert.Equal(t, Standing{Status: StatusSuspended, SuspendedUntil: now.Add(time.Hour), Reason: "botting"}, character.Standing())
+	assert.False(t, character.Standing().SuspensionExpired(now))
+	assert.True(t, character.Standing().SuspensionExpired(now.Add(time.Hour)))
+	assert.NoError(t, character.LiftSuspension(ReasonSuspensionExpired))
+	assert.Equal(t, Standing{}, character.Standing())
+	assert.NoError(t, character.Ban("botting"))
+
+	assert.Equal(t, []Event{
+		CharacterSuspended{Character: character.CharacterID, Until: now.Add(time.Hour), Reason: "botting"},
+		SuspensionLifted{Character: character.CharacterID, Reason: ReasonSuspensionExpired},
+		CharacterBanned{Character: character.CharacterID, Reason: "botting"},
+	}, character.PendingEvents()[1:])
+}
+
+func TestCharacterReachLevel(t *testing.T) {
+	character := setupTestCharacter(t)
+	assert.Equal(t, StartingLevel, character.Level())
+
+	assert.NoError(t, character.ReachLevel(5))
+	assert.Equal(t, 5, character.Level())
+
+	for _, level := range []int{5, 4} {
+		err := character.ReachLevel(level)
+		assert.ErrorIs(t, err, ErrCannotReachLevel)
+		assert.ErrorIs(t, err, ErrInvalidLevel)
+	}
+	assert.Equal(t, 5, character.Level(), "levels are never lost")
+}
+
+func TestInactiveCharacterCannotChange(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	character := setupTestCharacter(t)
+	sword := setupTestItem(t, "Sword")
+	assert.NoError(t, character.PickItem(*sword))
+	assert.NoError(t, character.PickGold(50))
+	assert.NoError(t, character.Suspend(now.Add(time.Hour), "cheating", now))
+	recorded := len(character.PendingEvents())
+
+	operations := map[string]func() error{
+		"pick item":    func() error { return character.PickItem(*setupTestItem(t, "Shield")) },
+		"drop item":    func() error { return character.DropItem(*sword) },
+		"pick gold":    func() error { return character.PickGold(10) },
+		"drop gold":    func() error { return character.DropGold(10) },
+		"deposit gold": func() error { return character.DepositGold(10, character.GetCurrentVaultId()) },
+		"change guild": func() error { return character.UpdateGuildInfo(guild.NewGuildID(uuid.New())) },
+		"reach level":  func() error { return character.ReachLevel(2) },
+	}
+	for name, operation := range operations {
+		err := operation()
+		assert.ErrorIs(t, err, ErrCharacterNotActive, name)
+
+		violations := specifications.Violations(err)
+		if assert.Len(t, violations, 1, name) {
+			assert.Equal(t, "status", violations[0].Field)
+			assert.Equal(t, RuleActive, violations[0].Rule)
+			assert.Equal(t, map[string]any{"status": "SUSPENDED"}, violations[0].Params)
+		}
+	}
+	assert.Len(t, character.PendingEvents(), recorded, "blocked operations record nothing")
+	inventory := character.Inventory()
+	assert.Equal(t, 50, inventory.GetCurrentGold())
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, status := range []Status{StatusActive, StatusSuspended, StatusBanned, StatusDeleted} {
+		parsed, err := ParseStatus(status.String())
+		assert.NoError(t, err)
+		assert.Equal(t, status, parsed)
+	}
+
+	_, err := ParseStatus("FROZEN")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
@@ -1 +1,157 @@
 package character
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+)
+
+const (
+	EventCharacterCreated = "CharacterCreated"
+	EventItemAdded        = "ItemAdded"
+	EventItemDropped      = "ItemDropped"
+	EventGoldAdded        = "GoldAdded"
+	EventGoldWithdrawn    = "GoldWithdrawn"
+	EventGuildChanged     = "GuildChanged"
+	EventLevelReached     = "LevelReached"
+	EventSuspended        = "CharacterSuspended"
+	EventSuspensionLifted = "SuspensionLifted"
+	EventBanned           = "CharacterBanned"
+	EventDeleted          = "CharacterDeleted"
+)
+
+// Event is a change the character aggregate went through. The events are
+// recorded by the aggregate methods and persisted by the repository in the
+// same transaction as the state they produced.
+type Event interface {
+	AggregateID() CharacterID
+	EventType() string
+}
+
+type CharacterCreated struct {
+	Character CharacterID
+	Login     login.LoginID
+	Nickname  string
+	Class     class.Class
+	Inventory inventory.InventoryID
+	Guild     guild.GuildID
+	Vault     vault.VaultID
+	Level     int
+}
+
+type ItemAdded struct {
+	Character CharacterID
+	Item      playeritem.PlayerItem
+}
+
+type ItemDropped struct {
+	Character CharacterID
+	Item      playeritem.PlayerItem
+}
+
+type GoldAdded struct {
+	Character CharacterID
+	Amount    int
+}
+
+// GoldWithdrawn is gold leaving the inventory. Vault is where it went when it
+// was deposited; the zero vault means it left the game.
+type GoldWithdrawn struct {
+	Character CharacterID
+	Amount    int
+	Vault     vault.VaultID
+}
+
+type GuildChanged struct {
+	Character CharacterID
+	Guild     guild.GuildID
+}
+
+type LevelReached struct {
+	Character CharacterID
+	Level     int
+}
+
+type CharacterSuspended struct {
+	Character CharacterID
+	Until     time.Time
+	Reason    string
+}
+
+type SuspensionLifted struct {
+	Character CharacterID
+	Reason    string
+}
+
+type CharacterBanned struct {
+	Character CharacterID
+	Reason    string
+}
+
+type CharacterDeleted struct {
+	Character CharacterID
+	Reason    string
+}
+
+func (e CharacterCreated) AggregateID() CharacterID   { return e.Character }
+func (e ItemAdded) AggregateID() CharacterID          { return e.Character }
+func (e ItemDropped) AggregateID() CharacterID        { return e.Character }
+func (e GoldAdded) AggregateID() CharacterID          { return e.Character }
+func (e GoldWithdrawn) AggregateID() CharacterID      { return e.Character }
+func (e GuildChanged) AggregateID() CharacterID       { return e.Character }
+func (e LevelReached) AggregateID() CharacterID       { return e.Character }
+func (e CharacterSuspended) AggregateID() CharacterID { return e.Character }
+func (e SuspensionLifted) AggregateID() CharacterID   { return e.Character }
+func (e CharacterBanned) AggregateID() CharacterID    { return e.Character }
+func (e CharacterDeleted) AggregateID() CharacterID   { return e.Character }
+
+func (CharacterCreated) EventType() string   { return EventCharacterCreated }
+func (ItemAdded) EventType() string          { return EventItemAdded }
+func (ItemDropped) EventType() string        { return EventItemDropped }
+func (GoldAdded) EventType() string          { return EventGoldAdded }
+func (GoldWithdrawn) EventType() string      { return EventGoldWithdrawn }
+func (GuildChanged) EventType() string       { return EventGuildChanged }
+func (LevelReached) EventType() string       { return EventLevelReached }
+func (CharacterSuspended) EventType() string { return EventSuspended }
+func (SuspensionLifted) EventType() string   { return EventSuspensionLifted }
+func (CharacterBanned) EventType() string    { return EventBanned }
+func (CharacterDeleted) EventType() string   { return EventDeleted }
+
+// IsInventoryEvent reports whether event changed the inventory of the
+// character, as opposed to the character itself.
+func IsInventoryEvent(event Event) bool {
+	switch event.(type) {
+	case ItemAdded, ItemDropped, GoldAdded, GoldWithdrawn:
+		return true
+	}
+	return false
+}
+
+// ReplayInventory applies the inventory events to inv in order, going through
+// the same rules the events were recorded under. Other events are skipped, so
+// the whole history of a character can be passed in.
+func ReplayInventory(inv *inventory.Inventory, events []Event) error {
+	for _, event := range events {
+		var err error
+		switch e := event.(type) {
+		case ItemAdded:
+			err = inv.AddItem(e.Item)
+		case ItemDropped:
+			err = inv.DropItem(e.Item)
+		case GoldAdded:
+			err = inv.AddGold(e.Amount)
+		case GoldWithdrawn:
+			err = inv.WithdrawGold(e.Amount)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot replay %s: %w", event.EventType(), err)
+		}
+	}
+	return nil
+}
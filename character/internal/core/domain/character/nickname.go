@@ -0,0 +1,128 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrUnknownScript     = errors.New("unknown unicode script")
+	ErrNicknameTaken     = errors.New("nickname is already taken")
+	ErrNoNicknameScripts = errors.New("nickname rules must allow a script")
+)
+
+// DefaultNicknameScripts lets our Brazilian and Asian players use their real
+// names.
+var DefaultNicknameScripts = []string{"Latin", "Han", "Hiragana", "Katakana", "Hangul"}
+
+// NicknameRules decides which letters a nickname may hold: letters of the
+// allowed Unicode scripts and ASCII digits. Combining marks and modifier
+// letters, such as the Japanese long vowel mark, may follow a letter.
+type NicknameRules struct {
+	scripts []string
+	tables  []*unicode.RangeTable
+}
+
+// NewNicknameRules allows the letters of the given scripts, named as in
+// unicode.Scripts, e.g. "Latin" or "Han".
+func NewNicknameRules(scripts ...string) (NicknameRules, error) {
+	if len(scripts) == 0 {
+		return NicknameRules{}, ErrNoNicknameScripts
+	}
+	rules := NicknameRules{}
+	for _, script := range scripts {
+		table, ok := unicode.Scripts[script]
+		if !ok {
+			return NicknameRules{}, fmt.Errorf("%w: %s", ErrUnknownScript, script)
+		}
+		rules.scripts = append(rules.scripts, script)
+		rules.tables = append(rules.tables, table)
+	}
+	return rules, nil
+}
+
+func DefaultNicknameRules() NicknameRules {
+	rules, _ := NewNicknameRules(DefaultNicknameScripts...)
+	return rules
+}
+
+func (r NicknameRules) Scripts() []string {
+	return slices.Clone(r.scripts)
+}
+
+// allows reports whether every rune of the normalised nickname is allowed.
+func (r NicknameRules) allows(nickname string) bool {
+	previousLetter := false
+	for _, c := range nickname {
+		switch {
+		case c <= unicode.MaxASCII && unicode.IsDigit(c):
+			previousLetter = false
+		case unicode.IsLetter(c) && unicode.In(c, r.tables...):
+			previousLetter = true
+		case unicode.In(c, unicode.Mn, unicode.Mc, unicode.Lm) && previousLetter:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeNickname returns the NFKC form nicknames are stored and compared
+// in, so full width letters and ligatures read as their plain forms.
+func NormalizeNickname(nickname string) string {
+	return norm.NFKC.String(nickname)
+}
+
+// NicknameLength counts the grapheme clusters of the nickname, the
+// characters a player sees. Nicknames only hold letters, digits and marks,
+// so a cluster starts at every rune that is neither a combining mark nor a
+// Hangul vowel or final consonant jamo.
+func NicknameLength(nickname string) int {
+	length := 0
+	for _, c := range nickname {
+		if unicode.In(c, unicode.Mn, unicode.Mc, unicode.Me) || isHangulTrailingJamo(c) {
+			continue
+		}
+		length++
+	}
+	return length
+}
+
+func isHangulTrailingJamo(c rune) bool {
+	return (c >= 0x1160 && c <= 0x11FF) || (c >= 0xD7B0 && c <= 0xD7FF)
+}
+
+// NicknameSkeleton maps the nickname to the form uniqueness is checked on,
+// after UTS #39: case is folded first, then letters of other scripts that
+// look like Latin ones are replaced by them. "Arthas" written with a Cyrillic
+// "а" has the skeleton of "Arthas".
+func NicknameSkeleton(nickname string) string {
+	var skeleton strings.Builder
+	for _, c := range norm.NFD.String(NormalizeNickname(nickname)) {
+		c = unicode.ToLower(c)
+		if latin, ok := confusables[c]; ok {
+			c = latin
+		}
+		skeleton.WriteRune(c)
+	}
+	return norm.NFC.String(skeleton.String())
+}
+
+// confusables maps the lower case runes most often used to impersonate a
+// nickname to the Latin letter they, or their capitals, look like.
+var confusables = map[rune]rune{
+	// digits
+	'1': 'l', '0': 'o',
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'ь': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'н': 'h', 'һ': 'h', 'і': 'i',
+	'ӏ': 'l', 'ј': 'j', 'к': 'k', 'м': 'm', 'о': 'o', 'р': 'p', 'ѕ': 's', 'т': 't', 'х': 'x',
+	'у': 'y', 'ԛ': 'q', 'ԝ': 'w',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'h', 'ι': 'i', 'κ': 'k', 'μ': 'm', 'ν': 'n', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'y', 'χ': 'x', 'ζ': 'z',
+}
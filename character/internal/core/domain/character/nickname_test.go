@@ -0,0 +1,100 @@
+package character
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/specifications"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+)
+
+func TestNewNicknameRules(t *testing.T) {
+	rules, err := NewNicknameRules("Latin", "Cyrillic")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Latin", "Cyrillic"}, rules.Scripts())
+
+	_, err = NewNicknameRules("Latin", "Klingon")
+	assert.ErrorIs(t, err, ErrUnknownScript)
+
+	_, err = NewNicknameRules()
+	assert.ErrorIs(t, err, ErrNoNicknameScripts)
+}
+
+func TestValidateNickname(t *testing.T) {
+	tests := []struct {
+		name     string
+		nickname string
+		err      error
+	}{
+		{"ascii", "Arthas99", nil},
+		{"portuguese", "João", nil},
+		{"decomposed accent", "Joa\u0303o", nil},
+		{"chinese", "諸葛孔明", nil},
+		{"three chinese characters", "李小龍", ErrInvalidNicknameSize},
+		{"japanese long vowel mark", "アーサー", nil},
+		{"korean", "서울사람", nil},
+		{"full width letters", "Ａｒｔｈａｓ", nil},
+		{"cyrillic is not allowed by default", "Arth\u0430s", ErrInvalidNicknameChars},
+		{"space", "Arthas Menethil", ErrInvalidNicknameChars},
+		{"symbol", "Arthas!", ErrInvalidNicknameChars},
+		{"leading mark", "\u0303Arthas", ErrInvalidNicknameChars},
+		{"three graphemes", "Jo\u0303a", ErrInvalidNicknameSize},
+		{"sixteen graphemes", "ÁÁÁÁÁÁÁÁÁÁÁÁÁÁÁÁ", ErrInvalidNicknameSize},
+		{"fifteen graphemes of two bytes", "ÁÁÁÁÁÁÁÁÁÁÁÁÁÁÁ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNewCharacter(class.Defaults(), DefaultNicknameRules(), NormalizeNickname(tt.nickname), login.NewLoginID(uuid.New()), class.Mage)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCharsetViolationListsScripts(t *testing.T) {
+	err := ValidateNewCharacter(class.Defaults(), DefaultNicknameRules(), "Arthas!", login.NewLoginID(uuid.New()), class.Mage)
+
+	violations := specifications.Violations(err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, RuleCharset, violations[0].Rule)
+	assert.Equal(t, map[string]any{"scripts": DefaultNicknameScripts}, violations[0].Params)
+}
+
+func TestCreateNewCharacterNormalizesNickname(t *testing.T) {
+	c, err := CreateNewCharacter(class.Defaults(), DefaultNicknameRules(), "Ａｒｔｈａｓ", login.NewLoginID(uuid.New()), class.Mage, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	assert.Equal(t, "Arthas", c.Nickname())
+}
+
+func TestNicknameSkeleton(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"cyrillic a", "Arthas", "Arth\u0430s", true},
+		{"greek omicron", "Sylvanas", "Sylvan\u03bfs", false},
+		{"greek omicron for o", "Thrall0", "Thrall\u03bf", true},
+		{"case", "Arthas", "ARTHAS", true},
+		{"cyrillic capital", "Thrall", "\u0422hrall", true},
+		{"capital I folds before mapping", "Ian", "ian", true},
+		{"capital I is not l", "Ian", "lan", false},
+		{"digits", "L1ch0", "Llcho", true},
+		{"full width", "Arthas", "Ａｒｔｈａｓ", true},
+		{"composed and decomposed", "João", "Joa\u0303o", true},
+		{"accents still count", "João", "Joao", false},
+		{"different names", "Arthas", "Uther", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, NicknameSkeleton(tt.a) == NicknameSkeleton(tt.b))
+		})
+	}
+}
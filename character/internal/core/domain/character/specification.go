@@ -2,7 +2,7 @@ package character
 
 import (
 	"errors"
-	"regexp"
+	"fmt"
 
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
@@ -12,10 +12,23 @@ import (
 	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
 )
 
+const (
+	MinNicknameLength = 4
+	MaxNicknameLength = 15
+)
+
+const (
+	RuleLength   = "LENGTH"
+	RuleCharset  = "CHARSET"
+	RuleRequired = "REQUIRED"
+	RuleActive   = "ACTIVE"
+	RuleCatalog  = "CATALOG"
+)
+
 var (
-	ErrInvalidNicknameSize  = errors.New("invalid nickname size -  must by between 4 and 15 characters")
-	ErrInvalidNicknameChars = errors.New("invalid nickname charecters -  must not contain special characters")
-	ErrInvalidClass         = errors.New("invalid class was provided")
+	ErrInvalidNicknameSize  = fmt.Errorf("invalid nickname size -  must by between %d and %d characters", MinNicknameLength, MaxNicknameLength)
+	ErrInvalidNicknameChars = errors.New("invalid nickname charecters -  must only contain letters of the allowed scripts and digits")
+	ErrInvalidClass         = class.ErrInvalidClass
 	ErrInvalidLoginId       = errors.New("loginid not provided")
 	ErrEmptyCharacterID     = errors.New("player id cannot be empty")
 )
@@ -25,9 +38,12 @@ type CharacterParams struct {
 	loginID     login.LoginID
 	nickname    string
 	class       class.Class
+	classes     class.Catalog
+	nicknames   NicknameRules
 	inventory   inventory.Inventory
 	guild       guild.GuildID
 	vault       vault.VaultID
+	status      Status
 }
 
 func NewCharacterParams(character *Character) *CharacterParams {
@@ -39,14 +55,20 @@ func NewCharacterParams(character *Character) *CharacterParams {
 		inventory:   character.inventory,
 		guild:       character.guild,
 		vault:       character.vault,
+		status:      character.standing.Status,
 	}
 }
 
-func ValidateNewCharacter(nickname string, loginID login.LoginID, class class.Class) error {
+// ValidateNewCharacter checks a character about to be created. Its class
+// must be defined in the catalog and its nickname, in NFKC form, must follow
+// the nickname rules. Whether the nickname is taken is up to the repository.
+func ValidateNewCharacter(classes class.Catalog, nicknames NicknameRules, nickname string, loginID login.LoginID, class class.Class) error {
 	params := CharacterParams{
-		nickname: nickname,
-		loginID:  loginID,
-		class:    class,
+		nickname:  nickname,
+		loginID:   loginID,
+		class:     class,
+		classes:   classes,
+		nicknames: nicknames,
 	}
 
 	spec := NewCharacterSpecification()
@@ -58,14 +80,37 @@ func NewCharacterSpecification() specifications.Specification[CharacterParams] {
 		NicknameSizeSpec(),
 		NotSpecialCharacterSpec(),
 		LoginNotEmptySpec(),
+		ClassInCatalogSpec(),
 	)
 }
 
+// ValidateActive checks that the character may change, which every mutating
+// operation of the aggregate requires.
+func ValidateActive(character *Character) error {
+	spec := ActiveSpec()
+	return spec(specifications.Base[CharacterParams]{Entity: NewCharacterParams(character)})
+}
+
+func ActiveSpec() specifications.Specification[CharacterParams] {
+	return func(b specifications.Base[CharacterParams]) error {
+		if status := b.Entity.status; status != StatusActive {
+			return specifications.NewViolation("status", RuleActive, ErrCharacterNotActive, map[string]any{
+				"status": status.String(),
+			})
+		}
+		return nil
+	}
+}
+
+// NicknameSizeSpec measures the nickname in grapheme clusters.
 func NicknameSizeSpec() specifications.Specification[CharacterParams] {
 	return func(b specifications.Base[CharacterParams]) error {
-		nickname := b.Entity.nickname
-		if len(nickname) < 4 || len(nickname) > 15 {
-			return ErrInvalidNicknameSize
+		length := NicknameLength(b.Entity.nickname)
+		if length < MinNicknameLength || length > MaxNicknameLength {
+			return specifications.NewViolation("nickname", RuleLength, ErrInvalidNicknameSize, map[string]any{
+				"min": MinNicknameLength,
+				"max": MaxNicknameLength,
+			})
 		}
 		return nil
 	}
@@ -73,8 +118,10 @@ func NicknameSizeSpec() specifications.Specification[CharacterParams] {
 
 func NotSpecialCharacterSpec() specifications.Specification[CharacterParams] {
 	return func(b specifications.Base[CharacterParams]) error {
-		if hasSpecialCharacters(b.Entity.nickname) {
-			return ErrInvalidNicknameChars
+		if !b.Entity.nicknames.allows(b.Entity.nickname) {
+			return specifications.NewViolation("nickname", RuleCharset, ErrInvalidNicknameChars, map[string]any{
+				"scripts": b.Entity.nicknames.Scripts(),
+			})
 		}
 		return nil
 	}
@@ -83,7 +130,7 @@ func NotSpecialCharacterSpec() specifications.Specification[CharacterParams] {
 func CharacterIDNotEmptySpec() specifications.Specification[CharacterParams] {
 	return func(b specifications.Base[CharacterParams]) error {
 		if b.Entity.characterID.Equals(CharacterID{}) {
-			return ErrEmptyCharacterID
+			return specifications.NewViolation("characterId", RuleRequired, ErrEmptyCharacterID, nil)
 		}
 		return nil
 	}
@@ -92,13 +139,19 @@ func CharacterIDNotEmptySpec() specifications.Specification[CharacterParams] {
 func LoginNotEmptySpec() specifications.Specification[CharacterParams] {
 	return func(b specifications.Base[CharacterParams]) error {
 		if b.Entity.loginID == (login.LoginID{}) {
-			return ErrInvalidLoginId
+			return specifications.NewViolation("loginId", RuleRequired, ErrInvalidLoginId, nil)
 		}
 		return nil
 	}
 }
 
-func hasSpecialCharacters(input string) bool {
-	re := regexp.MustCompile(`[^a-zA-Z0-9]`)
-	return re.MatchString(input)
+func ClassInCatalogSpec() specifications.Specification[CharacterParams] {
+	return func(b specifications.Base[CharacterParams]) error {
+		if _, ok := b.Entity.classes.Definition(b.Entity.class); !ok {
+			return specifications.NewViolation("class", RuleCatalog, ErrInvalidClass, map[string]any{
+				"class": b.Entity.class.String(),
+			})
+		}
+		return nil
+	}
 }
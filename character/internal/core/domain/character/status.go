@@ -0,0 +1,147 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidStatus           = errors.New("invalid character status")
+	ErrCharacterNotActive      = errors.New("character is not active")
+	ErrInvalidStatusTransition = errors.New("character status cannot change this way")
+	ErrMissingStatusReason     = errors.New("a reason is required to change the character status")
+	ErrInvalidSuspensionEnd    = errors.New("suspension must end in the future")
+)
+
+// ReasonSuspensionExpired is the reason recorded when a suspension is lifted
+// because it ran out.
+const ReasonSuspensionExpired = "suspension expired"
+
+// Status is where a character is in its moderation lifecycle. Only active
+// characters can change; the others are frozen until a moderator acts.
+//
+//	active ──suspend──▶ suspended ──lift / expire──▶ active
+//	active, suspended ──ban──▶ banned
+//	active, suspended, banned ──delete──▶ deleted
+type Status int
+
+const (
+	StatusActive Status = iota
+	StatusSuspended
+	StatusBanned
+	StatusDeleted
+)
+
+func (s Status) String() string {
+	return [...]string{"ACTIVE", "SUSPENDED", "BANNED", "DELETED"}[s]
+}
+
+func ParseStatus(s string) (Status, error) {
+	switch strings.ToUpper(s) {
+	case "ACTIVE":
+		return StatusActive, nil
+	case "SUSPENDED":
+		return StatusSuspended, nil
+	case "BANNED":
+		return StatusBanned, nil
+	case "DELETED":
+		return StatusDeleted, nil
+	default:
+		return StatusActive, fmt.Errorf("%w: %s", ErrInvalidStatus, s)
+	}
+}
+
+// Standing is the status of a character together with why it got there.
+// SuspendedUntil is only set while the character is suspended and Reason is
+// empty while it is active.
+type Standing struct {
+	Status         Status
+	SuspendedUntil time.Time
+	Reason         string
+}
+
+// SuspensionExpired reports whether the character is suspended and the
+// suspension ended at or before now.
+func (s Standing) SuspensionExpired(now time.Time) bool {
+	return s.Status == StatusSuspended && !now.Before(s.SuspendedUntil)
+}
+
+func (c *Character) Standing() Standing {
+	return c.standing
+}
+
+// Suspend freezes the character until the given instant. Suspending a
+// suspended character replaces the end and the reason of the suspension.
+func (c *Character) Suspend(until time.Time, reason string, now time.Time) error {
+	if c.standing.Status != StatusActive && c.standing.Status != StatusSuspended {
+		return fmt.Errorf("%w: cannot suspend a %s character", ErrInvalidStatusTransition, c.standing.Status)
+	}
+	reason, err := statusReason(reason)
+	if err != nil {
+		return err
+	}
+	if !until.After(now) {
+		return fmt.Errorf("%w: %s", ErrInvalidSuspensionEnd, until.Format(time.RFC3339))
+	}
+
+	c.standing = Standing{Status: StatusSuspended, SuspendedUntil: until, Reason: reason}
+	c.record(CharacterSuspended{Character: c.CharacterID, Until: until, Reason: reason})
+	return nil
+}
+
+// LiftSuspension makes a suspended character active again before its
+// suspension ends.
+func (c *Character) LiftSuspension(reason string) error {
+	if c.standing.Status != StatusSuspended {
+		return fmt.Errorf("%w: cannot lift the suspension of a %s character", ErrInvalidStatusTransition, c.standing.Status)
+	}
+	reason, err := statusReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.standing = Standing{Status: StatusActive}
+	c.record(SuspensionLifted{Character: c.CharacterID, Reason: reason})
+	return nil
+}
+
+// Ban freezes the character for good.
+func (c *Character) Ban(reason string) error {
+	if c.standing.Status != StatusActive && c.standing.Status != StatusSuspended {
+		return fmt.Errorf("%w: cannot ban a %s character", ErrInvalidStatusTransition, c.standing.Status)
+	}
+	reason, err := statusReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.standing = Standing{Status: StatusBanned, Reason: reason}
+	c.record(CharacterBanned{Character: c.CharacterID, Reason: reason})
+	return nil
+}
+
+// Delete retires the character. The character is kept, so its history and
+// gold stay auditable.
+func (c *Character) Delete(reason string) error {
+	if c.standing.Status == StatusDeleted {
+		return fmt.Errorf("%w: character is already deleted", ErrInvalidStatusTransition)
+	}
+	reason, err := statusReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.standing = Standing{Status: StatusDeleted, Reason: reason}
+	c.record(CharacterDeleted{Character: c.CharacterID, Reason: reason})
+	return nil
+}
+
+func statusReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", ErrMissingStatusReason
+	}
+	return reason, nil
+}
@@ -1,31 +1,152 @@
 package class
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+)
+
+var (
+	ErrInvalidClass         = errors.New("invalid class was provided")
+	ErrInvalidClassID       = errors.New("class id must be 2 to 32 upper case letters, digits or underscores")
+	ErrMissingDisplayName   = errors.New("class display name must be provided")
+	ErrInvalidStartingItem  = errors.New("invalid class starting item")
+	ErrTooManyStartingItems = errors.New("a class cannot start with more items than an inventory holds")
+	ErrInvalidStat          = errors.New("invalid class base stat")
 )
 
-type Class int
+var classIDPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
 
+// Class is the id of a class in the catalog, e.g. "MAGE".
+type Class string
+
+// The classes the game shipped with. Other classes only exist in the
+// catalog.
 const (
-	Mage Class = iota
-	Warrior
-	Ranger
+	Mage    Class = "MAGE"
+	Warrior Class = "WARRIOR"
+	Ranger  Class = "RANGER"
 )
 
 func (c Class) String() string {
-	return [...]string{"MAGE", "WARRIOR", "RANGER"}[c]
-}
-
-func ParseClass(s string) (Class, error) {
-	switch strings.ToUpper(s) {
-	case "MAGE":
-		return Mage, nil
-	case "WARRIOR":
-		return Warrior, nil
-	case "RANGER":
-		return Ranger, nil
-	default:
-		return Mage, fmt.Errorf("invalid class: %s", s)
+	return string(c)
+}
+
+// FromID reads a class id without checking the catalog, for classes that
+// were already validated, such as persisted ones.
+func FromID(s string) Class {
+	return Class(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// ParseClass reads a class id and checks that the catalog defines it.
+func ParseClass(catalog Catalog, s string) (Class, error) {
+	c := FromID(s)
+	if _, ok := catalog.Definition(c); !ok {
+		return c, fmt.Errorf("%w: %s", ErrInvalidClass, s)
+	}
+	return c, nil
+}
+
+// Catalog defines the classes characters can be created with.
+type Catalog interface {
+	Definition(c Class) (Definition, bool)
+}
+
+// Definition is a class as designers define it: the items every character
+// of the class starts with and its base stats. How many starting items fit
+// is up to the inventory, so the catalog checks it when a class is defined.
+type Definition struct {
+	Class         Class
+	DisplayName   string
+	StartingItems []StartingItem
+	Stats         Stats
+}
+
+type StartingItem struct {
+	Item        item.ItemID
+	Description string
+	Quantity    int
+}
+
+// Stats maps a stat name, e.g. "strength", to its base value.
+type Stats map[string]int
+
+func NewDefinition(id string, displayName string, startingItems []StartingItem, stats Stats) (Definition, error) {
+	if !classIDPattern.MatchString(id) {
+		return Definition{}, fmt.Errorf("%w: %q", ErrInvalidClassID, id)
 	}
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return Definition{}, ErrMissingDisplayName
+	}
+
+	for i, startingItem := range startingItems {
+		switch {
+		case startingItem.Item.ID() == uuid.Nil:
+			return Definition{}, fmt.Errorf("%w: item %d has no item id", ErrInvalidStartingItem, i)
+		case strings.TrimSpace(startingItem.Description) == "":
+			return Definition{}, fmt.Errorf("%w: item %d has no description", ErrInvalidStartingItem, i)
+		case startingItem.Quantity <= 0:
+			return Definition{}, fmt.Errorf("%w: item %d quantity must be positive", ErrInvalidStartingItem, i)
+		}
+	}
+
+	for name, value := range stats {
+		if strings.TrimSpace(name) == "" {
+			return Definition{}, fmt.Errorf("%w: stat without a name", ErrInvalidStat)
+		}
+		if value < 0 {
+			return Definition{}, fmt.Errorf("%w: %s cannot be negative", ErrInvalidStat, name)
+		}
+	}
+
+	return Definition{
+		Class:         Class(id),
+		DisplayName:   displayName,
+		StartingItems: startingItems,
+		Stats:         stats,
+	}, nil
+}
+
+// Definitions is a catalog fixed at construction.
+type Definitions map[Class]Definition
+
+func NewDefinitions(definitions ...Definition) Definitions {
+	catalog := make(Definitions, len(definitions))
+	for _, definition := range definitions {
+		catalog[definition.Class] = definition
+	}
+	return catalog
+}
+
+func (d Definitions) Definition(c Class) (Definition, bool) {
+	definition, ok := d[c]
+	return definition, ok
+}
+
+// List returns the definitions ordered by class id.
+func (d Definitions) List() []Definition {
+	definitions := make([]Definition, 0, len(d))
+	for _, definition := range d {
+		definitions = append(definitions, definition)
+	}
+	slices.SortFunc(definitions, func(a, b Definition) int {
+		return strings.Compare(string(a.Class), string(b.Class))
+	})
+	return definitions
+}
+
+// Defaults is the catalog the game shipped with, which the catalog table is
+// seeded with.
+func Defaults() Definitions {
+	return NewDefinitions(
+		Definition{Class: Mage, DisplayName: "Mage", Stats: Stats{"strength": 2, "agility": 3, "intellect": 8, "health": 80}},
+		Definition{Class: Warrior, DisplayName: "Warrior", Stats: Stats{"strength": 8, "agility": 4, "intellect": 2, "health": 120}},
+		Definition{Class: Ranger, DisplayName: "Ranger", Stats: Stats{"strength": 4, "agility": 8, "intellect": 3, "health": 100}},
+	)
 }
@@ -0,0 +1,63 @@
+package class
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+)
+
+func TestNewDefinition(t *testing.T) {
+	mace := StartingItem{Item: item.NewItemID(uuid.New()), Description: "Mace", Quantity: 1}
+
+	tests := []struct {
+		name          string
+		id            string
+		displayName   string
+		startingItems []StartingItem
+		stats         Stats
+		err           error
+	}{
+		{"valid", "PALADIN", "Paladin", []StartingItem{mace}, Stats{"faith": 7}, nil},
+		{"without items or stats", "DRUID_2", "Druid", nil, nil, nil},
+		{"lower case id", "paladin", "Paladin", nil, nil, ErrInvalidClassID},
+		{"one letter id", "P", "Paladin", nil, nil, ErrInvalidClassID},
+		{"blank display name", "PALADIN", "  ", nil, nil, ErrMissingDisplayName},
+		{"item without id", "PALADIN", "Paladin", []StartingItem{{Description: "Mace", Quantity: 1}}, nil, ErrInvalidStartingItem},
+		{"item without description", "PALADIN", "Paladin", []StartingItem{{Item: mace.Item, Quantity: 1}}, nil, ErrInvalidStartingItem},
+		{"item without quantity", "PALADIN", "Paladin", []StartingItem{{Item: mace.Item, Description: "Mace"}}, nil, ErrInvalidStartingItem},
+		{"negative stat", "PALADIN", "Paladin", nil, Stats{"faith": -1}, ErrInvalidStat},
+		{"unnamed stat", "PALADIN", "Paladin", nil, Stats{"": 1}, ErrInvalidStat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, err := NewDefinition(tt.id, tt.displayName, tt.startingItems, tt.stats)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Class(tt.id), definition.Class)
+			assert.Equal(t, tt.displayName, definition.DisplayName)
+		})
+	}
+}
+
+func TestParseClass(t *testing.T) {
+	c, err := ParseClass(Defaults(), " mage ")
+	require.NoError(t, err)
+	assert.Equal(t, Mage, c)
+
+	_, err = ParseClass(Defaults(), "paladin")
+	assert.ErrorIs(t, err, ErrInvalidClass)
+}
+
+func TestDefinitionsList(t *testing.T) {
+	var ids []Class
+	for _, definition := range Defaults().List() {
+		ids = append(ids, definition.Class)
+	}
+	assert.Equal(t, []Class{Mage, Ranger, Warrior}, ids)
+}
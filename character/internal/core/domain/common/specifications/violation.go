@@ -0,0 +1,51 @@
+package specifications
+
+// Violation is a specification failure tied to a field. It wraps the domain
+// sentinel error, so errors.Is keeps working, and carries the rule that was
+// broken together with its parameters (e.g. min/max for a size rule).
+type Violation struct {
+	Field  string
+	Rule   string
+	Params map[string]any
+	Err    error
+}
+
+func NewViolation(field string, rule string, err error, params map[string]any) *Violation {
+	return &Violation{
+		Field:  field,
+		Rule:   rule,
+		Params: params,
+		Err:    err,
+	}
+}
+
+func (v *Violation) Error() string {
+	return v.Err.Error()
+}
+
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// Violations collects every Violation found in the error tree of err,
+// including errors combined with errors.Join or wrapped with %w.
+func Violations(err error) []*Violation {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *Violation:
+		return []*Violation{e}
+	case interface{ Unwrap() []error }:
+		var violations []*Violation
+		for _, inner := range e.Unwrap() {
+			violations = append(violations, Violations(inner)...)
+		}
+		return violations
+	case interface{ Unwrap() error }:
+		return Violations(e.Unwrap())
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/base"
@@ -41,6 +42,19 @@ func NewInventory() *Inventory {
 	}
 }
 
+// Restore rebuilds an inventory from persisted state.
+func Restore(id InventoryID, goldAmount int, items []playeritem.PlayerItem) *Inventory {
+	inventory := &Inventory{
+		InventoryID: id,
+		goldAmount:  goldAmount,
+		items:       make(map[playeritem.PlayerItemID]playeritem.PlayerItem, MAX_ITEMS),
+	}
+	for _, item := range items {
+		inventory.items[item.PlayerItemID] = item
+	}
+	return inventory
+}
+
 func (i *Inventory) AddItem(playeritem playeritem.PlayerItem) error {
 
 	if len(i.items)+1 > MAX_ITEMS {
@@ -86,6 +100,11 @@ func (i *Inventory) WithdrawGold(amount int) error {
 	return nil
 }
 
+func (i *Inventory) FindItem(playerItemID playeritem.PlayerItemID) (playeritem.PlayerItem, bool) {
+	item, ok := i.items[playerItemID]
+	return item, ok
+}
+
 func (i *Inventory) GetCurrentGold() int {
 	return i.goldAmount
 }
@@ -98,6 +117,18 @@ func (i *Inventory) ShowItems() []playeritem.PlayerItemID {
 	return keys
 }
 
+// Items returns the items held in the inventory ordered by id.
+func (i *Inventory) Items() []playeritem.PlayerItem {
+	items := make([]playeritem.PlayerItem, 0, len(i.items))
+	for _, item := range i.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(a, b int) bool {
+		return items[a].ID().String() < items[b].ID().String()
+	})
+	return items
+}
+
 func (pID InventoryID) Equals(otherID InventoryID) bool {
 	return pID.BaseID.Equals(otherID.BaseID)
 }
@@ -0,0 +1,180 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountKind string
+
+const (
+	KindCharacter AccountKind = "character"
+	KindVault     AccountKind = "vault"
+	KindGuild     AccountKind = "guild"
+	KindWorld     AccountKind = "world"
+)
+
+var (
+	ErrInvalidAccount        = errors.New("invalid ledger account")
+	ErrMissingTransactionID  = errors.New("ledger transaction id is required")
+	ErrTooFewEntries         = errors.New("ledger transaction needs at least two entries")
+	ErrZeroEntry             = errors.New("ledger entry amount must not be zero")
+	ErrDuplicateAccount      = errors.New("ledger transaction lists an account twice")
+	ErrUnbalancedTransaction = errors.New("ledger transaction entries do not sum to zero")
+)
+
+// The world accounts stand for gold entering the economy, such as loot, and
+// leaving it, such as gold dropped on the floor. Their balances are the
+// opposite of everything they moved, so the source one is never positive.
+var (
+	WorldSource = AccountID{kind: KindWorld, owner: "source"}
+	WorldSink   = AccountID{kind: KindWorld, owner: "sink"}
+)
+
+// AccountID names a ledger account as kind:owner, e.g. character:<uuid>.
+type AccountID struct {
+	kind  AccountKind
+	owner string
+}
+
+func CharacterAccount(id uuid.UUID) AccountID {
+	return AccountID{kind: KindCharacter, owner: id.String()}
+}
+
+func VaultAccount(id uuid.UUID) AccountID {
+	return AccountID{kind: KindVault, owner: id.String()}
+}
+
+func GuildAccount(id uuid.UUID) AccountID {
+	return AccountID{kind: KindGuild, owner: id.String()}
+}
+
+// ParseAccountID reads the kind:owner form String returns. Character, vault
+// and guild owners must be uuids; the world ones are source and sink.
+func ParseAccountID(value string) (AccountID, error) {
+	kind, owner, ok := strings.Cut(value, ":")
+	if !ok {
+		return AccountID{}, fmt.Errorf("%w: %q is not kind:owner", ErrInvalidAccount, value)
+	}
+
+	switch AccountKind(kind) {
+	case KindCharacter, KindVault, KindGuild:
+		id, err := uuid.Parse(owner)
+		if err != nil || id == uuid.Nil {
+			return AccountID{}, fmt.Errorf("%w: %q must be owned by a uuid", ErrInvalidAccount, value)
+		}
+		return AccountID{kind: AccountKind(kind), owner: id.String()}, nil
+	case KindWorld:
+		if owner != WorldSource.owner && owner != WorldSink.owner {
+			return AccountID{}, fmt.Errorf("%w: %q is neither %s nor %s", ErrInvalidAccount, value, WorldSource, WorldSink)
+		}
+		return AccountID{kind: KindWorld, owner: owner}, nil
+	}
+	return AccountID{}, fmt.Errorf("%w: unknown kind in %q", ErrInvalidAccount, value)
+}
+
+func (a AccountID) Kind() AccountKind {
+	return a.kind
+}
+
+func (a AccountID) Owner() string {
+	return a.owner
+}
+
+func (a AccountID) IsZero() bool {
+	return a == AccountID{}
+}
+
+func (a AccountID) String() string {
+	return string(a.kind) + ":" + a.owner
+}
+
+// Entry moves Amount gold into Account, or out of it when negative.
+type Entry struct {
+	Account AccountID
+	Amount  int
+}
+
+// Transaction is a balanced set of entries: the gold leaving some accounts is
+// exactly the gold entering the others. Its id is chosen by the writer and
+// makes posting idempotent.
+type Transaction struct {
+	id         string
+	reason     string
+	occurredAt time.Time
+	entries    []Entry
+}
+
+func NewTransaction(id, reason string, occurredAt time.Time, entries ...Entry) (*Transaction, error) {
+	if id == "" {
+		return nil, ErrMissingTransactionID
+	}
+	if len(entries) < 2 {
+		return nil, ErrTooFewEntries
+	}
+
+	sum := 0
+	seen := make(map[AccountID]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Account.IsZero() {
+			return nil, fmt.Errorf("%w: missing account", ErrInvalidAccount)
+		}
+		if entry.Amount == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrZeroEntry, entry.Account)
+		}
+		if seen[entry.Account] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateAccount, entry.Account)
+		}
+		seen[entry.Account] = true
+		sum += entry.Amount
+	}
+	if sum != 0 {
+		return nil, fmt.Errorf("%w: off by %d", ErrUnbalancedTransaction, sum)
+	}
+
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b Entry) int {
+		return strings.Compare(a.Account.String(), b.Account.String())
+	})
+
+	return &Transaction{
+		id:         id,
+		reason:     reason,
+		occurredAt: occurredAt,
+		entries:    sorted,
+	}, nil
+}
+
+// Transfer is the common two-entry transaction moving amount from one account
+// to another.
+func Transfer(id, reason string, occurredAt time.Time, from, to AccountID, amount int) (*Transaction, error) {
+	return NewTransaction(id, reason, occurredAt, Entry{Account: from, Amount: -amount}, Entry{Account: to, Amount: amount})
+}
+
+func (t Transaction) ID() string {
+	return t.id
+}
+
+func (t Transaction) Reason() string {
+	return t.reason
+}
+
+func (t Transaction) OccurredAt() time.Time {
+	return t.occurredAt
+}
+
+// Entries returns the entries ordered by account.
+func (t Transaction) Entries() []Entry {
+	return slices.Clone(t.entries)
+}
+
+// SameAs reports whether other records the same movement. A retried write
+// carries the same id, reason and entries, but may have another timestamp.
+func (t Transaction) SameAs(other Transaction) bool {
+	return t.id == other.id && t.reason == other.reason && slices.Equal(t.entries, other.entries)
+}
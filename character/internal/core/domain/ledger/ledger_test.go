@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccountID(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    AccountID
+		wantErr bool
+	}{
+		{"character", "character:" + owner.String(), CharacterAccount(owner), false},
+		{"vault", "vault:" + owner.String(), VaultAccount(owner), false},
+		{"guild", "guild:" + owner.String(), GuildAccount(owner), false},
+		{"world source", "world:source", WorldSource, false},
+		{"world sink", "world:sink", WorldSink, false},
+		{"uppercase uuid is normalized", "vault:" + strings.ToUpper(owner.String()), VaultAccount(owner), false},
+		{"missing separator", "character", AccountID{}, true},
+		{"unknown kind", "bank:" + owner.String(), AccountID{}, true},
+		{"owner is not a uuid", "character:arthas", AccountID{}, true},
+		{"nil owner", "guild:" + uuid.Nil.String(), AccountID{}, true},
+		{"unknown world account", "world:treasury", AccountID{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccountID(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAccount)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got.String(), mustParse(t, got.String()).String(), "String round-trips")
+		})
+	}
+}
+
+func TestNewTransaction(t *testing.T) {
+	character := CharacterAccount(uuid.New())
+	vault := VaultAccount(uuid.New())
+	guild := GuildAccount(uuid.New())
+
+	tests := []struct {
+		name    string
+		id      string
+		entries []Entry
+		wantErr error
+	}{
+		{"transfer", "tx-1", []Entry{{character, -10}, {vault, 10}}, nil},
+		{"split", "tx-1", []Entry{{character, -10}, {vault, 7}, {guild, 3}}, nil},
+		{"missing id", "", []Entry{{character, -10}, {vault, 10}}, ErrMissingTransactionID},
+		{"single entry", "tx-1", []Entry{{character, 10}}, ErrTooFewEntries},
+		{"zero entry", "tx-1", []Entry{{character, -10}, {vault, 10}, {guild, 0}}, ErrZeroEntry},
+		{"repeated account", "tx-1", []Entry{{character, -10}, {character, 10}}, ErrDuplicateAccount},
+		{"missing account", "tx-1", []Entry{{character, -10}, {AccountID{}, 10}}, ErrInvalidAccount},
+		{"unbalanced", "tx-1", []Entry{{character, -10}, {vault, 9}}, ErrUnbalancedTransaction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewTransaction(tt.id, "deposit", time.Now(), tt.entries...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tx)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, tx.Entries(), len(tt.entries))
+		})
+	}
+}
+
+func TestTransactionSameAs(t *testing.T) {
+	character := CharacterAccount(uuid.New())
+	vault := VaultAccount(uuid.New())
+	now := time.Now()
+
+	original, err := Transfer("tx-1", "deposit", now, character, vault, 10)
+	require.NoError(t, err)
+
+	retried, err := NewTransaction("tx-1", "deposit", now.Add(time.Minute), Entry{vault, 10}, Entry{character, -10})
+	require.NoError(t, err)
+	assert.True(t, original.SameAs(*retried), "entry order and timestamp do not matter")
+
+	changed, err := Transfer("tx-1", "deposit", now, character, vault, 11)
+	require.NoError(t, err)
+	assert.False(t, original.SameAs(*changed))
+
+	renamed, err := Transfer("tx-1", "guild tax", now, character, vault, 10)
+	require.NoError(t, err)
+	assert.False(t, original.SameAs(*renamed))
+}
+
+func mustParse(t *testing.T, value string) AccountID {
+	t.Helper()
+	account, err := ParseAccountID(value)
+	require.NoError(t, err)
+	return account
+}
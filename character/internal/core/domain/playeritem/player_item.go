@@ -53,6 +53,16 @@ func NewPlayerItem(itemID item.ItemID, description string, quantity int) (*Playe
 	}, nil
 }
 
+// Restore rebuilds a player item from persisted state without validating it.
+func Restore(id PlayerItemID, itemID item.ItemID, description string, quantity int) PlayerItem {
+	return PlayerItem{
+		PlayerItemID: id,
+		itemID:       itemID,
+		description:  description,
+		quantity:     quantity,
+	}
+}
+
 func (p *PlayerItem) Describe() string {
 	return p.description
 }
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("rate limit must be written as requests/period, e.g. 60/1m, with a positive number of requests and period")
+
+// Limit is a token bucket holding up to Requests tokens and refilled with
+// Requests tokens every Per, so a caller may burst up to Requests requests
+// and then sustain Requests per Per. Every request takes one token.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit reads a limit written as requests/period, e.g. 60/1m.
+func ParseLimit(value string) (Limit, error) {
+	requests, per, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w, got %q", ErrInvalidLimit, value)
+	}
+
+	var (
+		limit Limit
+		err   error
+	)
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil {
+		return Limit{}, fmt.Errorf("%w, got %q", ErrInvalidLimit, value)
+	}
+	if limit.Per, err = time.ParseDuration(strings.TrimSpace(per)); err != nil {
+		return Limit{}, fmt.Errorf("%w, got %q", ErrInvalidLimit, value)
+	}
+	if err := limit.Validate(); err != nil {
+		return Limit{}, fmt.Errorf("%w, got %q", ErrInvalidLimit, value)
+	}
+	return limit, nil
+}
+
+func (l Limit) Validate() error {
+	if l.Requests <= 0 || l.Per <= 0 {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// UnmarshalText lets limits be written as requests/period in config files.
+func (l *Limit) UnmarshalText(text []byte) error {
+	limit, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = limit
+	return nil
+}
+
+// Bucket is the state of one token bucket as of UpdatedAt. The zero Bucket
+// is full.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Decision is the outcome of taking a token.
+type Decision struct {
+	Allowed bool
+	Limit   int
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// RetryAfter is how long a refused caller has to wait for the next
+	// token. It is 0 when the request is allowed.
+	RetryAfter time.Duration
+	// Reset is how long the bucket takes to be full again. A bucket that was
+	// not used for that long may be forgotten.
+	Reset time.Duration
+}
+
+// Take refills b up to now and takes one token from it. It returns the new
+// state of the bucket, which is unchanged apart from the refill when the
+// request is refused.
+func (l Limit) Take(b Bucket, now time.Time) (Bucket, Decision) {
+	capacity := float64(l.Requests)
+	tokens := capacity
+	if !b.UpdatedAt.IsZero() {
+		tokens = b.Tokens
+		if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+			tokens += elapsed.Seconds() * capacity / l.Per.Seconds()
+		}
+		tokens = math.Min(tokens, capacity)
+	}
+
+	decision := Decision{Limit: l.Requests}
+	if tokens >= 1 {
+		tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = l.refill(1 - tokens)
+	}
+	decision.Remaining = int(tokens)
+	decision.Reset = l.refill(capacity - tokens)
+
+	return Bucket{Tokens: tokens, UpdatedAt: now}, decision
+}
+
+// refill is the time the bucket takes to gain tokens.
+func (l Limit) refill(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(l.Per) / float64(l.Requests)))
+}
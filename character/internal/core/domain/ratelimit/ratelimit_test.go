@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{"per minute", "60/1m", Limit{Requests: 60, Per: time.Minute}, false},
+		{"spaces are ignored", " 5 / 10s ", Limit{Requests: 5, Per: 10 * time.Second}, false},
+		{"missing period", "60", Limit{}, true},
+		{"malformed requests", "many/1m", Limit{}, true},
+		{"malformed period", "60/minute", Limit{}, true},
+		{"no requests", "0/1m", Limit{}, true},
+		{"negative period", "60/-1m", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidLimit)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Requests: 2, Per: 10 * time.Second}
+	now := time.Now()
+
+	bucket, decision := limit.Take(Bucket{}, now)
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: 5 * time.Second}, decision, "a new bucket starts full")
+
+	bucket, decision = limit.Take(bucket, now)
+	assert.True(t, decision.Allowed)
+	assert.Zero(t, decision.Remaining)
+	assert.Equal(t, 10*time.Second, decision.Reset)
+
+	bucket, decision = limit.Take(bucket, now.Add(time.Second))
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 4*time.Second, decision.RetryAfter)
+
+	bucket, decision = limit.Take(bucket, now.Add(5*time.Second))
+	assert.True(t, decision.Allowed, "one token was refilled")
+	assert.Zero(t, decision.Remaining)
+
+	_, decision = limit.Take(bucket, now.Add(time.Hour))
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining, "refills stop at the capacity")
+}
+
+func TestTakeWithClockSkew(t *testing.T) {
+	limit := Limit{Requests: 1, Per: time.Minute}
+	now := time.Now()
+
+	bucket, _ := limit.Take(Bucket{}, now)
+	_, decision := limit.Take(bucket, now.Add(-time.Second))
+	assert.False(t, decision.Allowed, "an earlier time never refills the bucket")
+}
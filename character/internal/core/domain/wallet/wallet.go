@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+)
+
+// CreditSource is where premium cash comes from. Cash is bought with real
+// money, so only the sources below may ever credit a wallet.
+type CreditSource string
+
+const (
+	// SourcePurchase is a purchase confirmed by the payment provider.
+	SourcePurchase CreditSource = "purchase"
+	// SourceRefund gives back cash a debit took.
+	SourceRefund CreditSource = "refund"
+	// SourceGrant is cash granted by support.
+	SourceGrant CreditSource = "grant"
+)
+
+var trustedSources = []CreditSource{SourcePurchase, SourceRefund, SourceGrant}
+
+const MaxReferenceLength = 255
+
+var (
+	ErrInvalidLoginID     = errors.New("wallet login id is required")
+	ErrInvalidCashAmount  = errors.New("cash amount must be positive")
+	ErrNotEnoughCash      = errors.New("cannot debit that amount - cash is not enough")
+	ErrUntrustedSource    = errors.New("cash can only be credited from a trusted source")
+	ErrInvalidReference   = errors.New("cash movement reference is required")
+	ErrDuplicateReference = errors.New("cash movement reference was already used")
+)
+
+// Movement is one credit or debit of a wallet. Its reference is chosen by
+// the caller, e.g. the purchase id, and is unique within the wallet, which
+// makes retried movements detectable.
+type Movement struct {
+	Reference string
+	// Amount is positive for credits and negative for debits.
+	Amount int
+	// Source is the CreditSource of a credit or the reason of a debit.
+	Source string
+}
+
+// Wallet holds the premium cash of a login account. Unlike gold it is not
+// carried by a character: every character of the login shares it.
+type Wallet struct {
+	login   login.LoginID
+	balance int
+	version int
+	pending []Movement
+}
+
+func NewWallet(loginId login.LoginID) (*Wallet, error) {
+	if loginId.ID() == uuid.Nil {
+		return nil, ErrInvalidLoginID
+	}
+	return &Wallet{login: loginId}, nil
+}
+
+// Restore rebuilds a wallet from persisted state. version is the one the
+// repository uses to detect concurrent updates.
+func Restore(loginId login.LoginID, balance int, version int) *Wallet {
+	return &Wallet{
+		login:   loginId,
+		balance: balance,
+		version: version,
+	}
+}
+
+func (w *Wallet) LoginID() login.LoginID {
+	return w.login
+}
+
+func (w *Wallet) Balance() int {
+	return w.balance
+}
+
+// Version is 0 for a wallet that was never stored.
+func (w *Wallet) Version() int {
+	return w.version
+}
+
+// PendingMovements returns the movements made since the wallet was loaded.
+func (w *Wallet) PendingMovements() []Movement {
+	return slices.Clone(w.pending)
+}
+
+func (w *Wallet) Credit(reference string, amount int, source CreditSource) error {
+	if !slices.Contains(trustedSources, source) {
+		return fmt.Errorf("%w: %q", ErrUntrustedSource, source)
+	}
+	return w.move(Movement{Reference: reference, Amount: amount, Source: string(source)}, amount)
+}
+
+func (w *Wallet) Debit(reference string, amount int, reason string) error {
+	if amount > w.balance {
+		return ErrNotEnoughCash
+	}
+	return w.move(Movement{Reference: reference, Amount: -amount, Source: reason}, amount)
+}
+
+func (w *Wallet) move(movement Movement, amount int) error {
+	if movement.Reference == "" || len(movement.Reference) > MaxReferenceLength {
+		return ErrInvalidReference
+	}
+	if amount <= 0 {
+		return ErrInvalidCashAmount
+	}
+	if slices.ContainsFunc(w.pending, func(m Movement) bool { return m.Reference == movement.Reference }) {
+		return fmt.Errorf("%w: %s", ErrDuplicateReference, movement.Reference)
+	}
+
+	w.balance += movement.Amount
+	w.pending = append(w.pending, movement)
+	return nil
+}
@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+)
+
+func TestNewWallet(t *testing.T) {
+	w, err := NewWallet(login.NewLoginID(uuid.New()))
+	require.NoError(t, err)
+	assert.Zero(t, w.Balance())
+	assert.Zero(t, w.Version())
+
+	_, err = NewWallet(login.LoginID{})
+	assert.ErrorIs(t, err, ErrInvalidLoginID)
+}
+
+func TestWalletCredit(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		amount    int
+		source    CreditSource
+		wantErr   error
+	}{
+		{"purchase", "purchase-1", 500, SourcePurchase, nil},
+		{"refund", "refund-1", 5, SourceRefund, nil},
+		{"grant", "grant-1", 5, SourceGrant, nil},
+		{"untrusted source", "gift-1", 5, CreditSource("player gift"), ErrUntrustedSource},
+		{"zero amount", "purchase-1", 0, SourcePurchase, ErrInvalidCashAmount},
+		{"negative amount", "purchase-1", -5, SourcePurchase, ErrInvalidCashAmount},
+		{"missing reference", "", 5, SourcePurchase, ErrInvalidReference},
+		{"reference too long", strings.Repeat("r", MaxReferenceLength+1), 5, SourcePurchase, ErrInvalidReference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Restore(login.NewLoginID(uuid.New()), 10, 1)
+
+			err := w.Credit(tt.reference, tt.amount, tt.source)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, 10, w.Balance())
+				assert.Empty(t, w.PendingMovements())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 10+tt.amount, w.Balance())
+			assert.Equal(t, []Movement{{Reference: tt.reference, Amount: tt.amount, Source: string(tt.source)}}, w.PendingMovements())
+		})
+	}
+}
+
+func TestWalletDebit(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int
+		wantErr error
+	}{
+		{"part of the balance", 30, nil},
+		{"whole balance", 100, nil},
+		{"more than the balance", 101, ErrNotEnoughCash},
+		{"zero amount", 0, ErrInvalidCashAmount},
+		{"negative amount", -5, ErrInvalidCashAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Restore(login.NewLoginID(uuid.New()), 100, 1)
+
+			err := w.Debit("order-1", tt.amount, "cosmetic")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, 100, w.Balance(), "a failed debit takes nothing")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 100-tt.amount, w.Balance())
+			assert.Equal(t, []Movement{{Reference: "order-1", Amount: -tt.amount, Source: "cosmetic"}}, w.PendingMovements())
+		})
+	}
+}
+
+func TestWalletRejectsRepeatedReference(t *testing.T) {
+	w := Restore(login.NewLoginID(uuid.New()), 0, 1)
+
+	require.NoError(t, w.Credit("purchase-1", 50, SourcePurchase))
+	assert.ErrorIs(t, w.Debit("purchase-1", 10, "cosmetic"), ErrDuplicateReference)
+	assert.Equal(t, 50, w.Balance())
+}
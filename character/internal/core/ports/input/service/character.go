@@ -2,20 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
 	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
 )
 
+var ErrCannotCreateCharacter = errors.New("cannot create a character")
+
 type CharacterService interface {
+	// CreateCharacter fails with ErrCannotCreateCharacter, wrapping
+	// ErrUnknownLogin when the login does not exist and the domain errors
+	// when the class or nickname are not valid.
 	CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error
 	TransferItemTo(ctx context.Context, characterId character.CharacterID, playeritem playeritem.PlayerItem, quantity int, vaultId vault.VaultID) error
 	TradeItem(ctx context.Context, origin character.CharacterID, playeritem playeritem.PlayerItem, quantity int, destiny character.CharacterID) error
 	DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) error
-	PickItem() error
-	DropItem(ctx context.Context, playerItemID playeritem.PlayerItemID, quantity int) error
+	PickItem(ctx context.Context, characterId character.CharacterID, itemId item.ItemID, description string, quantity int) error
+	DropItem(ctx context.Context, characterId character.CharacterID, playerItemID playeritem.PlayerItemID) error
+	PickGold(ctx context.Context, characterId character.CharacterID, amount int) error
 	LeaveGuild(ctx context.Context, characterID character.CharacterID) error
 }
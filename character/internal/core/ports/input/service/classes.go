@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+)
+
+// Classes is the catalog characters pick their class from. Designers define
+// classes at runtime; a class defined on one instance reaches the others
+// on their next refresh.
+type Classes interface {
+	class.Catalog
+	ListClasses(ctx context.Context) ([]class.Definition, error)
+	// DefineClass creates the class or replaces its definition. Characters
+	// already created keep the items they started with.
+	DefineClass(ctx context.Context, definition class.Definition) error
+}
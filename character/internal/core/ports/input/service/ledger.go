@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+)
+
+var (
+	ErrCharacterAccountPosting = errors.New("character accounts only move through character events")
+	ErrReservedTransactionID   = errors.New("transaction id is reserved for character events")
+)
+
+// Ledger records gold moving between the accounts of the game. Character
+// accounts are posted from the character events, so Post only takes the
+// movements other services own, such as vault and guild transfers.
+type Ledger interface {
+	// Post fails with ErrCharacterAccountPosting when tx touches a
+	// character account, and with ErrReservedTransactionID when its id is
+	// one the character events are posted under. It is idempotent: posting the same transaction id
+	// again with the same entries succeeds without moving any gold.
+	Post(ctx context.Context, tx ledger.Transaction) error
+	Balance(ctx context.Context, account ledger.AccountID) (int, error)
+}
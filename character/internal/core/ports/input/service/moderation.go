@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+)
+
+// Moderation changes the standing of characters on behalf of game masters.
+// Each change returns the standing it left the character in, and fails with
+// character.ErrInvalidStatusTransition when the current status does not
+// allow it.
+type Moderation interface {
+	// Suspend freezes the character until the given instant, after which
+	// the suspension is lifted automatically.
+	Suspend(ctx context.Context, characterId character.CharacterID, until time.Time, reason string) (character.Standing, error)
+	LiftSuspension(ctx context.Context, characterId character.CharacterID, reason string) (character.Standing, error)
+	Ban(ctx context.Context, characterId character.CharacterID, reason string) (character.Standing, error)
+}
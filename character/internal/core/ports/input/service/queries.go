@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// MaxSearchLimit bounds the characters returned by one search.
+const MaxSearchLimit = 100
+
+var ErrInvalidSearchLimit = errors.New("search limit must be between 1 and 100")
+
+// CharacterPage is one page of a character search.
+type CharacterPage struct {
+	Characters []repository.CharacterView
+	// Next is where the following page starts, nil on the last page.
+	Next *repository.CharacterSearchKey
+}
+
+// CharacterQueries reads the character read model. Views are eventually
+// consistent: a change is visible once the projector has applied its events.
+type CharacterQueries interface {
+	GetCharacter(ctx context.Context, characterId character.CharacterID) (*repository.CharacterView, error)
+	// InventoryHistory reads the event store directly, so it is never behind.
+	InventoryHistory(ctx context.Context, characterId character.CharacterID, filter repository.InventoryHistoryFilter) ([]repository.RecordedEvent, error)
+	// SearchCharacters returns a page of at most search.Limit characters,
+	// failing with ErrInvalidSearchLimit beyond MaxSearchLimit.
+	SearchCharacters(ctx context.Context, search repository.CharacterSearch) (*CharacterPage, error)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+)
+
+// CharacterTransfer moves characters between environments. The ids owned by
+// the character are never carried over: an import always creates a new
+// character, inventory and player items, so importing the same export twice
+// yields two characters.
+type CharacterTransfer interface {
+	Export(ctx context.Context, characterId character.CharacterID) (*character.Character, error)
+	// Import fails with ErrUnknownLogin when the login does not exist.
+	Import(ctx context.Context, imported CharacterImport) (ImportedCharacter, error)
+}
+
+// CharacterImport is the state an imported character starts from. Login,
+// guild, vault and item ids reference other services and are taken as they
+// are, so they must already be valid in the environment imported into. A
+// zero Level is the starting level.
+type CharacterImport struct {
+	Login    login.LoginID
+	Nickname string
+	Class    class.Class
+	Level    int
+	Gold     int
+	Items    []ItemImport
+	Guild    guild.GuildID
+	Vault    vault.VaultID
+	Standing character.Standing
+}
+
+type ItemImport struct {
+	// Source is the id of the player item in the exported character.
+	Source      playeritem.PlayerItemID
+	Item        item.ItemID
+	Description string
+	Quantity    int
+}
+
+// ImportedCharacter holds the ids the import assigned. Items maps the player
+// item ids of the export to the new ones.
+type ImportedCharacter struct {
+	Character character.CharacterID
+	Inventory inventory.InventoryID
+	Items     map[playeritem.PlayerItemID]playeritem.PlayerItemID
+}
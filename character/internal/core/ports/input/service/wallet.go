@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+)
+
+var ErrUnknownLogin = errors.New("login does not exist")
+
+// Wallets moves the premium cash of the login accounts. Both movements are
+// idempotent: repeating one with the same reference and amount returns the
+// wallet without moving cash again, while reusing the reference for another
+// movement fails with wallet.ErrDuplicateReference.
+type Wallets interface {
+	// GetWallet returns an empty wallet for a login that never had cash.
+	GetWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error)
+	// CreditPurchase credits cash bought through the payment provider, using
+	// purchaseId as the reference. It fails with ErrUnknownLogin when the
+	// login does not exist.
+	CreditPurchase(ctx context.Context, loginId login.LoginID, purchaseId string, amount int) (*wallet.Wallet, error)
+	Debit(ctx context.Context, loginId login.LoginID, reference string, amount int, reason string) (*wallet.Wallet, error)
+}
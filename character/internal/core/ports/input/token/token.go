@@ -4,4 +4,6 @@ import "context"
 
 type AuthService interface {
 	TokenValidation(ctx context.Context, token string) (bool, error)
+	// Authenticate verifies token and returns the subject it was issued to.
+	Authenticate(ctx context.Context, token string) (string, error)
 }
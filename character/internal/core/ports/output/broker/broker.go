@@ -0,0 +1,19 @@
+package broker
+
+import "context"
+
+type Message struct {
+	ID      string
+	Key     string
+	Payload []byte
+	Headers map[string]string
+}
+
+type Handler func(ctx context.Context, msg Message) error
+
+type Broker interface {
+	Publish(ctx context.Context, topic string, msg Message) error
+	// Subscribe delivers the messages of topic to handler until ctx is done.
+	// A message the handler returns an error for is delivered again.
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+}
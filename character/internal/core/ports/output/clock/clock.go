@@ -0,0 +1,9 @@
+package clock
+
+import "time"
+
+// Clock tells the current time, so time-dependent behaviour such as key
+// expiry can be driven by tests.
+type Clock interface {
+	Now() time.Time
+}
@@ -1,7 +1,11 @@
 package gateway
 
-import "github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+)
 
 type Vault interface {
-	CreateVault() (vault.VaultID, error)
+	CreateVault(ctx context.Context) (vault.VaultID, error)
 }
@@ -0,0 +1,29 @@
+package logger
+
+import "context"
+
+// Correlation fields shared by every log line of a request.
+const (
+	FieldRequestID   = "requestId"
+	FieldSubject     = "subject"
+	FieldCharacterID = "characterId"
+)
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx that also carries the key-value
+// pairs in args. Fields already stored in ctx are kept.
+func ContextWithFields(ctx context.Context, args ...interface{}) context.Context {
+	current := FieldsFromContext(ctx)
+	fields := make([]interface{}, 0, len(current)+len(args))
+	fields = append(fields, current...)
+	fields = append(fields, args...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// FieldsFromContext returns the key-value pairs stored in ctx by
+// ContextWithFields.
+func FieldsFromContext(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+	return fields
+}
@@ -1,8 +1,15 @@
 package logger
 
+import "context"
+
 type Logger interface {
 	Info(msg string, args ...interface{})
 	Warn(msg string, args ...interface{})
 	Error(msg string, args ...interface{})
 	Debug(msg string, args ...interface{})
+	// With returns a logger that adds the key-value pairs in args to every line.
+	With(args ...interface{}) Logger
+	// WithContext returns a logger that adds the correlation fields stored in
+	// ctx to every line.
+	WithContext(ctx context.Context) Logger
 }
@@ -0,0 +1,13 @@
+package logger
+
+import "context"
+
+// Nop discards every line. It stands in for a logger in tests.
+type Nop struct{}
+
+func (Nop) Info(msg string, args ...interface{})   {}
+func (Nop) Warn(msg string, args ...interface{})   {}
+func (Nop) Error(msg string, args ...interface{})  {}
+func (Nop) Debug(msg string, args ...interface{})  {}
+func (Nop) With(args ...interface{}) Logger        { return Nop{} }
+func (Nop) WithContext(ctx context.Context) Logger { return Nop{} }
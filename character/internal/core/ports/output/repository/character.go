@@ -2,12 +2,41 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
 )
 
+var (
+	ErrCharacterNotFound = errors.New("character not found")
+	ErrConcurrentUpdate  = errors.New("character was modified by another operation")
+)
+
+// CharacterRepository persists the character aggregate together with its
+// inventory. Update only succeeds when the stored version still matches the
+// version the character was loaded at; otherwise it fails with
+// ErrConcurrentUpdate and the caller has to load the character again.
+// Save and Update append the pending events of the character to the event
+// log in the same transaction as its state.
 type CharacterRepository interface {
 	FindCharacterById(ctx context.Context, characterId character.CharacterID) (*character.Character, error)
 	Save(ctx context.Context, character character.Character) error
 	Update(ctx context.Context, character character.Character) error
 }
+
+// CharacterGold lists the characters holding gold, so the ledger can be
+// reconciled with every one of them. It reads the characters the character
+// repository stores.
+type CharacterGold interface {
+	// CharactersWithGold returns the characters whose inventory holds gold.
+	CharactersWithGold(ctx context.Context) ([]character.CharacterID, error)
+}
+
+// CharacterSuspensions finds the suspensions that ran out, so they can be
+// lifted. It reads the characters the character repository stores.
+type CharacterSuspensions interface {
+	// ExpiredSuspensions returns at most limit suspended characters whose
+	// suspension ended at or before now, the longest ended first.
+	ExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]character.CharacterID, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+)
+
+// RecordedEvent is a character event as stored in the event log. Position
+// orders the events of every character and only grows, although it may have
+// gaps.
+type RecordedEvent struct {
+	Position   int64
+	OccurredAt time.Time
+	Event      character.Event
+}
+
+// CharacterEventLog reads the events CharacterRepository appends on Save and
+// Update, in the order they were recorded.
+type CharacterEventLog interface {
+	// ReadEvents returns up to limit events stored after position.
+	ReadEvents(ctx context.Context, after int64, limit int) ([]RecordedEvent, error)
+	// LastPosition returns the position of the newest event, 0 when empty.
+	LastPosition(ctx context.Context) (int64, error)
+}
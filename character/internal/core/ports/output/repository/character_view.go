@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CharacterView is the denormalised read model of a character and its
+// inventory. It is projected from the event log, so it may lag behind the
+// character aggregate.
+type CharacterView struct {
+	CharacterID uuid.UUID
+	LoginID     uuid.UUID
+	Nickname    string
+	Class       string
+	InventoryID uuid.UUID
+	GuildID     uuid.UUID
+	VaultID     uuid.UUID
+	Level       int
+	Gold        int
+	// Items are ordered by player item id.
+	Items []ItemView
+	// Position is the position of the last event applied to the view.
+	Position int64
+}
+
+// CharacterSort is the order of a character search. Ties are broken by
+// character id, so the order is total.
+type CharacterSort string
+
+const (
+	// SortByNickname orders case-insensitively.
+	SortByNickname CharacterSort = "nickname"
+	SortByGold     CharacterSort = "gold"
+)
+
+// CharacterSearchKey is the place of a character in the search order. Only
+// the field of the sort and the character id are used.
+type CharacterSearchKey struct {
+	Nickname    string
+	Gold        int
+	CharacterID uuid.UUID
+}
+
+// CharacterSearch selects character views. Zero filters match everything.
+type CharacterSearch struct {
+	// NicknamePrefix matches case-insensitively.
+	NicknamePrefix string
+	Class          string
+	GuildID        uuid.UUID
+	// MinLevel and MaxLevel bound the level, both included.
+	MinLevel   int
+	MaxLevel   int
+	Sort       CharacterSort
+	Descending bool
+	// After, when set, skips the characters up to and including that key,
+	// which is the key of the last character of the previous page.
+	After *CharacterSearchKey
+	Limit int
+}
+
+type ItemView struct {
+	PlayerItemID uuid.UUID
+	ItemID       uuid.UUID
+	Description  string
+	Quantity     int
+}
+
+// CharacterViewRepository stores the character read model together with the
+// position of the last event projected into it.
+type CharacterViewRepository interface {
+	// FindCharacterView fails with ErrCharacterNotFound when the character
+	// has not been projected yet.
+	FindCharacterView(ctx context.Context, characterId uuid.UUID) (*CharacterView, error)
+	// SaveCharacterView stores view and moves the checkpoint to view.Position
+	// atomically.
+	SaveCharacterView(ctx context.Context, view CharacterView) error
+	// SearchCharacterViews returns at most search.Limit views in the order
+	// of search. Their items are not loaded.
+	SearchCharacterViews(ctx context.Context, search CharacterSearch) ([]CharacterView, error)
+	Checkpoint(ctx context.Context) (int64, error)
+	SaveCheckpoint(ctx context.Context, position int64) error
+	// Reset drops every view and the checkpoint, so the projection can be
+	// rebuilt from the first event.
+	Reset(ctx context.Context) error
+}
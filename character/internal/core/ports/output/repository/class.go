@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+)
+
+// ClassRepository stores the class catalog designers edit. SaveClass
+// creates the class or replaces its definition.
+type ClassRepository interface {
+	ListClasses(ctx context.Context) ([]class.Definition, error)
+	SaveClass(ctx context.Context, definition class.Definition) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrIdempotencyKeyInUse    = errors.New("idempotency key is already reserved")
+)
+
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Completed   bool
+	ExpiresAt   time.Time
+}
+
+type IdempotencyRepository interface {
+	Find(ctx context.Context, key string) (*IdempotencyRecord, error)
+	Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) error
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
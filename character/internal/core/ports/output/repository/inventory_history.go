@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+)
+
+// InventoryHistoryFilter narrows the inventory history of a character. Zero
+// fields do not filter.
+type InventoryHistoryFilter struct {
+	// From is the first instant included.
+	From time.Time
+	// To is the first instant excluded.
+	To time.Time
+	// ItemID matches either the player item id or the catalog item id, so
+	// support can follow one sword or every sword the character held.
+	ItemID uuid.UUID
+}
+
+// Matches reports whether the recorded inventory event passes the filter.
+func (f InventoryHistoryFilter) Matches(recorded RecordedEvent) bool {
+	if !f.From.IsZero() && recorded.OccurredAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !recorded.OccurredAt.Before(f.To) {
+		return false
+	}
+	if f.ItemID == uuid.Nil {
+		return true
+	}
+
+	switch e := recorded.Event.(type) {
+	case character.ItemAdded:
+		return e.Item.ID() == f.ItemID || e.Item.ItemID().ID() == f.ItemID
+	case character.ItemDropped:
+		return e.Item.ID() == f.ItemID || e.Item.ItemID().ID() == f.ItemID
+	}
+	return false
+}
+
+// InventoryHistory reads the inventory events the character repository
+// appended, which form the audit trail of every inventory.
+type InventoryHistory interface {
+	// InventoryHistory returns the inventory events of the character that
+	// pass filter, oldest first. It returns ErrCharacterNotFound when the
+	// character does not exist.
+	InventoryHistory(ctx context.Context, characterId character.CharacterID, filter InventoryHistoryFilter) ([]RecordedEvent, error)
+}
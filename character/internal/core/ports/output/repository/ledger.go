@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+)
+
+var ErrTransactionConflict = errors.New("ledger transaction id was already posted with other entries")
+
+type AccountBalance struct {
+	Account ledger.AccountID
+	Balance int
+}
+
+// LedgerRepository stores the double-entry gold ledger together with the
+// position of the last character event posted into it.
+type LedgerRepository interface {
+	// Post stores tx and moves the balances of its accounts atomically.
+	// Posting an id that is stored already does nothing when tx is the same
+	// movement, and fails with ErrTransactionConflict otherwise.
+	Post(ctx context.Context, tx ledger.Transaction) error
+	// Balance is 0 for an account that never moved gold.
+	Balance(ctx context.Context, account ledger.AccountID) (int, error)
+	// Balances lists the accounts of kind that moved gold, ordered by
+	// account.
+	Balances(ctx context.Context, kind ledger.AccountKind) ([]AccountBalance, error)
+	Checkpoint(ctx context.Context) (int64, error)
+	SaveCheckpoint(ctx context.Context, position int64) error
+}
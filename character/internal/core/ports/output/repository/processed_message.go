@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrMessageAlreadyProcessed = errors.New("message was already processed")
+
+// ProcessedMessageRepository tells which broker messages were applied. The
+// character repository records the message id carried by the context of
+// Update in the same transaction as the character, so a message is either
+// applied and recorded or neither.
+type ProcessedMessageRepository interface {
+	IsProcessed(ctx context.Context, messageID string) (bool, error)
+}
+
+type messageIDKey struct{}
+
+// WithMessageID tags ctx with the id of the broker message being applied.
+// An Update made with it fails with ErrMessageAlreadyProcessed, and changes
+// nothing, when the message was recorded before.
+func WithMessageID(ctx context.Context, messageID string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, messageID)
+}
+
+// MessageIDFromContext returns the message id set by WithMessageID.
+func MessageIDFromContext(ctx context.Context) (string, bool) {
+	messageID, ok := ctx.Value(messageIDKey{}).(string)
+	return messageID, ok && messageID != ""
+}
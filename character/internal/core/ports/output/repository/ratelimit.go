@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+)
+
+// RateLimitRepository keeps the token buckets of the rate limiter. Replicas
+// sharing an implementation share the buckets.
+type RateLimitRepository interface {
+	// Take takes one token as of now from the bucket of key, which is
+	// refilled following limit. Taking from several replicas at once must
+	// never hand out the same token twice.
+	Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+)
+
+var (
+	ErrWalletNotFound         = errors.New("wallet not found")
+	ErrConcurrentWalletUpdate = errors.New("wallet was modified by another operation")
+	ErrMovementNotFound       = errors.New("cash movement not found")
+)
+
+// WalletRepository persists the premium cash wallets together with their
+// movements. SaveWallet inserts a wallet at version 0 and otherwise only
+// succeeds while the stored version still matches the loaded one, failing
+// with ErrConcurrentWalletUpdate. The pending movements are stored in the
+// same transaction; a reference stored already for the wallet fails with
+// wallet.ErrDuplicateReference.
+type WalletRepository interface {
+	FindWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error)
+	SaveWallet(ctx context.Context, w wallet.Wallet) error
+	FindMovement(ctx context.Context, loginId login.LoginID, reference string) (*wallet.Movement, error)
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
 	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
 	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
 	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
@@ -18,50 +20,74 @@ import (
 	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
 )
 
-var (
-	ErrWhileCreation = errors.New("an error occur during creating")
-)
-
 type CharacterServiceImpl struct {
 	vaultGateway        gateway.Vault
+	loginGateway        gateway.Login
 	characterRepository repository.CharacterRepository
+	classes             class.Catalog
+	nicknames           character.NicknameRules
 	logger              logger.Logger
 }
 
-func NewCharacterService(characterRepository repository.CharacterRepository, vaultGateway gateway.Vault, logger logger.Logger) service.CharacterService {
+func NewCharacterService(characterRepository repository.CharacterRepository, vaultGateway gateway.Vault, loginGateway gateway.Login, classes class.Catalog, nicknames character.NicknameRules, logger logger.Logger) service.CharacterService {
 	return &CharacterServiceImpl{
 		vaultGateway:        vaultGateway,
+		loginGateway:        loginGateway,
 		characterRepository: characterRepository,
+		classes:             classes,
+		nicknames:           nicknames,
 		logger:              logger,
 	}
 }
 
-func (s *CharacterServiceImpl) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error {
-	s.logger.Info("Creating character", "loginId", loginId, "nickname", nickname, "class", class)
+// CreateCharacter checks the login and class before creating the vault, so a
+// rejected character leaves no vault behind.
+func (s *CharacterServiceImpl) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, characterClass class.Class) error {
+	log := s.logger.WithContext(ctx)
+	log.Info("Creating character", "loginId", loginId, "nickname", nickname, "class", characterClass)
+
+	ok, err := s.loginGateway.IsLoginValid(ctx, loginId)
+	if err != nil {
+		log.Error("Failed to check login", "error", err)
+		return fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %w: %s", service.ErrCannotCreateCharacter, service.ErrUnknownLogin, loginId.ID())
+	}
+
+	characterClass, err = class.ParseClass(s.classes, string(characterClass))
+	if err != nil {
+		return fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err)
+	}
 
-	vaultId, err := s.vaultGateway.CreateVault()
+	vaultId, err := s.vaultGateway.CreateVault(ctx)
 	if err != nil {
-		s.logger.Error("Failed to create vault", "error", err)
-		return fmt.Errorf("%w: %v", ErrWhileCreation, err)
+		log.Error("Failed to create vault", "error", err)
+		return fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err)
 	}
 
-	character, err := character.CreateNewCharacter(nickname, loginId, class, vaultId)
+	character, err := character.CreateNewCharacter(s.classes, s.nicknames, nickname, loginId, characterClass, vaultId)
 	if err != nil {
-		s.logger.Error("Failed to create character entity", "error", err)
-		return fmt.Errorf("%w: %v", ErrWhileCreation, err)
+		log.Error("Failed to create character entity", "error", err)
+		return fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err)
 	}
 
+	ctx = withCharacter(ctx, character.CharacterID)
+	log = s.logger.WithContext(ctx)
+
 	err = s.characterRepository.Save(ctx, *character)
 	if err != nil {
-		s.logger.Error("Failed to save character", "error", err)
-		return err
+		log.Error("Failed to save character", "error", err)
+		return fmt.Errorf("%w: %w", service.ErrCannotCreateCharacter, err)
 	}
 
-	s.logger.Info("Character created successfully", "nickname", nickname)
+	log.Info("Character created successfully", "nickname", nickname)
 	return nil
 }
 
 func (s *CharacterServiceImpl) TransferItemTo(ctx context.Context, characterId character.CharacterID, playeritem playeritem.PlayerItem, quantity int, vaultId vault.VaultID) error {
+	ctx = withCharacter(ctx, characterId)
+
 	character, err := s.characterRepository.FindCharacterById(ctx, characterId)
 	if err != nil {
 		return fmt.Errorf("failed to find character: %w", err)
@@ -91,6 +117,8 @@ func (s *CharacterServiceImpl) TradeItem(ctx context.Context, origin character.C
 }
 
 func (s *CharacterServiceImpl) DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) error {
+	ctx = withCharacter(ctx, characterId)
+
 	character, err := s.characterRepository.FindCharacterById(ctx, characterId)
 	if err != nil {
 		return fmt.Errorf("failed to find character: %w", err)
@@ -101,8 +129,8 @@ func (s *CharacterServiceImpl) DepositGold(ctx context.Context, characterId char
 		return errors.New("character does not own this vault")
 	}
 
-	// Drop gold from character's inventory
-	if err := character.DropGold(quantity); err != nil {
+	// Move gold from character's inventory into the vault
+	if err := character.DepositGold(quantity, vaultId); err != nil {
 		return fmt.Errorf("failed to drop gold from inventory: %w", err)
 	}
 
@@ -114,22 +142,88 @@ func (s *CharacterServiceImpl) DepositGold(ctx context.Context, characterId char
 	return nil
 }
 
-func (s *CharacterServiceImpl) PickItem() error {
-	return errors.New("method not implemented - requires character ID and item parameters")
+func (s *CharacterServiceImpl) PickItem(ctx context.Context, characterId character.CharacterID, itemId item.ItemID, description string, quantity int) error {
+	ctx = withCharacter(ctx, characterId)
+
+	character, err := s.characterRepository.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return fmt.Errorf("failed to find character: %w", err)
+	}
+
+	newItem, err := playeritem.NewPlayerItem(itemId, description, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to create player item: %w", err)
+	}
+
+	if err := character.PickItem(*newItem); err != nil {
+		return fmt.Errorf("failed to pick item: %w", err)
+	}
+
+	// Save the updated character state
+	if err := s.characterRepository.Update(ctx, *character); err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+
+	return nil
 }
 
-func (s *CharacterServiceImpl) DropItem(ctx context.Context, playerItemID playeritem.PlayerItemID, quantity int) error {
-	return errors.New("method not implemented - requires character ID parameter")
+func (s *CharacterServiceImpl) DropItem(ctx context.Context, characterId character.CharacterID, playerItemID playeritem.PlayerItemID) error {
+	ctx = withCharacter(ctx, characterId)
+
+	character, err := s.characterRepository.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return fmt.Errorf("failed to find character: %w", err)
+	}
+
+	item, ok := character.FindItem(playerItemID)
+	if !ok {
+		return fmt.Errorf("failed to drop item: %w", inventory.ErrPlayerItemNotFound)
+	}
+
+	if err := character.DropItem(item); err != nil {
+		return fmt.Errorf("failed to drop item: %w", err)
+	}
+
+	// Save the updated character state
+	if err := s.characterRepository.Update(ctx, *character); err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+
+	return nil
+}
+
+func (s *CharacterServiceImpl) PickGold(ctx context.Context, characterId character.CharacterID, amount int) error {
+	ctx = withCharacter(ctx, characterId)
+
+	character, err := s.characterRepository.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return fmt.Errorf("failed to find character: %w", err)
+	}
+
+	if err := character.PickGold(amount); err != nil {
+		return fmt.Errorf("failed to pick gold: %w", err)
+	}
+
+	// Save the updated character state
+	if err := s.characterRepository.Update(ctx, *character); err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+
+	return nil
 }
 
 func (s *CharacterServiceImpl) LeaveGuild(ctx context.Context, characterID character.CharacterID) error {
+	ctx = withCharacter(ctx, characterID)
+
 	character, err := s.characterRepository.FindCharacterById(ctx, characterID)
 	if err != nil {
 		return fmt.Errorf("failed to find character: %w", err)
 	}
 
 	// Update guild info to empty guild
-	character.UpdateGuildInfo(guild.NewGuildID(uuid.Nil))
+	if err := character.UpdateGuildInfo(guild.NewGuildID(uuid.Nil)); err != nil {
+		return fmt.Errorf("failed to leave guild: %w", err)
+	}
 
 	// Save the updated character state
 	if err := s.characterRepository.Update(ctx, *character); err != nil {
@@ -138,3 +232,9 @@ func (s *CharacterServiceImpl) LeaveGuild(ctx context.Context, characterID chara
 
 	return nil
 }
+
+// withCharacter tags ctx with the character an operation works on, so the
+// log lines written below the service carry its id.
+func withCharacter(ctx context.Context, characterId character.CharacterID) context.Context {
+	return logger.ContextWithFields(ctx, logger.FieldCharacterID, characterId.ID().String())
+}
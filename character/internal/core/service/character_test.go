@@ -8,12 +8,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
 	"github.com/vterry/ddd-study/character/internal/core/domain/character"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
 	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
 	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 )
 
 // MockVaultService is a mock implementation of VaultService
@@ -21,8 +28,8 @@ type MockVaultService struct {
 	mock.Mock
 }
 
-func (m *MockVaultService) CreateVault() (vault.VaultID, error) {
-	args := m.Called()
+func (m *MockVaultService) CreateVault(ctx context.Context) (vault.VaultID, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(vault.VaultID), args.Error(1)
 }
 
@@ -49,16 +56,6 @@ func (m *MockCharacterRepository) Update(ctx context.Context, character characte
 	return args.Error(0)
 }
 
-// MockLogger is a stub implementation of logger.Logger for tests
-// It does nothing
-
-type MockLogger struct{}
-
-func (m *MockLogger) Info(msg string, args ...interface{})  {}
-func (m *MockLogger) Warn(msg string, args ...interface{})  {}
-func (m *MockLogger) Error(msg string, args ...interface{}) {}
-func (m *MockLogger) Debug(msg string, args ...interface{}) {}
-
 func TestCreateCharacter(t *testing.T) {
 	validLogin := login.NewLoginID(uuid.New())
 
@@ -76,7 +73,7 @@ func TestCreateCharacter(t *testing.T) {
 			nickname: "TestChar",
 			class:    class.Warrior,
 			setupMocks: func(vs *MockVaultService, cr *MockCharacterRepository) {
-				vs.On("CreateVault").Return(vault.NewVaultID(uuid.New()), nil)
+				vs.On("CreateVault", mock.Anything).Return(vault.NewVaultID(uuid.New()), nil)
 				cr.On("Save", mock.Anything, mock.AnythingOfType("Character")).Return(nil)
 			},
 			wantErr: false,
@@ -89,7 +86,7 @@ func TestCreateCharacter(t *testing.T) {
 			setupMocks: func(vs *MockVaultService, cr *MockCharacterRepository) {
 				// Even though we expect validation to fail, we should still set up the mock
 				// in case the validation changes in the future
-				vs.On("CreateVault").Return(vault.NewVaultID(uuid.New()), nil)
+				vs.On("CreateVault", mock.Anything).Return(vault.NewVaultID(uuid.New()), nil)
 			},
 			wantErr: true,
 		},
@@ -99,7 +96,7 @@ func TestCreateCharacter(t *testing.T) {
 			nickname: "TestChar",
 			class:    class.Warrior,
 			setupMocks: func(vs *MockVaultService, cr *MockCharacterRepository) {
-				vs.On("CreateVault").Return(vault.NewVaultID(uuid.Nil), assert.AnError)
+				vs.On("CreateVault", mock.Anything).Return(vault.NewVaultID(uuid.Nil), assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -111,7 +108,7 @@ func TestCreateCharacter(t *testing.T) {
 			mockRepo := new(MockCharacterRepository)
 			tt.setupMocks(mockVaultService, mockRepo)
 
-			service := NewCharacterService(mockRepo, mockVaultService, &MockLogger{})
+			service := NewCharacterService(mockRepo, mockVaultService, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
 			err := service.CreateCharacter(context.Background(), tt.loginID, tt.nickname, tt.class)
 
 			if tt.wantErr {
@@ -126,6 +123,49 @@ func TestCreateCharacter(t *testing.T) {
 	}
 }
 
+func TestCreateCharacterTagsContextWithCharacterID(t *testing.T) {
+	mockVaultService := new(MockVaultService)
+	mockRepo := new(MockCharacterRepository)
+
+	mockVaultService.On("CreateVault", mock.Anything).Return(vault.NewVaultID(uuid.New()), nil)
+	mockRepo.On("Save", mock.MatchedBy(func(ctx context.Context) bool {
+		fields := logger.FieldsFromContext(ctx)
+		return len(fields) == 4 && fields[0] == logger.FieldRequestID && fields[2] == logger.FieldCharacterID
+	}), mock.AnythingOfType("Character")).Return(nil)
+
+	ctx := logger.ContextWithFields(context.Background(), logger.FieldRequestID, "req-1")
+	service := NewCharacterService(mockRepo, mockVaultService, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+
+	assert.NoError(t, service.CreateCharacter(ctx, login.NewLoginID(uuid.New()), "TestChar", class.Warrior))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCharacterChecksLoginAndClassFirst(t *testing.T) {
+	tests := []struct {
+		name   string
+		logins stubLogin
+		class  class.Class
+		err    error
+	}{
+		{"unknown login", stubLogin{valid: false}, class.Warrior, service.ErrUnknownLogin},
+		{"unknown class", stubLogin{valid: true}, class.FromID("paladin"), class.ErrInvalidClass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// neither mock expects a call: no vault is created for a rejected character
+			mockVaultService := new(MockVaultService)
+			mockRepo := new(MockCharacterRepository)
+			svc := NewCharacterService(mockRepo, mockVaultService, tt.logins, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+
+			err := svc.CreateCharacter(context.Background(), login.NewLoginID(uuid.New()), "TestChar", tt.class)
+			assert.ErrorIs(t, err, service.ErrCannotCreateCharacter)
+			assert.ErrorIs(t, err, tt.err)
+			mockVaultService.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTransferItemTo(t *testing.T) {
 	characterID := character.NewCharacterID(uuid.New())
 	vaultID := vault.NewVaultID(uuid.New())
@@ -141,7 +181,7 @@ func TestTransferItemTo(t *testing.T) {
 			name: "successful item transfer",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				// Add item to character's inventory
 				_ = character.PickItem(*testItem)
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
@@ -160,7 +200,7 @@ func TestTransferItemTo(t *testing.T) {
 			name: "wrong vault",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 			},
 			wantErr: true,
@@ -169,7 +209,7 @@ func TestTransferItemTo(t *testing.T) {
 			name: "failed item drop",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				// Don't add item to inventory, so drop will fail
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 			},
@@ -179,7 +219,7 @@ func TestTransferItemTo(t *testing.T) {
 			name: "failed character update",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				_ = character.PickItem(*testItem)
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 				cr.On("Update", mock.Anything, mock.AnythingOfType("Character")).Return(assert.AnError)
@@ -193,7 +233,7 @@ func TestTransferItemTo(t *testing.T) {
 			mockRepo := new(MockCharacterRepository)
 			tt.setupMocks(mockRepo)
 
-			service := NewCharacterService(mockRepo, nil, &MockLogger{})
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
 			err := service.TransferItemTo(context.Background(), characterID, *testItem, 1, vaultID)
 
 			if tt.wantErr {
@@ -222,8 +262,8 @@ func TestTradeItem(t *testing.T) {
 			name: "successful trade",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
-				destinyChar, _ := character.CreateNewCharacter("DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				destinyChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				// Add item to origin character's inventory
 				_ = originChar.PickItem(*testItem)
 				cr.On("FindCharacterById", mock.Anything, originID).Return(originChar, nil)
@@ -243,7 +283,7 @@ func TestTradeItem(t *testing.T) {
 			name: "destiny character not found",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				cr.On("FindCharacterById", mock.Anything, originID).Return(originChar, nil)
 				cr.On("FindCharacterById", mock.Anything, destinyID).Return(nil, assert.AnError)
 			},
@@ -253,8 +293,8 @@ func TestTradeItem(t *testing.T) {
 			name: "failed item drop from origin",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
-				destinyChar, _ := character.CreateNewCharacter("DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				destinyChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				// Don't add item to origin character's inventory
 				cr.On("FindCharacterById", mock.Anything, originID).Return(originChar, nil)
 				cr.On("FindCharacterById", mock.Anything, destinyID).Return(destinyChar, nil)
@@ -265,8 +305,8 @@ func TestTradeItem(t *testing.T) {
 			name: "failed item pick with destiny",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
-				destinyChar, _ := character.CreateNewCharacter("DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				destinyChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				// Fill destiny character's inventory
 				for i := 0; i < 10; i++ {
 					item, _ := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), fmt.Sprintf("Item%d", i), 1)
@@ -282,8 +322,8 @@ func TestTradeItem(t *testing.T) {
 			name: "failed origin character update",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
-				destinyChar, _ := character.CreateNewCharacter("DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				destinyChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				_ = originChar.PickItem(*testItem)
 				cr.On("FindCharacterById", mock.Anything, originID).Return(originChar, nil)
 				cr.On("FindCharacterById", mock.Anything, destinyID).Return(destinyChar, nil)
@@ -295,8 +335,8 @@ func TestTradeItem(t *testing.T) {
 			name: "failed destiny character update",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				originChar, _ := character.CreateNewCharacter("OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
-				destinyChar, _ := character.CreateNewCharacter("DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				originChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "OriginChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				destinyChar, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "DestinyChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				_ = originChar.PickItem(*testItem)
 				cr.On("FindCharacterById", mock.Anything, originID).Return(originChar, nil)
 				cr.On("FindCharacterById", mock.Anything, destinyID).Return(destinyChar, nil)
@@ -312,7 +352,7 @@ func TestTradeItem(t *testing.T) {
 			mockRepo := new(MockCharacterRepository)
 			tt.setupMocks(mockRepo)
 
-			service := NewCharacterService(mockRepo, nil, &MockLogger{})
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
 			err := service.TradeItem(context.Background(), originID, *testItem, 1, destinyID)
 
 			if tt.wantErr {
@@ -341,7 +381,7 @@ func TestDepositGold(t *testing.T) {
 			quantity: 100,
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				// Add gold to character's inventory
 				err := character.PickGold(200) // Add more than we want to deposit
 				assert.NoError(t, err)
@@ -363,7 +403,7 @@ func TestDepositGold(t *testing.T) {
 			quantity: 100,
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 			},
 			wantErr: true,
@@ -373,7 +413,7 @@ func TestDepositGold(t *testing.T) {
 			quantity: 100,
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				// Don't add any gold to character's inventory
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 			},
@@ -384,7 +424,7 @@ func TestDepositGold(t *testing.T) {
 			quantity: 100,
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vaultID)
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vaultID)
 				err := character.PickGold(200)
 				assert.NoError(t, err)
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
@@ -399,7 +439,7 @@ func TestDepositGold(t *testing.T) {
 			mockRepo := new(MockCharacterRepository)
 			tt.setupMocks(mockRepo)
 
-			service := NewCharacterService(mockRepo, nil, &MockLogger{})
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
 			err := service.DepositGold(context.Background(), characterID, tt.quantity, vaultID)
 
 			if tt.wantErr {
@@ -425,7 +465,7 @@ func TestLeaveGuild(t *testing.T) {
 			name: "successful guild leave",
 			setupMocks: func(cr *MockCharacterRepository) {
 				loginID := login.NewLoginID(uuid.New())
-				character, _ := character.CreateNewCharacter("TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
 				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
 				cr.On("Update", mock.Anything, mock.AnythingOfType("Character")).Return(nil)
 			},
@@ -445,7 +485,7 @@ func TestLeaveGuild(t *testing.T) {
 			mockRepo := new(MockCharacterRepository)
 			tt.setupMocks(mockRepo)
 
-			service := NewCharacterService(mockRepo, nil, &MockLogger{})
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
 			err := service.LeaveGuild(context.Background(), characterID)
 
 			if tt.wantErr {
@@ -459,18 +499,198 @@ func TestLeaveGuild(t *testing.T) {
 	}
 }
 
-func TestNotImplementedMethods(t *testing.T) {
-	service := NewCharacterService(nil, nil, &MockLogger{})
+func TestPickItem(t *testing.T) {
+	characterID := character.NewCharacterID(uuid.New())
+	itemID := item.NewItemID(uuid.New())
+
+	tests := []struct {
+		name        string
+		description string
+		setupMocks  func(*MockCharacterRepository)
+		wantErr     bool
+	}{
+		{
+			name:        "successful item pick",
+			description: "Sword",
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+				cr.On("Update", mock.Anything, mock.AnythingOfType("Character")).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:        "character not found",
+			description: "Sword",
+			setupMocks: func(cr *MockCharacterRepository) {
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "invalid item",
+			description: "",
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "full inventory",
+			description: "Sword",
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				for i := 0; i < 10; i++ {
+					item, _ := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), fmt.Sprintf("Item%d", i), 1)
+					_ = character.PickItem(*item)
+				}
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCharacterRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+			err := service.PickItem(context.Background(), characterID, itemID, tt.description, 1)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDropItem(t *testing.T) {
+	characterID := character.NewCharacterID(uuid.New())
+	testItem, _ := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), "Test Item", 1)
+
+	tests := []struct {
+		name       string
+		setupMocks func(*MockCharacterRepository)
+		wantErr    bool
+	}{
+		{
+			name: "successful item drop",
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				_ = character.PickItem(*testItem)
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+				cr.On("Update", mock.Anything, mock.AnythingOfType("Character")).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "item not in inventory",
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCharacterRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+			err := service.DropItem(context.Background(), characterID, testItem.PlayerItemID)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPickGold(t *testing.T) {
+	characterID := character.NewCharacterID(uuid.New())
 
-	t.Run("PickItem", func(t *testing.T) {
-		err := service.PickItem()
-		assert.Error(t, err)
-		assert.Equal(t, "method not implemented - requires character ID and item parameters", err.Error())
-	})
+	tests := []struct {
+		name       string
+		amount     int
+		setupMocks func(*MockCharacterRepository)
+		wantErr    bool
+	}{
+		{
+			name:   "successful gold pick",
+			amount: 100,
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+				cr.On("Update", mock.Anything, mock.AnythingOfType("Character")).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "negative amount",
+			amount: -1,
+			setupMocks: func(cr *MockCharacterRepository) {
+				loginID := login.NewLoginID(uuid.New())
+				character, _ := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "TestChar", loginID, class.Warrior, vault.NewVaultID(uuid.New()))
+				cr.On("FindCharacterById", mock.Anything, characterID).Return(character, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCharacterRepository)
+			tt.setupMocks(mockRepo)
+
+			service := NewCharacterService(mockRepo, nil, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+			err := service.PickGold(context.Background(), characterID, tt.amount)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
 
-	t.Run("DropItem", func(t *testing.T) {
-		err := service.DropItem(context.Background(), playeritem.NewPlayerItemID(uuid.New()), 1)
-		assert.Error(t, err)
-		assert.Equal(t, "method not implemented - requires character ID parameter", err.Error())
-	})
+func TestGoldRoundTripWithMemoryRepository(t *testing.T) {
+	repo := memory.NewCharacterRepository(clock.System{}, dao.InventoryPersistence{})
+	svc := NewCharacterService(repo, new(MockVaultService), stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), logger.Nop{})
+	ctx := context.Background()
+
+	vaultId := vault.NewVaultID(uuid.New())
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), "Arthas", login.NewLoginID(uuid.New()), class.Warrior, vaultId)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, *c))
+
+	require.NoError(t, svc.PickGold(ctx, c.CharacterID, 100))
+	require.NoError(t, svc.DepositGold(ctx, c.CharacterID, 40, vaultId))
+	assert.ErrorIs(t, svc.DepositGold(ctx, c.CharacterID, 100, vaultId), inventory.ErrNotEnoughGold)
+
+	stored, err := repo.FindCharacterById(ctx, c.CharacterID)
+	require.NoError(t, err)
+	storedInventory := stored.Inventory()
+	assert.Equal(t, 60, storedInventory.GetCurrentGold())
+	assert.Equal(t, 2, stored.Version(), "only the successful operations were written")
 }
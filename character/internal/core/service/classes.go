@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// ClassCatalog serves the class catalog from memory and reloads it from the
+// repository, so a class a designer defines is picked up without a deploy.
+// It is empty until the first Refresh.
+type ClassCatalog struct {
+	classes repository.ClassRepository
+	logger  logger.Logger
+
+	mu          sync.RWMutex
+	definitions class.Definitions
+}
+
+func NewClassCatalog(classes repository.ClassRepository, logger logger.Logger) *ClassCatalog {
+	return &ClassCatalog{
+		classes:     classes,
+		logger:      logger,
+		definitions: class.NewDefinitions(),
+	}
+}
+
+func (c *ClassCatalog) Definition(id class.Class) (class.Definition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.definitions.Definition(id)
+}
+
+func (c *ClassCatalog) ListClasses(ctx context.Context) ([]class.Definition, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.definitions.List(), nil
+}
+
+// DefineClass saves definition, whose starting items must all fit in the
+// inventory of a new character.
+func (c *ClassCatalog) DefineClass(ctx context.Context, definition class.Definition) error {
+	if len(definition.StartingItems) > inventory.MAX_ITEMS {
+		return fmt.Errorf("%w: %d, at most %d", class.ErrTooManyStartingItems, len(definition.StartingItems), inventory.MAX_ITEMS)
+	}
+	if err := c.classes.SaveClass(ctx, definition); err != nil {
+		return fmt.Errorf("failed to save class: %w", err)
+	}
+	c.logger.WithContext(ctx).Info("Class defined", "class", definition.Class.String())
+	return c.Refresh(ctx)
+}
+
+// Refresh reloads the catalog. On failure the catalog loaded last is kept.
+func (c *ClassCatalog) Refresh(ctx context.Context) error {
+	definitions, err := c.classes.ListClasses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load classes: %w", err)
+	}
+
+	c.mu.Lock()
+	c.definitions = class.NewDefinitions(definitions...)
+	c.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the catalog every interval until ctx is cancelled.
+func (c *ClassCatalog) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Error("Failed to refresh the class catalog", "error", err)
+		}
+	}
+}
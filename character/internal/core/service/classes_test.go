@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+type failingClassRepository struct {
+	*memory.ClassRepository
+	err error
+}
+
+func (r *failingClassRepository) ListClasses(ctx context.Context) ([]class.Definition, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.ClassRepository.ListClasses(ctx)
+}
+
+func TestClassCatalogRefresh(t *testing.T) {
+	repo := &failingClassRepository{ClassRepository: memory.NewClassRepository(class.Defaults().List()...)}
+	catalog := NewClassCatalog(repo, logger.Nop{})
+	ctx := context.Background()
+
+	_, ok := catalog.Definition(class.Mage)
+	assert.False(t, ok, "empty until refreshed")
+
+	require.NoError(t, catalog.Refresh(ctx))
+	_, ok = catalog.Definition(class.Mage)
+	assert.True(t, ok)
+
+	paladin, err := class.NewDefinition("PALADIN", "Paladin", nil, class.Stats{"faith": 7})
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveClass(ctx, paladin))
+	_, ok = catalog.Definition(paladin.Class)
+	assert.False(t, ok, "picked up on the next refresh")
+
+	repo.err = errors.New("connection refused")
+	assert.Error(t, catalog.Refresh(ctx))
+	_, ok = catalog.Definition(class.Mage)
+	assert.True(t, ok, "a failed refresh keeps the catalog")
+
+	repo.err = nil
+	require.NoError(t, catalog.Refresh(ctx))
+	listed, err := catalog.ListClasses(ctx)
+	require.NoError(t, err)
+	assert.Len(t, listed, 4)
+}
+
+func TestClassCatalogDefineClass(t *testing.T) {
+	catalog := NewClassCatalog(memory.NewClassRepository(), logger.Nop{})
+	ctx := context.Background()
+
+	paladin, err := class.NewDefinition("PALADIN", "Paladin", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, catalog.DefineClass(ctx, paladin))
+
+	defined, ok := catalog.Definition(paladin.Class)
+	require.True(t, ok, "defining refreshes the catalog")
+	assert.Equal(t, "Paladin", defined.DisplayName)
+
+	_, err = class.ParseClass(catalog, "paladin")
+	assert.NoError(t, err)
+
+	startingItems := make([]class.StartingItem, inventory.MAX_ITEMS+1)
+	for i := range startingItems {
+		startingItems[i] = class.StartingItem{Item: item.NewItemID(uuid.New()), Description: "Potion", Quantity: 1}
+	}
+	hoarder, err := class.NewDefinition("HOARDER", "Hoarder", startingItems, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, catalog.DefineClass(ctx, hoarder), class.ErrTooManyStartingItems)
+	_, ok = catalog.Definition(hoarder.Class)
+	assert.False(t, ok, "a class whose items do not fit is not saved")
+}
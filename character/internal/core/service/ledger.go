@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// Reasons of the transactions posted from the character events.
+const (
+	reasonGoldPicked    = "gold picked"
+	reasonGoldDeposited = "gold deposited"
+	reasonGoldDropped   = "gold dropped"
+)
+
+// characterEventPrefix starts the ids of the transactions posted from the
+// character events; other services may not post under it.
+const characterEventPrefix = "character-event:"
+
+type LedgerService struct {
+	ledger repository.LedgerRepository
+}
+
+func NewLedgerService(ledger repository.LedgerRepository) service.Ledger {
+	return &LedgerService{
+		ledger: ledger,
+	}
+}
+
+func (s *LedgerService) Post(ctx context.Context, tx ledger.Transaction) error {
+	if strings.HasPrefix(tx.ID(), characterEventPrefix) {
+		return fmt.Errorf("%w: %s", service.ErrReservedTransactionID, tx.ID())
+	}
+	for _, entry := range tx.Entries() {
+		if entry.Account.Kind() == ledger.KindCharacter {
+			return fmt.Errorf("%w: %s", service.ErrCharacterAccountPosting, entry.Account)
+		}
+	}
+	return s.ledger.Post(ctx, tx)
+}
+
+func (s *LedgerService) Balance(ctx context.Context, account ledger.AccountID) (int, error) {
+	return s.ledger.Balance(ctx, account)
+}
+
+// LedgerPoster posts the gold events of the character event log into the
+// ledger. Each event becomes a transaction named after its log position, so
+// an event posted again after a crash is recognised and skipped.
+type LedgerPoster struct {
+	mu     sync.Mutex
+	ledger repository.LedgerRepository
+	logger logger.Logger
+	events *eventSubscription
+}
+
+// NewLedgerPoster reads the log like NewCharacterProjector does.
+func NewLedgerPoster(events repository.CharacterEventLog, ledger repository.LedgerRepository, clock clock.Clock, logger logger.Logger, batchSize int, gapTimeout time.Duration) *LedgerPoster {
+	return &LedgerPoster{
+		ledger: ledger,
+		logger: logger,
+		events: newEventSubscription(events, clock, logger, batchSize, gapTimeout),
+	}
+}
+
+// Run catches up every interval until ctx is done.
+func (p *LedgerPoster) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.CatchUp(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("Failed to post character events to the ledger", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CatchUp posts the events recorded after the checkpoint and returns how
+// many were read. Events that move no gold are read and skipped.
+func (p *LedgerPoster) CatchUp(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	checkpoint, err := p.ledger.Checkpoint(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ledger checkpoint: %w", err)
+	}
+
+	// the checkpoint is saved once per catch up rather than per event; the
+	// transaction ids make posting the events after it again harmless
+	last, applied, err := p.events.follow(ctx, checkpoint, p.post)
+	if last != checkpoint {
+		if saveErr := p.ledger.SaveCheckpoint(ctx, last); saveErr != nil {
+			return applied, errors.Join(err, fmt.Errorf("failed to save ledger checkpoint: %w", saveErr))
+		}
+	}
+	return applied, err
+}
+
+// Lag is the number of log positions recorded after the checkpoint.
+func (p *LedgerPoster) Lag(ctx context.Context) (int64, error) {
+	checkpoint, err := p.ledger.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return p.events.lag(ctx, checkpoint)
+}
+
+func (p *LedgerPoster) post(ctx context.Context, event repository.RecordedEvent) error {
+	tx, err := goldTransaction(event)
+	if err != nil {
+		p.logger.Error("Skipping character event", "position", event.Position, "type", event.Event.EventType(), "error", err)
+		return nil
+	}
+	if tx == nil {
+		return nil
+	}
+
+	err = p.ledger.Post(ctx, *tx)
+	if errors.Is(err, repository.ErrTransactionConflict) {
+		// a broken event must not stall every character behind it
+		p.logger.Error("Skipping character event", "position", event.Position, "type", event.Event.EventType(), "error", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post ledger transaction: %w", err)
+	}
+	return nil
+}
+
+// goldTransaction maps a gold event to its ledger transaction, nil for events
+// that move no gold. Gold picked up comes from the world; gold withdrawn goes
+// to the vault it was deposited into, or back to the world.
+func goldTransaction(event repository.RecordedEvent) (*ledger.Transaction, error) {
+	id := fmt.Sprintf("%s%d", characterEventPrefix, event.Position)
+
+	switch e := event.Event.(type) {
+	case character.GoldAdded:
+		return ledger.Transfer(id, reasonGoldPicked, event.OccurredAt, ledger.WorldSource, ledger.CharacterAccount(e.Character.ID()), e.Amount)
+	case character.GoldWithdrawn:
+		from := ledger.CharacterAccount(e.Character.ID())
+		if e.Vault.Equals(vault.VaultID{}) {
+			return ledger.Transfer(id, reasonGoldDropped, event.OccurredAt, from, ledger.WorldSink, e.Amount)
+		}
+		return ledger.Transfer(id, reasonGoldDeposited, event.OccurredAt, from, ledger.VaultAccount(e.Vault.ID()), e.Amount)
+	}
+	return nil, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type ledgerFixture struct {
+	repo       *memory.CharacterRepository
+	ledger     *memory.LedgerRepository
+	poster     *LedgerPoster
+	reconciler *LedgerReconciler
+}
+
+func newLedgerFixture(t *testing.T) ledgerFixture {
+	t.Helper()
+	clock := &manualClock{now: time.Now()}
+	repo := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	entries := memory.NewLedgerRepository()
+	poster := NewLedgerPoster(repo, entries, clock, logger.Nop{}, 2, gapTimeout)
+	return ledgerFixture{
+		repo:       repo,
+		ledger:     entries,
+		poster:     poster,
+		reconciler: NewLedgerReconciler(poster, entries, repo, repo, clock, logger.Nop{}),
+	}
+}
+
+func (f ledgerFixture) balance(t *testing.T, account ledger.AccountID) int {
+	t.Helper()
+	balance, err := f.ledger.Balance(context.Background(), account)
+	require.NoError(t, err)
+	return balance
+}
+
+func TestLedgerPosterPostsGoldEvents(t *testing.T) {
+	f := newLedgerFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(100))
+	require.NoError(t, c.PickItem(newProjectedItem(t, "Sword", 1)))
+	require.NoError(t, c.DepositGold(30, c.GetCurrentVaultId()))
+	require.NoError(t, c.DropGold(20))
+	require.NoError(t, f.repo.Save(ctx, *c))
+
+	read, err := f.poster.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, read)
+
+	assert.Equal(t, 50, f.balance(t, ledger.CharacterAccount(c.ID())))
+	assert.Equal(t, 30, f.balance(t, ledger.VaultAccount(c.GetCurrentVaultId().ID())))
+	assert.Equal(t, 20, f.balance(t, ledger.WorldSink))
+	assert.Equal(t, -100, f.balance(t, ledger.WorldSource))
+
+	lag, err := f.poster.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+}
+
+func TestLedgerPosterIsIdempotent(t *testing.T) {
+	f := newLedgerFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(40))
+	require.NoError(t, f.repo.Save(ctx, *c))
+	_, err := f.poster.CatchUp(ctx)
+	require.NoError(t, err)
+
+	// a poster that crashed before saving its checkpoint posts again
+	require.NoError(t, f.ledger.SaveCheckpoint(ctx, 0))
+	_, err = f.poster.CatchUp(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 40, f.balance(t, ledger.CharacterAccount(c.ID())))
+}
+
+func TestLedgerServiceRejectsCharacterAccounts(t *testing.T) {
+	entries := memory.NewLedgerRepository()
+	s := NewLedgerService(entries)
+	ctx := context.Background()
+	vault := ledger.VaultAccount(uuid.New())
+	guild := ledger.GuildAccount(uuid.New())
+
+	toCharacter, err := ledger.Transfer("tx-1", "refund", time.Now(), vault, ledger.CharacterAccount(uuid.New()), 10)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.Post(ctx, *toCharacter), service.ErrCharacterAccountPosting)
+
+	toGuild, err := ledger.Transfer("tx-2", "guild donation", time.Now(), vault, guild, 10)
+	require.NoError(t, err)
+	require.NoError(t, s.Post(ctx, *toGuild))
+
+	balance, err := s.Balance(ctx, guild)
+	require.NoError(t, err)
+	assert.Equal(t, 10, balance)
+}
+
+func TestLedgerServiceReservesCharacterEventIDs(t *testing.T) {
+	f := newLedgerFixture(t)
+	s := NewLedgerService(f.ledger)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(25))
+	require.NoError(t, f.repo.Save(ctx, *c))
+	events, err := f.repo.ReadEvents(ctx, 0, 10)
+	require.NoError(t, err)
+	var position int64
+	for _, event := range events {
+		if _, ok := event.Event.(character.GoldAdded); ok {
+			position = event.Position
+		}
+	}
+
+	// a client claiming the id of the event first would make the poster skip it
+	squatted, err := ledger.Transfer(fmt.Sprintf("character-event:%d", position), "squat", time.Now(), ledger.WorldSource, ledger.GuildAccount(uuid.New()), 25)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.Post(ctx, *squatted), service.ErrReservedTransactionID)
+
+	_, err = f.poster.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25, f.balance(t, ledger.CharacterAccount(c.ID())))
+}
+
+func TestLedgerReconciler(t *testing.T) {
+	f := newLedgerFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(60))
+	require.NoError(t, f.repo.Save(ctx, *c))
+
+	report, err := f.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accounts, "pending events are posted first")
+	assert.Empty(t, report.Mismatches)
+	assert.Zero(t, report.Imbalance)
+
+	// gold credited outside the character events drifts the ledger apart
+	drift, err := ledger.Transfer("drift", "bug", time.Now(), ledger.WorldSource, ledger.CharacterAccount(c.ID()), 5)
+	require.NoError(t, err)
+	require.NoError(t, f.ledger.Post(ctx, *drift))
+
+	report, err = f.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerMismatch{{Account: ledger.CharacterAccount(c.ID()), Ledger: 65, State: 60}}, report.Mismatches)
+	assert.Zero(t, report.Imbalance, "the drift is still balanced")
+	assert.Equal(t, *report, f.reconciler.LastReport())
+}
+
+func TestLedgerReconcilerFindsUnrecordedGold(t *testing.T) {
+	clock := &manualClock{now: time.Now()}
+	repo := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	entries := memory.NewLedgerRepository()
+	// the poster reads another log, so the gold of repo is never posted
+	poster := NewLedgerPoster(memory.NewCharacterRepository(clock, dao.InventoryPersistence{}), entries, clock, logger.Nop{}, 2, gapTimeout)
+	reconciler := NewLedgerReconciler(poster, entries, repo, repo, clock, logger.Nop{})
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(40))
+	require.NoError(t, repo.Save(ctx, *c))
+	broke := newProjectedCharacter(t)
+	require.NoError(t, repo.Save(ctx, *broke))
+
+	report, err := reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accounts, "characters without gold need no account")
+	assert.Equal(t, []LedgerMismatch{{Account: ledger.CharacterAccount(c.ID()), Ledger: 0, State: 40, Unrecorded: true}}, report.Mismatches)
+}
+
+func TestGoldTransaction(t *testing.T) {
+	c := newProjectedCharacter(t)
+	at := time.Now()
+
+	tx, err := goldTransaction(repository.RecordedEvent{Position: 7, OccurredAt: at, Event: character.GoldWithdrawn{Character: c.CharacterID, Amount: 5, Vault: c.GetCurrentVaultId()}})
+	require.NoError(t, err)
+	assert.Equal(t, "character-event:7", tx.ID())
+	assert.Equal(t, reasonGoldDeposited, tx.Reason())
+
+	tx, err = goldTransaction(repository.RecordedEvent{Position: 8, OccurredAt: at, Event: c.PendingEvents()[0]})
+	require.NoError(t, err)
+	assert.Nil(t, tx, "only gold events move gold")
+}
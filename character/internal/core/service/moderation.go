@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// suspensionSweepBatch bounds the expired suspensions lifted per query.
+const suspensionSweepBatch = 100
+
+type ModerationService struct {
+	characters repository.CharacterRepository
+	clock      clock.Clock
+	logger     logger.Logger
+}
+
+func NewModerationService(characters repository.CharacterRepository, clock clock.Clock, logger logger.Logger) service.Moderation {
+	return &ModerationService{
+		characters: characters,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+func (s *ModerationService) Suspend(ctx context.Context, characterId character.CharacterID, until time.Time, reason string) (character.Standing, error) {
+	now := s.clock.Now()
+	return s.change(ctx, characterId, "suspend", func(c *character.Character) error {
+		return c.Suspend(until, reason, now)
+	})
+}
+
+func (s *ModerationService) LiftSuspension(ctx context.Context, characterId character.CharacterID, reason string) (character.Standing, error) {
+	return s.change(ctx, characterId, "lift the suspension of", func(c *character.Character) error {
+		return c.LiftSuspension(reason)
+	})
+}
+
+func (s *ModerationService) Ban(ctx context.Context, characterId character.CharacterID, reason string) (character.Standing, error) {
+	return s.change(ctx, characterId, "ban", func(c *character.Character) error {
+		return c.Ban(reason)
+	})
+}
+
+func (s *ModerationService) change(ctx context.Context, characterId character.CharacterID, action string, apply func(*character.Character) error) (character.Standing, error) {
+	ctx = withCharacter(ctx, characterId)
+
+	loaded, err := s.characters.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return character.Standing{}, fmt.Errorf("failed to find character: %w", err)
+	}
+
+	if err := apply(loaded); err != nil {
+		return character.Standing{}, fmt.Errorf("failed to %s character: %w", action, err)
+	}
+
+	if err := s.characters.Update(ctx, *loaded); err != nil {
+		return character.Standing{}, fmt.Errorf("failed to update character: %w", err)
+	}
+
+	standing := loaded.Standing()
+	s.logger.WithContext(ctx).Info("Character standing changed", "status", standing.Status.String(), "reason", standing.Reason)
+	return standing, nil
+}
+
+// SuspensionSweeper lifts the suspensions that ran out. Until it runs, a
+// character whose suspension ended stays frozen.
+type SuspensionSweeper struct {
+	characters  repository.CharacterRepository
+	suspensions repository.CharacterSuspensions
+	clock       clock.Clock
+	logger      logger.Logger
+}
+
+func NewSuspensionSweeper(characters repository.CharacterRepository, suspensions repository.CharacterSuspensions, clock clock.Clock, logger logger.Logger) *SuspensionSweeper {
+	return &SuspensionSweeper{
+		characters:  characters,
+		suspensions: suspensions,
+		clock:       clock,
+		logger:      logger,
+	}
+}
+
+// Run sweeps every interval until ctx is done.
+func (s *SuspensionSweeper) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to lift expired suspensions", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep lifts every suspension that ended by now and returns how many it
+// lifted. A character changed by a moderator meanwhile is left alone: it is
+// either no longer suspended or suspended anew, and a conflicting update is
+// retried by the next sweep. A character failing to be lifted is logged and
+// skipped, so it does not hold back the others; the sweep then fails with the
+// first of those errors once every other suspension is lifted.
+func (s *SuspensionSweeper) Sweep(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	lifted := 0
+	// failed characters keep being listed first, so every query asks for
+	// them on top of the batch and skips them
+	failed := make(map[character.CharacterID]struct{})
+	var firstErr error
+
+	for {
+		limit := suspensionSweepBatch + len(failed)
+		expired, err := s.suspensions.ExpiredSuspensions(ctx, now, limit)
+		if err != nil {
+			return lifted, fmt.Errorf("failed to list expired suspensions: %w", err)
+		}
+
+		liftedInBatch, failedInBatch := 0, 0
+		for _, characterId := range expired {
+			if _, ok := failed[characterId]; ok {
+				continue
+			}
+			ok, err := s.lift(ctx, characterId, now)
+			if err != nil {
+				if ctx.Err() != nil {
+					return lifted, err
+				}
+				s.logger.WithContext(withCharacter(ctx, characterId)).Error("Failed to lift expired suspension", "error", err)
+				failed[characterId] = struct{}{}
+				failedInBatch++
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if ok {
+				liftedInBatch++
+			}
+		}
+		lifted += liftedInBatch
+
+		// a batch changing nothing would be listed again forever
+		if len(expired) < limit || (liftedInBatch == 0 && failedInBatch == 0) {
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return lifted, fmt.Errorf("failed to lift %d expired suspensions: %w", len(failed), firstErr)
+	}
+	return lifted, nil
+}
+
+func (s *SuspensionSweeper) lift(ctx context.Context, characterId character.CharacterID, now time.Time) (bool, error) {
+	ctx = withCharacter(ctx, characterId)
+	log := s.logger.WithContext(ctx)
+
+	loaded, err := s.characters.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return false, fmt.Errorf("failed to find character: %w", err)
+	}
+	if !loaded.Standing().SuspensionExpired(now) {
+		return false, nil
+	}
+
+	if err := loaded.LiftSuspension(character.ReasonSuspensionExpired); err != nil {
+		return false, fmt.Errorf("failed to lift suspension: %w", err)
+	}
+
+	err = s.characters.Update(ctx, *loaded)
+	if errors.Is(err, repository.ErrConcurrentUpdate) {
+		log.Warn("Character changed while lifting its suspension, retrying on the next sweep")
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update character: %w", err)
+	}
+
+	log.Info("Expired suspension lifted")
+	return true, nil
+}
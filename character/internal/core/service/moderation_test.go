@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+// failingUpdates fails to update one character.
+type failingUpdates struct {
+	*memory.CharacterRepository
+	failing character.CharacterID
+}
+
+func (f failingUpdates) Update(ctx context.Context, c character.Character) error {
+	if c.CharacterID == f.failing {
+		return errors.New("storage unavailable")
+	}
+	return f.CharacterRepository.Update(ctx, c)
+}
+
+type moderationFixture struct {
+	clock      *manualClock
+	characters *memory.CharacterRepository
+	moderation *ModerationService
+	sweeper    *SuspensionSweeper
+}
+
+func newModerationFixture(t *testing.T) moderationFixture {
+	t.Helper()
+	clock := &manualClock{now: time.Now().UTC()}
+	characters := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	return moderationFixture{
+		clock:      clock,
+		characters: characters,
+		moderation: NewModerationService(characters, clock, logger.Nop{}).(*ModerationService),
+		sweeper:    NewSuspensionSweeper(characters, characters, clock, logger.Nop{}),
+	}
+}
+
+func (f moderationFixture) newCharacter(t *testing.T) character.CharacterID {
+	t.Helper()
+	c := newProjectedCharacter(t)
+	require.NoError(t, f.characters.Save(context.Background(), *c))
+	return c.CharacterID
+}
+
+func (f moderationFixture) status(t *testing.T, id character.CharacterID) character.Status {
+	t.Helper()
+	c, err := f.characters.FindCharacterById(context.Background(), id)
+	require.NoError(t, err)
+	return c.Standing().Status
+}
+
+func TestModerationSuspendAndLift(t *testing.T) {
+	f := newModerationFixture(t)
+	ctx := context.Background()
+	id := f.newCharacter(t)
+	until := f.clock.Now().Add(time.Hour)
+
+	standing, err := f.moderation.Suspend(ctx, id, until, "gold duping")
+	require.NoError(t, err)
+	assert.Equal(t, character.Standing{Status: character.StatusSuspended, SuspendedUntil: until, Reason: "gold duping"}, standing)
+	assert.Equal(t, character.StatusSuspended, f.status(t, id))
+
+	_, err = f.moderation.Ban(ctx, id, "")
+	assert.ErrorIs(t, err, character.ErrMissingStatusReason)
+
+	standing, err = f.moderation.LiftSuspension(ctx, id, "appeal accepted")
+	require.NoError(t, err)
+	assert.Equal(t, character.Standing{}, standing)
+
+	_, err = f.moderation.LiftSuspension(ctx, id, "appeal accepted")
+	assert.ErrorIs(t, err, character.ErrInvalidStatusTransition)
+}
+
+func TestSuspensionSweeperLiftsExpiredSuspensions(t *testing.T) {
+	f := newModerationFixture(t)
+	ctx := context.Background()
+	short, long, banned := f.newCharacter(t), f.newCharacter(t), f.newCharacter(t)
+
+	_, err := f.moderation.Suspend(ctx, short, f.clock.Now().Add(time.Hour), "spam")
+	require.NoError(t, err)
+	_, err = f.moderation.Suspend(ctx, long, f.clock.Now().Add(48*time.Hour), "botting")
+	require.NoError(t, err)
+	_, err = f.moderation.Suspend(ctx, banned, f.clock.Now().Add(time.Hour), "botting")
+	require.NoError(t, err)
+	_, err = f.moderation.Ban(ctx, banned, "botting again")
+	require.NoError(t, err)
+
+	lifted, err := f.sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lifted, "no suspension ended yet")
+
+	f.clock.Advance(time.Hour)
+	lifted, err = f.sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, lifted)
+	assert.Equal(t, character.StatusActive, f.status(t, short))
+	assert.Equal(t, character.StatusSuspended, f.status(t, long))
+	assert.Equal(t, character.StatusBanned, f.status(t, banned), "a ban outlives the suspension it replaced")
+
+	events, err := f.characters.ReadEvents(ctx, 0, 100)
+	require.NoError(t, err)
+	last := events[len(events)-1].Event
+	assert.Equal(t, character.SuspensionLifted{Character: short, Reason: character.ReasonSuspensionExpired}, last)
+
+	lifted, err = f.sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lifted, "lifted suspensions are not lifted again")
+}
+
+func TestSuspensionSweeperSkipsFailingCharacters(t *testing.T) {
+	f := newModerationFixture(t)
+	ctx := context.Background()
+	first, second, third := f.newCharacter(t), f.newCharacter(t), f.newCharacter(t)
+	for i, id := range []character.CharacterID{first, second, third} {
+		_, err := f.moderation.Suspend(ctx, id, f.clock.Now().Add(time.Duration(i+1)*time.Minute), "spam")
+		require.NoError(t, err)
+	}
+	sweeper := NewSuspensionSweeper(failingUpdates{f.characters, first}, f.characters, f.clock, logger.Nop{})
+
+	f.clock.Advance(time.Hour)
+	lifted, err := sweeper.Sweep(ctx)
+	assert.Error(t, err, "the failure is reported")
+	assert.Equal(t, 2, lifted, "the suspension ended first does not hold back the others")
+	assert.Equal(t, character.StatusSuspended, f.status(t, first))
+	assert.Equal(t, character.StatusActive, f.status(t, second))
+	assert.Equal(t, character.StatusActive, f.status(t, third))
+}
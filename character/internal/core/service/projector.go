@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+var ErrUnexpectedEvent = errors.New("event does not apply to the character view")
+
+// CharacterProjector keeps the character read model up to date by applying
+// the events of the character event log in position order. The checkpoint is
+// stored with the views, so a restarted projector resumes where it stopped.
+type CharacterProjector struct {
+	mu     sync.Mutex
+	views  repository.CharacterViewRepository
+	logger logger.Logger
+	events *eventSubscription
+}
+
+// NewCharacterProjector reads the log in batches of batchSize. A gap in the
+// positions stops the projection for at most gapTimeout, giving a slower
+// concurrent transaction the chance to commit the missing event first.
+func NewCharacterProjector(events repository.CharacterEventLog, views repository.CharacterViewRepository, clock clock.Clock, logger logger.Logger, batchSize int, gapTimeout time.Duration) *CharacterProjector {
+	return &CharacterProjector{
+		views:  views,
+		logger: logger,
+		events: newEventSubscription(events, clock, logger, batchSize, gapTimeout),
+	}
+}
+
+// Run catches up every interval until ctx is done.
+func (p *CharacterProjector) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.CatchUp(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("Failed to project character events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CatchUp applies the events recorded after the checkpoint and returns how
+// many were applied.
+func (p *CharacterProjector) CatchUp(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.catchUp(ctx)
+}
+
+// Rebuild drops the read model and projects it again from the first event.
+func (p *CharacterProjector) Rebuild(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.views.Reset(ctx); err != nil {
+		return fmt.Errorf("failed to reset character views: %w", err)
+	}
+	p.events.reset()
+
+	applied, err := p.catchUp(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Info("Character views rebuilt", "events", applied)
+	return nil
+}
+
+// Lag is the number of log positions recorded after the checkpoint.
+func (p *CharacterProjector) Lag(ctx context.Context) (int64, error) {
+	checkpoint, err := p.views.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return p.events.lag(ctx, checkpoint)
+}
+
+func (p *CharacterProjector) catchUp(ctx context.Context) (int, error) {
+	checkpoint, err := p.views.Checkpoint(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read projection checkpoint: %w", err)
+	}
+
+	_, applied, err := p.events.follow(ctx, checkpoint, p.apply)
+	return applied, err
+}
+
+func (p *CharacterProjector) apply(ctx context.Context, event repository.RecordedEvent) error {
+	id := event.Event.AggregateID().ID()
+
+	view, err := p.views.FindCharacterView(ctx, id)
+	if errors.Is(err, repository.ErrCharacterNotFound) {
+		view = &repository.CharacterView{CharacterID: id}
+	} else if err != nil {
+		return fmt.Errorf("failed to load character view: %w", err)
+	}
+
+	if err := project(view, event.Event); err != nil {
+		// a broken event must not stall every character behind it
+		p.logger.Error("Skipping character event", "position", event.Position, "type", event.Event.EventType(), "characterId", id, "error", err)
+		return p.views.SaveCheckpoint(ctx, event.Position)
+	}
+
+	view.Position = event.Position
+	if err := p.views.SaveCharacterView(ctx, *view); err != nil {
+		return fmt.Errorf("failed to save character view: %w", err)
+	}
+	return nil
+}
+
+// project applies event to view. A view that was never projected only
+// accepts CharacterCreated.
+func project(view *repository.CharacterView, event character.Event) error {
+	if created, ok := event.(character.CharacterCreated); ok {
+		*view = repository.CharacterView{
+			CharacterID: created.Character.ID(),
+			LoginID:     created.Login.ID(),
+			Nickname:    created.Nickname,
+			Class:       created.Class.String(),
+			InventoryID: created.Inventory.ID(),
+			GuildID:     created.Guild.ID(),
+			VaultID:     created.Vault.ID(),
+			Level:       created.Level,
+			Position:    view.Position,
+		}
+		return nil
+	}
+
+	if view.Position == 0 {
+		return fmt.Errorf("%w: %s before %s", ErrUnexpectedEvent, event.EventType(), character.EventCharacterCreated)
+	}
+
+	switch e := event.(type) {
+	case character.ItemAdded:
+		added := repository.ItemView{
+			PlayerItemID: e.Item.ID(),
+			ItemID:       e.Item.ItemID().ID(),
+			Description:  e.Item.Describe(),
+			Quantity:     e.Item.GetCurrentQuantity(),
+		}
+		view.Items = slices.DeleteFunc(view.Items, func(i repository.ItemView) bool { return i.PlayerItemID == added.PlayerItemID })
+		view.Items = append(view.Items, added)
+		slices.SortFunc(view.Items, func(a, b repository.ItemView) int {
+			return strings.Compare(a.PlayerItemID.String(), b.PlayerItemID.String())
+		})
+	case character.ItemDropped:
+		dropped := e.Item.ID()
+		view.Items = slices.DeleteFunc(view.Items, func(i repository.ItemView) bool { return i.PlayerItemID == dropped })
+	case character.GoldAdded:
+		view.Gold += e.Amount
+	case character.GoldWithdrawn:
+		view.Gold -= e.Amount
+	case character.GuildChanged:
+		view.GuildID = e.Guild.ID()
+	case character.LevelReached:
+		view.Level = e.Level
+	case character.CharacterSuspended, character.SuspensionLifted, character.CharacterBanned, character.CharacterDeleted:
+		// the read model does not show the standing of the character
+	default:
+		return fmt.Errorf("%w: %s", ErrUnexpectedEvent, event.EventType())
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+const gapTimeout = 5 * time.Second
+
+// manualClock only moves when the test advances it
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+func (c *manualClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// stubEventLog serves a fixed list of events, which may contain gaps
+type stubEventLog struct {
+	events []repository.RecordedEvent
+}
+
+func (s *stubEventLog) ReadEvents(ctx context.Context, after int64, limit int) ([]repository.RecordedEvent, error) {
+	var page []repository.RecordedEvent
+	for _, event := range s.events {
+		if event.Position > after && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func (s *stubEventLog) LastPosition(ctx context.Context) (int64, error) {
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].Position, nil
+}
+
+type projectorFixture struct {
+	repo      *memory.CharacterRepository
+	views     *memory.CharacterViewRepository
+	projector *CharacterProjector
+}
+
+func newProjectorFixture(t *testing.T) projectorFixture {
+	t.Helper()
+	clock := &manualClock{now: time.Now()}
+	repo := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	views := memory.NewCharacterViewRepository()
+	return projectorFixture{
+		repo:      repo,
+		views:     views,
+		projector: NewCharacterProjector(repo, views, clock, logger.Nop{}, 2, gapTimeout),
+	}
+}
+
+func newProjectedCharacter(t *testing.T) *character.Character {
+	t.Helper()
+	// nicknames are unique, so every character gets its own
+	nickname := "Arthas" + uuid.NewString()[:8]
+	c, err := character.CreateNewCharacter(class.Defaults(), character.DefaultNicknameRules(), nickname, login.NewLoginID(uuid.New()), class.Warrior, vault.NewVaultID(uuid.New()))
+	require.NoError(t, err)
+	return c
+}
+
+func newProjectedItem(t *testing.T, description string, quantity int) playeritem.PlayerItem {
+	t.Helper()
+	i, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), description, quantity)
+	require.NoError(t, err)
+	return *i
+}
+
+func TestProjectorAppliesEveryEvent(t *testing.T) {
+	f := newProjectorFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	sword := newProjectedItem(t, "Sword", 1)
+	potion := newProjectedItem(t, "Potion", 3)
+	require.NoError(t, c.PickGold(100))
+	require.NoError(t, c.PickItem(sword))
+	require.NoError(t, c.PickItem(potion))
+	require.NoError(t, f.repo.Save(ctx, *c))
+
+	stored, err := f.repo.FindCharacterById(ctx, c.CharacterID)
+	require.NoError(t, err)
+	newGuild := guild.NewGuildID(uuid.New())
+	require.NoError(t, stored.DropGold(30))
+	require.NoError(t, stored.DropItem(sword))
+	require.NoError(t, stored.UpdateGuildInfo(newGuild))
+	require.NoError(t, stored.ReachLevel(4))
+	require.NoError(t, f.repo.Update(ctx, *stored))
+
+	applied, err := f.projector.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 8, applied, "batches are read until the log is drained")
+
+	view, err := f.views.FindCharacterView(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, c.LoginID().ID(), view.LoginID)
+	assert.Equal(t, c.Nickname(), view.Nickname)
+	assert.Equal(t, class.Warrior.String(), view.Class)
+	assert.Equal(t, newGuild.ID(), view.GuildID)
+	assert.Equal(t, 4, view.Level)
+	assert.Equal(t, 70, view.Gold)
+	assert.Equal(t, []repository.ItemView{{
+		PlayerItemID: potion.ID(),
+		ItemID:       potion.ItemID().ID(),
+		Description:  "Potion",
+		Quantity:     3,
+	}}, view.Items)
+
+	lag, err := f.projector.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+}
+
+func TestProjectorReportsLag(t *testing.T) {
+	f := newProjectorFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(10))
+	require.NoError(t, f.repo.Save(ctx, *c))
+
+	lag, err := f.projector.Lag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), lag)
+
+	_, err = f.projector.CatchUp(ctx)
+	require.NoError(t, err)
+
+	lag, err = f.projector.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+}
+
+func TestProjectorRebuild(t *testing.T) {
+	f := newProjectorFixture(t)
+	ctx := context.Background()
+
+	c := newProjectedCharacter(t)
+	require.NoError(t, c.PickGold(40))
+	require.NoError(t, f.repo.Save(ctx, *c))
+	_, err := f.projector.CatchUp(ctx)
+	require.NoError(t, err)
+
+	// a view drifting from the log is repaired by the rebuild
+	drifted, err := f.views.FindCharacterView(ctx, c.ID())
+	require.NoError(t, err)
+	drifted.Gold = 999
+	require.NoError(t, f.views.SaveCharacterView(ctx, *drifted))
+
+	require.NoError(t, f.projector.Rebuild(ctx))
+
+	view, err := f.views.FindCharacterView(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 40, view.Gold)
+
+	checkpoint, err := f.views.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), checkpoint)
+}
+
+func TestProjectorWaitsForMissingPositions(t *testing.T) {
+	c := newProjectedCharacter(t)
+	log := &stubEventLog{events: []repository.RecordedEvent{
+		{Position: 1, Event: c.PendingEvents()[0]},
+		{Position: 3, Event: character.GoldAdded{Character: c.CharacterID, Amount: 5}},
+	}}
+	clock := &manualClock{now: time.Now()}
+	views := memory.NewCharacterViewRepository()
+	projector := NewCharacterProjector(log, views, clock, logger.Nop{}, 10, gapTimeout)
+	ctx := context.Background()
+
+	applied, err := projector.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied, "position 2 may still be committing")
+
+	clock.Advance(gapTimeout - time.Second)
+	applied, err = projector.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+
+	clock.Advance(time.Second)
+	applied, err = projector.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied, "the gap is skipped once it expired")
+
+	view, err := views.FindCharacterView(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 5, view.Gold)
+}
+
+func TestProjectorSkipsEventsOfUnknownCharacters(t *testing.T) {
+	c := newProjectedCharacter(t)
+	log := &stubEventLog{events: []repository.RecordedEvent{
+		{Position: 1, Event: character.GoldAdded{Character: c.CharacterID, Amount: 5}},
+		{Position: 2, Event: c.PendingEvents()[0]},
+	}}
+	views := memory.NewCharacterViewRepository()
+	projector := NewCharacterProjector(log, views, &manualClock{now: time.Now()}, logger.Nop{}, 10, gapTimeout)
+	ctx := context.Background()
+
+	applied, err := projector.CatchUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+
+	view, err := views.FindCharacterView(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Zero(t, view.Gold, "gold added before the character existed is dropped")
+	assert.Equal(t, int64(2), view.Position)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// CharacterQueryService answers reads from the character read model and the
+// event store, never from the tables CharacterServiceImpl writes to.
+type CharacterQueryService struct {
+	views   repository.CharacterViewRepository
+	history repository.InventoryHistory
+}
+
+func NewCharacterQueryService(views repository.CharacterViewRepository, history repository.InventoryHistory) service.CharacterQueries {
+	return &CharacterQueryService{
+		views:   views,
+		history: history,
+	}
+}
+
+func (q *CharacterQueryService) GetCharacter(ctx context.Context, characterId character.CharacterID) (*repository.CharacterView, error) {
+	return q.views.FindCharacterView(withCharacter(ctx, characterId), characterId.ID())
+}
+
+func (q *CharacterQueryService) SearchCharacters(ctx context.Context, search repository.CharacterSearch) (*service.CharacterPage, error) {
+	if search.Limit < 1 || search.Limit > service.MaxSearchLimit {
+		return nil, fmt.Errorf("%w, got %d", service.ErrInvalidSearchLimit, search.Limit)
+	}
+
+	// one more view tells whether a next page exists
+	limit := search.Limit
+	search.Limit++
+
+	views, err := q.views.SearchCharacterViews(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &service.CharacterPage{Characters: views}
+	if len(views) > limit {
+		page.Characters = views[:limit]
+		last := page.Characters[limit-1]
+		page.Next = &repository.CharacterSearchKey{Nickname: last.Nickname, Gold: last.Gold, CharacterID: last.CharacterID}
+	}
+	return page, nil
+}
+
+func (q *CharacterQueryService) InventoryHistory(ctx context.Context, characterId character.CharacterID, filter repository.InventoryHistoryFilter) ([]repository.RecordedEvent, error) {
+	return q.history.InventoryHistory(withCharacter(ctx, characterId), characterId, filter)
+}
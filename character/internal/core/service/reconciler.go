@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ledger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// LedgerMismatch is a character whose ledger balance differs from the gold
+// in its inventory. Unrecorded is set when the character holds gold the
+// ledger has no account for.
+type LedgerMismatch struct {
+	Account    ledger.AccountID
+	Ledger     int
+	State      int
+	Unrecorded bool
+}
+
+type ReconciliationReport struct {
+	CheckedAt time.Time
+	// Accounts is the number of character accounts compared.
+	Accounts   int
+	Mismatches []LedgerMismatch
+	// Imbalance is the sum of every balance, which double entry keeps at 0.
+	Imbalance int
+}
+
+// LedgerReconciler compares the character balances of the ledger with the
+// gold of the character aggregates, both ways: every character account is
+// compared with its character, and every character holding gold with its
+// account. Vault and guild accounts have no state in this service and are
+// only covered by the imbalance check.
+type LedgerReconciler struct {
+	mu         sync.Mutex
+	poster     *LedgerPoster
+	ledger     repository.LedgerRepository
+	characters repository.CharacterRepository
+	gold       repository.CharacterGold
+	clock      clock.Clock
+	logger     logger.Logger
+	last       ReconciliationReport
+}
+
+func NewLedgerReconciler(poster *LedgerPoster, ledger repository.LedgerRepository, characters repository.CharacterRepository, gold repository.CharacterGold, clock clock.Clock, logger logger.Logger) *LedgerReconciler {
+	return &LedgerReconciler{
+		poster:     poster,
+		ledger:     ledger,
+		characters: characters,
+		gold:       gold,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// Run reconciles every interval until ctx is done.
+func (r *LedgerReconciler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("Failed to reconcile the gold ledger", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile posts the pending events and compares every character account
+// with its character, and every character holding gold that has no account
+// with an empty one. Gold moving while the comparison runs shows up as a
+// mismatch, so the mismatching accounts are compared once more after posting
+// again; only those still apart are reported.
+func (r *LedgerReconciler) Reconcile(ctx context.Context) (*ReconciliationReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.poster.CatchUp(ctx); err != nil {
+		return nil, err
+	}
+
+	balances, err := r.ledger.Balances(ctx, ledger.KindCharacter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list character balances: %w", err)
+	}
+	holding, err := r.gold.CharactersWithGold(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the characters holding gold: %w", err)
+	}
+	recorded := make(map[ledger.AccountID]bool, len(balances))
+	for _, balance := range balances {
+		recorded[balance.Account] = true
+	}
+	for _, characterId := range holding {
+		if account := ledger.CharacterAccount(characterId.ID()); !recorded[account] {
+			balances = append(balances, repository.AccountBalance{Account: account})
+		}
+	}
+
+	mismatches, err := r.compare(ctx, balances, recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mismatches) > 0 {
+		if _, err := r.poster.CatchUp(ctx); err != nil {
+			return nil, err
+		}
+		recheck := make([]repository.AccountBalance, 0, len(mismatches))
+		for _, mismatch := range mismatches {
+			balance, err := r.ledger.Balance(ctx, mismatch.Account)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ledger balance: %w", err)
+			}
+			recheck = append(recheck, repository.AccountBalance{Account: mismatch.Account, Balance: balance})
+		}
+		if mismatches, err = r.compare(ctx, recheck, recorded); err != nil {
+			return nil, err
+		}
+	}
+
+	imbalance, err := r.imbalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := ReconciliationReport{
+		CheckedAt:  r.clock.Now(),
+		Accounts:   len(balances),
+		Mismatches: mismatches,
+		Imbalance:  imbalance,
+	}
+	for _, mismatch := range mismatches {
+		if mismatch.Unrecorded {
+			r.logger.Warn("Character holds gold the ledger has no account for", "account", mismatch.Account.String(), "state", mismatch.State)
+			continue
+		}
+		r.logger.Warn("Gold ledger does not match the character", "account", mismatch.Account.String(), "ledger", mismatch.Ledger, "state", mismatch.State)
+	}
+	if imbalance != 0 {
+		r.logger.Error("Gold ledger is unbalanced", "imbalance", imbalance)
+	}
+
+	r.last = report
+	return &report, nil
+}
+
+// LastReport returns the report of the last successful reconciliation, the
+// zero report before the first one.
+func (r *LedgerReconciler) LastReport() ReconciliationReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.last
+}
+
+// compare loads the character of every balance. recorded holds the accounts
+// the ledger listed, the others are reported as unrecorded.
+func (r *LedgerReconciler) compare(ctx context.Context, balances []repository.AccountBalance, recorded map[ledger.AccountID]bool) ([]LedgerMismatch, error) {
+	var mismatches []LedgerMismatch
+	for _, balance := range balances {
+		owner, err := uuid.Parse(balance.Account.Owner())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read account %s: %w", balance.Account, err)
+		}
+
+		gold := 0
+		stored, err := r.characters.FindCharacterById(ctx, character.NewCharacterID(owner))
+		switch {
+		case errors.Is(err, repository.ErrCharacterNotFound):
+			// gold credited to a character that does not exist is a mismatch
+		case err != nil:
+			return nil, fmt.Errorf("failed to load character: %w", err)
+		default:
+			inventory := stored.Inventory()
+			gold = inventory.GetCurrentGold()
+		}
+
+		if gold != balance.Balance {
+			mismatches = append(mismatches, LedgerMismatch{
+				Account:    balance.Account,
+				Ledger:     balance.Balance,
+				State:      gold,
+				Unrecorded: !recorded[balance.Account] && balance.Balance == 0,
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+func (r *LedgerReconciler) imbalance(ctx context.Context) (int, error) {
+	sum := 0
+	for _, kind := range []ledger.AccountKind{ledger.KindCharacter, ledger.KindVault, ledger.KindGuild, ledger.KindWorld} {
+		balances, err := r.ledger.Balances(ctx, kind)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list %s balances: %w", kind, err)
+		}
+		for _, balance := range balances {
+			sum += balance.Balance
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+// eventSubscription reads the character event log in position order for a
+// consumer keeping its own checkpoint. A gap in the positions stops the
+// reading for at most gapTimeout, giving a slower concurrent transaction the
+// chance to commit the missing event first. It is not safe for concurrent
+// use; its owner serialises the calls.
+type eventSubscription struct {
+	events     repository.CharacterEventLog
+	clock      clock.Clock
+	logger     logger.Logger
+	batchSize  int
+	gapTimeout time.Duration
+	gap        *positionGap
+}
+
+// positionGap is a missing position the subscription is waiting for. A
+// transaction that took a position but has not committed yet leaves such a
+// gap; one that rolled back leaves it forever.
+type positionGap struct {
+	after int64
+	since time.Time
+}
+
+func newEventSubscription(events repository.CharacterEventLog, clock clock.Clock, logger logger.Logger, batchSize int, gapTimeout time.Duration) *eventSubscription {
+	return &eventSubscription{
+		events:     events,
+		clock:      clock,
+		logger:     logger,
+		batchSize:  max(batchSize, 1),
+		gapTimeout: gapTimeout,
+	}
+}
+
+// follow passes the events recorded after checkpoint to apply until the log
+// is drained or a gap has to be waited for. It returns the position of the
+// last applied event, checkpoint when none was, and how many were applied.
+func (s *eventSubscription) follow(ctx context.Context, checkpoint int64, apply func(context.Context, repository.RecordedEvent) error) (int64, int, error) {
+	applied := 0
+	for {
+		batch, err := s.events.ReadEvents(ctx, checkpoint, s.batchSize)
+		if err != nil {
+			return checkpoint, applied, fmt.Errorf("failed to read character events: %w", err)
+		}
+		if len(batch) == 0 {
+			return checkpoint, applied, nil
+		}
+
+		for _, event := range batch {
+			if event.Position != checkpoint+1 && !s.gapExpired(checkpoint) {
+				return checkpoint, applied, nil
+			}
+			s.gap = nil
+
+			if err := apply(ctx, event); err != nil {
+				return checkpoint, applied, err
+			}
+			checkpoint = event.Position
+			applied++
+		}
+	}
+}
+
+// lag is the number of log positions recorded after checkpoint.
+func (s *eventSubscription) lag(ctx context.Context, checkpoint int64) (int64, error) {
+	last, err := s.events.LastPosition(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return max(last-checkpoint, 0), nil
+}
+
+// reset forgets the gap being waited for, as when reading from the start.
+func (s *eventSubscription) reset() {
+	s.gap = nil
+}
+
+// gapExpired reports whether the subscription waited long enough for the
+// position following after.
+func (s *eventSubscription) gapExpired(after int64) bool {
+	now := s.clock.Now()
+	if s.gap == nil || s.gap.after != after {
+		s.gap = &positionGap{after: after, since: now}
+	}
+	if now.Sub(s.gap.since) < s.gapTimeout {
+		return false
+	}
+
+	s.logger.Warn("Skipping missing event positions", "after", after)
+	return true
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+var ErrCannotImport = errors.New("cannot import character")
+
+type CharacterTransferService struct {
+	characters repository.CharacterRepository
+	login      gateway.Login
+	classes    class.Catalog
+	nicknames  character.NicknameRules
+	clock      clock.Clock
+	logger     logger.Logger
+}
+
+func NewCharacterTransferService(characters repository.CharacterRepository, loginGateway gateway.Login, classes class.Catalog, nicknames character.NicknameRules, clock clock.Clock, logger logger.Logger) service.CharacterTransfer {
+	return &CharacterTransferService{
+		characters: characters,
+		login:      loginGateway,
+		classes:    classes,
+		nicknames:  nicknames,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+func (s *CharacterTransferService) Export(ctx context.Context, characterId character.CharacterID) (*character.Character, error) {
+	ctx = withCharacter(ctx, characterId)
+
+	exported, err := s.characters.FindCharacterById(ctx, characterId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find character: %w", err)
+	}
+
+	s.logger.WithContext(ctx).Info("Character exported")
+	return exported, nil
+}
+
+// Import replays the imported state through the same domain operations a
+// player goes through, so it is validated like any other change: the login
+// is checked like a new character's, the character is created, picks its items and gold, joins its guild and reaches
+// its level, and only then takes its standing, since an inactive character cannot change.
+// A suspension that already ran out is not carried over, and the class does
+// not hand out its starting items again.
+func (s *CharacterTransferService) Import(ctx context.Context, imported service.CharacterImport) (service.ImportedCharacter, error) {
+	ok, err := s.login.IsLoginValid(ctx, imported.Login)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to check login", "error", err)
+		return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+	}
+	if !ok {
+		return service.ImportedCharacter{}, fmt.Errorf("%w: %w: %s", ErrCannotImport, service.ErrUnknownLogin, imported.Login.ID())
+	}
+
+	created, err := character.CreateNewCharacter(importedClasses{s.classes}, s.nicknames, imported.Nickname, imported.Login, imported.Class, imported.Vault)
+	if err != nil {
+		return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+	}
+
+	ctx = withCharacter(ctx, created.CharacterID)
+	log := s.logger.WithContext(ctx)
+
+	items := make(map[playeritem.PlayerItemID]playeritem.PlayerItemID, len(imported.Items))
+	for _, item := range imported.Items {
+		picked, err := playeritem.NewPlayerItem(item.Item, item.Description, item.Quantity)
+		if err != nil {
+			return service.ImportedCharacter{}, fmt.Errorf("%w: item %s: %w", ErrCannotImport, item.Source.ID(), err)
+		}
+		if err := created.PickItem(*picked); err != nil {
+			return service.ImportedCharacter{}, fmt.Errorf("%w: item %s: %w", ErrCannotImport, item.Source.ID(), err)
+		}
+		items[item.Source] = picked.PlayerItemID
+	}
+
+	if imported.Gold != 0 {
+		if err := created.PickGold(imported.Gold); err != nil {
+			return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+		}
+	}
+
+	if imported.Guild.ID() != uuid.Nil {
+		if err := created.UpdateGuildInfo(imported.Guild); err != nil {
+			return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+		}
+	}
+
+	if imported.Level != 0 && imported.Level != created.Level() {
+		if err := created.ReachLevel(imported.Level); err != nil {
+			return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+		}
+	}
+
+	if err := s.restoreStanding(created, imported.Standing); err != nil {
+		return service.ImportedCharacter{}, fmt.Errorf("%w: %w", ErrCannotImport, err)
+	}
+
+	if err := s.characters.Save(ctx, *created); err != nil {
+		log.Error("Failed to save imported character", "error", err)
+		return service.ImportedCharacter{}, err
+	}
+
+	log.Info("Character imported", "nickname", created.Nickname(), "items", len(items), "status", created.Standing().Status.String())
+	return service.ImportedCharacter{
+		Character: created.CharacterID,
+		Inventory: created.Inventory().InventoryID,
+		Items:     items,
+	}, nil
+}
+
+func (s *CharacterTransferService) restoreStanding(c *character.Character, standing character.Standing) error {
+	switch standing.Status {
+	case character.StatusSuspended:
+		now := s.clock.Now()
+		if standing.SuspensionExpired(now) {
+			return nil
+		}
+		return c.Suspend(standing.SuspendedUntil, standing.Reason, now)
+	case character.StatusBanned:
+		return c.Ban(standing.Reason)
+	case character.StatusDeleted:
+		return c.Delete(standing.Reason)
+	default:
+		return nil
+	}
+}
+
+// importedClasses checks the class of an imported character without its
+// starting items: the exported inventory already holds what the character
+// kept of them.
+type importedClasses struct {
+	class.Catalog
+}
+
+func (c importedClasses) Definition(id class.Class) (class.Definition, bool) {
+	definition, ok := c.Catalog.Definition(id)
+	definition.StartingItems = nil
+	return definition, ok
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/guild"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/inventory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+func newTransferService(t *testing.T) (*CharacterTransferService, *memory.CharacterRepository, *manualClock) {
+	t.Helper()
+	clock := &manualClock{now: time.Now().UTC()}
+	characters := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	return NewCharacterTransferService(characters, stubLogin{valid: true}, class.Defaults(), character.DefaultNicknameRules(), clock, logger.Nop{}).(*CharacterTransferService), characters, clock
+}
+
+func newCharacterImport(items int) service.CharacterImport {
+	imported := service.CharacterImport{
+		Login:    login.NewLoginID(uuid.New()),
+		Nickname: "Jaina",
+		Class:    class.Mage,
+		Gold:     250,
+		Guild:    guild.NewGuildID(uuid.New()),
+		Vault:    vault.NewVaultID(uuid.New()),
+	}
+	for range items {
+		imported.Items = append(imported.Items, service.ItemImport{
+			Source:      playeritem.NewPlayerItemID(uuid.New()),
+			Item:        item.NewItemID(uuid.New()),
+			Description: "Staff",
+			Quantity:    1,
+		})
+	}
+	return imported
+}
+
+func TestCharacterTransferImport(t *testing.T) {
+	transfers, characters, _ := newTransferService(t)
+	ctx := context.Background()
+	imported := newCharacterImport(2)
+	imported.Level = 12
+	imported.Standing = character.Standing{Status: character.StatusBanned, Reason: "gold duping"}
+
+	created, err := transfers.Import(ctx, imported)
+	require.NoError(t, err)
+
+	loaded, err := characters.FindCharacterById(ctx, created.Character)
+	require.NoError(t, err)
+	assert.Equal(t, "Jaina", loaded.Nickname())
+	assert.Equal(t, class.Mage, loaded.Class())
+	assert.Equal(t, 12, loaded.Level())
+	assert.Equal(t, imported.Login.ID(), loaded.LoginID().ID())
+	assert.Equal(t, imported.Guild.ID(), loaded.GetCurrentGuild().ID())
+	assert.Equal(t, imported.Vault.ID(), loaded.GetCurrentVaultId().ID())
+	assert.Equal(t, imported.Standing, loaded.Standing())
+	held := loaded.Inventory()
+	assert.Equal(t, 250, held.GetCurrentGold())
+	assert.Equal(t, created.Inventory.ID(), held.ID())
+
+	require.Len(t, created.Items, 2)
+	for _, source := range imported.Items {
+		target, ok := created.Items[source.Source]
+		require.True(t, ok)
+		assert.NotEqual(t, source.Source.ID(), target.ID(), "player items get new ids")
+		picked, ok := loaded.FindItem(target)
+		require.True(t, ok)
+		assert.Equal(t, source.Item.ID(), picked.ItemID().ID())
+	}
+
+	exported, err := transfers.Export(ctx, created.Character)
+	require.NoError(t, err)
+	assert.Equal(t, created.Character, exported.CharacterID)
+}
+
+func TestCharacterTransferImportStanding(t *testing.T) {
+	tests := []struct {
+		name     string
+		standing func(now time.Time) character.Standing
+		want     character.Status
+	}{
+		{"active", func(time.Time) character.Standing { return character.Standing{} }, character.StatusActive},
+		{"running suspension", func(now time.Time) character.Standing {
+			return character.Standing{Status: character.StatusSuspended, SuspendedUntil: now.Add(time.Hour), Reason: "spam"}
+		}, character.StatusSuspended},
+		{"expired suspension", func(now time.Time) character.Standing {
+			return character.Standing{Status: character.StatusSuspended, SuspendedUntil: now.Add(-time.Hour), Reason: "spam"}
+		}, character.StatusActive},
+		{"deleted", func(time.Time) character.Standing {
+			return character.Standing{Status: character.StatusDeleted, Reason: "account closed"}
+		}, character.StatusDeleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, characters, clock := newTransferService(t)
+			imported := newCharacterImport(1)
+			imported.Standing = tt.standing(clock.Now())
+
+			created, err := transfers.Import(context.Background(), imported)
+			require.NoError(t, err)
+
+			loaded, err := characters.FindCharacterById(context.Background(), created.Character)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, loaded.Standing().Status)
+		})
+	}
+}
+
+func TestCharacterTransferImportChecksLogin(t *testing.T) {
+	clock := &manualClock{now: time.Now().UTC()}
+	characters := memory.NewCharacterRepository(clock, dao.InventoryPersistence{})
+	transfers := NewCharacterTransferService(characters, stubLogin{valid: false}, class.Defaults(), character.DefaultNicknameRules(), clock, logger.Nop{})
+
+	_, err := transfers.Import(context.Background(), newCharacterImport(1))
+	assert.ErrorIs(t, err, ErrCannotImport)
+	assert.ErrorIs(t, err, service.ErrUnknownLogin)
+
+	events, err := characters.ReadEvents(context.Background(), 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events, "a character of an unknown login is not imported")
+}
+
+func TestCharacterTransferImportValidates(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*service.CharacterImport)
+		err    error
+	}{
+		{"invalid nickname", func(i *service.CharacterImport) { i.Nickname = "x" }, character.ErrInvalidNicknameSize},
+		{"too many items", func(i *service.CharacterImport) { *i = newCharacterImport(inventory.MAX_ITEMS + 1) }, inventory.ErrInventoryIsFull},
+		{"item without description", func(i *service.CharacterImport) { i.Items[0].Description = "" }, playeritem.ErrNilDescription},
+		{"negative gold", func(i *service.CharacterImport) { i.Gold = -1 }, inventory.ErrInvalidGoldAmount},
+		{"negative level", func(i *service.CharacterImport) { i.Level = -1 }, character.ErrInvalidLevel},
+		{"ban without reason", func(i *service.CharacterImport) { i.Standing = character.Standing{Status: character.StatusBanned} }, character.ErrMissingStatusReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, characters, _ := newTransferService(t)
+			imported := newCharacterImport(1)
+			tt.change(&imported)
+
+			_, err := transfers.Import(context.Background(), imported)
+			assert.ErrorIs(t, err, ErrCannotImport)
+			assert.ErrorIs(t, err, tt.err)
+
+			events, err := characters.ReadEvents(context.Background(), 0, 10)
+			require.NoError(t, err)
+			assert.Empty(t, events, "a rejected import saves nothing")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type WalletService struct {
+	wallets      repository.WalletRepository
+	loginGateway gateway.Login
+	logger       logger.Logger
+}
+
+func NewWalletService(wallets repository.WalletRepository, loginGateway gateway.Login, logger logger.Logger) service.Wallets {
+	return &WalletService{
+		wallets:      wallets,
+		loginGateway: loginGateway,
+		logger:       logger,
+	}
+}
+
+func (s *WalletService) GetWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error) {
+	w, err := s.wallets.FindWallet(ctx, loginId)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		return wallet.NewWallet(loginId)
+	}
+	return w, err
+}
+
+func (s *WalletService) CreditPurchase(ctx context.Context, loginId login.LoginID, purchaseId string, amount int) (*wallet.Wallet, error) {
+	w, err := s.wallets.FindWallet(ctx, loginId)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		if w, err = s.openWallet(ctx, loginId); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	movement := wallet.Movement{Reference: purchaseId, Amount: amount, Source: string(wallet.SourcePurchase)}
+	return s.apply(ctx, w, movement, func() error {
+		return w.Credit(purchaseId, amount, wallet.SourcePurchase)
+	})
+}
+
+func (s *WalletService) Debit(ctx context.Context, loginId login.LoginID, reference string, amount int, reason string) (*wallet.Wallet, error) {
+	w, err := s.GetWallet(ctx, loginId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	movement := wallet.Movement{Reference: reference, Amount: -amount, Source: reason}
+	return s.apply(ctx, w, movement, func() error {
+		return w.Debit(reference, amount, reason)
+	})
+}
+
+// openWallet creates the wallet of a login receiving cash for the first
+// time, once the login gateway confirmed the login exists.
+func (s *WalletService) openWallet(ctx context.Context, loginId login.LoginID) (*wallet.Wallet, error) {
+	ok, err := s.loginGateway.IsLoginValid(ctx, loginId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check login: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", service.ErrUnknownLogin, loginId.ID())
+	}
+	return wallet.NewWallet(loginId)
+}
+
+// apply makes movement on w through move and stores it, unless a movement
+// with the same reference is stored already: the same movement is a retry
+// and returns w untouched, another one is a conflict.
+func (s *WalletService) apply(ctx context.Context, w *wallet.Wallet, movement wallet.Movement, move func() error) (*wallet.Wallet, error) {
+	stored, err := s.wallets.FindMovement(ctx, w.LoginID(), movement.Reference)
+	switch {
+	case err == nil && *stored == movement:
+		return w, nil
+	case err == nil:
+		return nil, fmt.Errorf("%w: %s", wallet.ErrDuplicateReference, movement.Reference)
+	case !errors.Is(err, repository.ErrMovementNotFound):
+		return nil, fmt.Errorf("failed to find cash movement: %w", err)
+	}
+
+	if err := move(); err != nil {
+		return nil, err
+	}
+	if err := s.wallets.SaveWallet(ctx, *w); err != nil {
+		return nil, fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	s.logger.WithContext(ctx).Info("Cash moved", "loginId", w.LoginID().ID(), "reference", movement.Reference, "amount", movement.Amount, "source", movement.Source)
+	return w, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/wallet"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+)
+
+type stubLogin struct {
+	valid bool
+}
+
+func (s stubLogin) IsLoginValid(ctx context.Context, loginId login.LoginID) (bool, error) {
+	return s.valid, nil
+}
+
+func TestWalletServiceCreditPurchase(t *testing.T) {
+	ctx := context.Background()
+	wallets := NewWalletService(memory.NewWalletRepository(), stubLogin{valid: true}, logger.Nop{})
+	loginId := login.NewLoginID(uuid.New())
+
+	w, err := wallets.CreditPurchase(ctx, loginId, "purchase-1", 100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, w.Balance())
+
+	w, err = wallets.CreditPurchase(ctx, loginId, "purchase-1", 100)
+	require.NoError(t, err, "a retried purchase is not credited twice")
+	assert.Equal(t, 100, w.Balance())
+
+	_, err = wallets.CreditPurchase(ctx, loginId, "purchase-1", 500)
+	assert.ErrorIs(t, err, wallet.ErrDuplicateReference)
+
+	w, err = wallets.GetWallet(ctx, loginId)
+	require.NoError(t, err)
+	assert.Equal(t, 100, w.Balance())
+}
+
+func TestWalletServiceCreditPurchaseUnknownLogin(t *testing.T) {
+	wallets := NewWalletService(memory.NewWalletRepository(), stubLogin{}, logger.Nop{})
+
+	_, err := wallets.CreditPurchase(context.Background(), login.NewLoginID(uuid.New()), "purchase-1", 100)
+	assert.ErrorIs(t, err, service.ErrUnknownLogin)
+}
+
+func TestWalletServiceDebit(t *testing.T) {
+	ctx := context.Background()
+	wallets := NewWalletService(memory.NewWalletRepository(), stubLogin{valid: true}, logger.Nop{})
+	loginId := login.NewLoginID(uuid.New())
+
+	_, err := wallets.Debit(ctx, loginId, "skin-1", 10, "skin")
+	assert.ErrorIs(t, err, wallet.ErrNotEnoughCash, "an empty wallet holds nothing to spend")
+
+	_, err = wallets.CreditPurchase(ctx, loginId, "purchase-1", 100)
+	require.NoError(t, err)
+
+	w, err := wallets.Debit(ctx, loginId, "skin-1", 30, "skin")
+	require.NoError(t, err)
+	assert.Equal(t, 70, w.Balance())
+
+	w, err = wallets.Debit(ctx, loginId, "skin-1", 30, "skin")
+	require.NoError(t, err, "a retried debit is not taken twice")
+	assert.Equal(t, 70, w.Balance())
+
+	_, err = wallets.Debit(ctx, loginId, "skin-2", 71, "skin")
+	assert.ErrorIs(t, err, wallet.ErrNotEnoughCash)
+
+	_, err = wallets.Debit(ctx, loginId, "purchase-1", 10, "skin")
+	assert.ErrorIs(t, err, wallet.ErrDuplicateReference, "references are shared by credits and debits")
+}
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/input/consumer"
+	grpcadapter "github.com/vterry/ddd-study/character/internal/adapters/input/grpc"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/grpc/pb"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/middleware"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/openapi"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	coreservice "github.com/vterry/ddd-study/character/internal/core/service"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+	"github.com/vterry/ddd-study/character/internal/infra/health"
+	"github.com/vterry/ddd-study/character/internal/infra/logger"
+	"github.com/vterry/ddd-study/character/internal/infra/metrics"
+	"github.com/vterry/ddd-study/character/internal/infra/tracing"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+)
+
+var ErrMissingDependency = errors.New("missing dependency")
+
+// App is the composition root of the character service. It wires the core
+// service and the REST and gRPC adapters from the ports passed as options,
+// so production and tests only differ in the adapters they hand in.
+type App struct {
+	handler    http.Handler
+	service    service.CharacterService
+	queries    service.CharacterQueries
+	projector  *coreservice.CharacterProjector
+	ledger     service.Ledger
+	poster     *coreservice.LedgerPoster
+	reconciler *coreservice.LedgerReconciler
+	wallets    service.Wallets
+	moderation service.Moderation
+	transfers  service.CharacterTransfer
+	classes    *coreservice.ClassCatalog
+	sweeper    *coreservice.SuspensionSweeper
+	consumer   *consumer.InventoryConsumer
+	// gameServers are the token subjects allowed to call the gRPC service
+	gameServers []string
+	deps        dependencies
+}
+
+// New wires the application. The repositories, event log, gateways and token
+// validator are required, and so is the broker when the consumer is enabled;
+// logger, clock, metrics, tracing and readiness default to the production
+// ones. The class catalog is loaded before New returns.
+func New(cfg config.Config, opts ...Option) (*App, error) {
+	deps := dependencies{
+		clock:      clock.System{},
+		tracer:     otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&deps)
+	}
+
+	if err := deps.validate(cfg); err != nil {
+		return nil, err
+	}
+	if deps.logger == nil {
+		deps.logger = logger.NewZapLogger()
+	}
+	if deps.metrics == nil {
+		deps.metrics = metrics.New()
+	}
+	if deps.readiness == nil {
+		deps.readiness = health.NewChecker(cfg.HealthCheckTimeout)
+	}
+
+	characters := metrics.InstrumentCharacterRepository(
+		tracing.TraceCharacterRepository(deps.characters, deps.storageSystem, deps.tracer),
+		deps.storageSystem, deps.metrics,
+	)
+	vault := metrics.InstrumentVaultGateway(tracing.TraceVaultGateway(deps.vault, deps.tracer), deps.metrics)
+	login := metrics.InstrumentLoginGateway(tracing.TraceLoginGateway(deps.login, deps.tracer), deps.metrics)
+
+	// characters cannot be created before the catalog is loaded
+	classes := coreservice.NewClassCatalog(deps.classes, deps.logger)
+	if err := classes.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	nicknames, err := character.NewNicknameRules(cfg.Nickname.Scripts...)
+	if err != nil {
+		return nil, err
+	}
+
+	characterService := metrics.InstrumentCharacterService(
+		tracing.TraceCharacterService(coreservice.NewCharacterService(characters, vault, login, classes, nicknames, deps.logger), deps.tracer),
+		deps.metrics,
+	)
+
+	projector := coreservice.NewCharacterProjector(deps.events, deps.views, deps.clock, deps.logger, cfg.Projection.BatchSize, cfg.Projection.GapTimeout)
+	deps.metrics.ObserveProjectionLag(projector.Lag)
+
+	poster := coreservice.NewLedgerPoster(deps.events, deps.ledger, deps.clock, deps.logger, cfg.Projection.BatchSize, cfg.Projection.GapTimeout)
+	reconciler := coreservice.NewLedgerReconciler(poster, deps.ledger, deps.characters, deps.gold, deps.clock, deps.logger)
+	deps.metrics.ObserveLedgerMismatches(func() int { return len(reconciler.LastReport().Mismatches) })
+
+	app := &App{
+		service:     characterService,
+		queries:     coreservice.NewCharacterQueryService(deps.views, deps.history),
+		projector:   projector,
+		ledger:      coreservice.NewLedgerService(deps.ledger),
+		poster:      poster,
+		reconciler:  reconciler,
+		wallets:     coreservice.NewWalletService(deps.wallets, login, deps.logger),
+		moderation:  coreservice.NewModerationService(characters, deps.clock, deps.logger),
+		transfers:   coreservice.NewCharacterTransferService(characters, login, classes, nicknames, deps.clock, deps.logger),
+		classes:     classes,
+		sweeper:     coreservice.NewSuspensionSweeper(characters, deps.suspensions, deps.clock, deps.logger),
+		gameServers: cfg.GameServerSubjects,
+		deps:        deps,
+	}
+
+	handler, err := app.newHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.handler = handler
+
+	if cfg.Consumer.Enabled {
+		app.consumer = consumer.NewInventoryConsumer(deps.broker, deps.processed, characterService, deps.logger, consumer.Config{
+			Topic:           cfg.Consumer.Topic,
+			DeadLetterTopic: cfg.Consumer.DeadLetterTopic,
+			MaxAttempts:     cfg.Consumer.MaxAttempts,
+			Backoff:         cfg.Consumer.Backoff,
+		})
+	}
+
+	return app, nil
+}
+
+// Handler serves the REST API under /character/v1 together with the health
+// and metrics endpoints.
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// Projector keeps the read model behind the character GET endpoints up to
+// date. The caller runs it; App never starts goroutines on its own.
+func (a *App) Projector() *coreservice.CharacterProjector {
+	return a.projector
+}
+
+// LedgerPoster copies the gold events into the ledger. The caller runs it.
+func (a *App) LedgerPoster() *coreservice.LedgerPoster {
+	return a.poster
+}
+
+// LedgerReconciler compares the ledger with the characters. The caller runs
+// it.
+func (a *App) LedgerReconciler() *coreservice.LedgerReconciler {
+	return a.reconciler
+}
+
+// SuspensionSweeper lifts the suspensions that ran out. The caller runs it.
+func (a *App) SuspensionSweeper() *coreservice.SuspensionSweeper {
+	return a.sweeper
+}
+
+// ClassCatalog serves the class catalog, loaded when the app is built. The
+// caller runs it to pick up the classes designers define.
+func (a *App) ClassCatalog() *coreservice.ClassCatalog {
+	return a.classes
+}
+
+// InventoryConsumer applies the inventory commands read from the broker, or
+// is nil when the consumer is disabled. The caller runs it.
+func (a *App) InventoryConsumer() *consumer.InventoryConsumer {
+	return a.consumer
+}
+
+// GRPCServer returns a gRPC server exposing the same core service to the
+// game servers listed in GAME_SERVER_SUBJECTS.
+func (a *App) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcadapter.RequestIDInterceptor(),
+		grpcadapter.AuthInterceptor(a.deps.tokens),
+		grpcadapter.RequireSubject(a.gameServers),
+	))
+	pb.RegisterCharacterServiceServer(server, grpcadapter.NewCharacterServer(a.service))
+	return server
+}
+
+func (a *App) newHandler(cfg config.Config) (http.Handler, error) {
+	apiDoc, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	validator, err := openapi.NewValidator(apiDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := rest.NewHandler(*rest.NewCharacterService(a.service, a.queries, a.ledger, a.wallets, a.moderation, a.transfers, a.classes), a.deps.tokens, a.deps.idempotency, cfg.IdempotencyTTL, a.deps.clock, validator, rest.Access{
+		PaymentSubjects: cfg.Wallet.PaymentSubjects,
+		DebitSubjects:   cfg.Wallet.DebitSubjects,
+		AdminSubjects:   cfg.AdminSubjects,
+	}, a.deps.rateLimits, middleware.RateLimits{
+		Default: cfg.RateLimit.Default,
+		Routes:  cfg.RateLimit.Routes,
+	})
+
+	v1 := http.NewServeMux()
+	handler.RegisterRoutes(v1)
+
+	instrumented := middleware.Chain(v1,
+		middleware.RequestID,
+		middleware.Tracing(a.deps.tracer, a.deps.propagator),
+		middleware.Metrics(a.deps.metrics.HTTPRequests, a.deps.metrics.HTTPDuration),
+	)
+
+	root := http.NewServeMux()
+	root.Handle("/character/v1/", http.StripPrefix("/character/v1", instrumented))
+	root.Handle("GET /healthz", a.deps.readiness.LivenessHandler())
+	root.Handle("GET /readyz", a.deps.readiness.ReadinessHandler())
+	root.Handle("GET /metrics", a.deps.metrics.Handler())
+
+	return root, nil
+}
+
+func (d dependencies) validate(cfg config.Config) error {
+	var missing []error
+	require := func(ok bool, name string) {
+		if !ok {
+			missing = append(missing, fmt.Errorf("%w: %s", ErrMissingDependency, name))
+		}
+	}
+
+	require(d.characters != nil, "character repository")
+	require(d.events != nil, "character event log")
+	require(d.views != nil, "character view repository")
+	require(d.history != nil, "inventory history")
+	require(d.suspensions != nil, "character suspensions")
+	require(d.gold != nil, "character gold")
+	require(d.classes != nil, "class repository")
+	require(d.ledger != nil, "ledger repository")
+	require(d.wallets != nil, "wallet repository")
+	require(d.idempotency != nil, "idempotency repository")
+	require(d.rateLimits != nil, "rate limit repository")
+	require(d.processed != nil, "processed message repository")
+	require(d.broker != nil || !cfg.Consumer.Enabled, "broker")
+	require(d.vault != nil, "vault gateway")
+	require(d.login != nil, "login gateway")
+	require(d.tokens != nil, "token validator")
+
+	return errors.Join(missing...)
+}
@@ -0,0 +1,1177 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/consumer"
+	"github.com/vterry/ddd-study/character/internal/adapters/input/rest/middleware"
+	memorybroker "github.com/vterry/ddd-study/character/internal/adapters/output/broker"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	coreservice "github.com/vterry/ddd-study/character/internal/core/service"
+	"github.com/vterry/ddd-study/character/internal/infra/app"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+)
+
+// fakeLogin accepts every login except the rejected ones
+type fakeLogin struct {
+	rejected map[uuid.UUID]bool
+}
+
+func (f fakeLogin) IsLoginValid(ctx context.Context, loginId login.LoginID) (bool, error) {
+	return !f.rejected[loginId.ID()], nil
+}
+
+// fakeTokens accepts only the "valid" token
+type fakeTokens struct{}
+
+func (fakeTokens) TokenValidation(ctx context.Context, token string) (bool, error) {
+	return token == "valid", nil
+}
+
+func (fakeTokens) Authenticate(ctx context.Context, token string) (string, error) {
+	if token != "valid" {
+		return "", errors.New("invalid token")
+	}
+	return "player-1", nil
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// countingRepository counts the characters saved through the API
+type countingRepository struct {
+	*memory.CharacterRepository
+	mu    sync.Mutex
+	saved int
+	ids   []character.CharacterID
+}
+
+func (c *countingRepository) Save(ctx context.Context, character character.Character) error {
+	c.mu.Lock()
+	c.saved++
+	c.ids = append(c.ids, character.CharacterID)
+	c.mu.Unlock()
+	return c.CharacterRepository.Save(ctx, character)
+}
+
+type testAPI struct {
+	server     *httptest.Server
+	characters *countingRepository
+	projector  *coreservice.CharacterProjector
+	poster     *coreservice.LedgerPoster
+	reconciler *coreservice.LedgerReconciler
+	broker     *memorybroker.InMemoryBroker
+	consumer   *consumer.InventoryConsumer
+	rejected   uuid.UUID
+	now        time.Time
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newTestAPIWithConfig(t, config.Default())
+}
+
+func newTestAPIWithConfig(t *testing.T, cfg config.Config) *testAPI {
+	t.Helper()
+	clock := fixedClock(time.Now())
+	api := &testAPI{
+		characters: &countingRepository{CharacterRepository: memory.NewCharacterRepository(clock, dao.InventoryPersistence{})},
+		broker:     memorybroker.NewInMemoryBroker(),
+		rejected:   uuid.New(),
+		now:        time.Time(clock),
+	}
+
+	application, err := app.New(cfg,
+		app.WithCharacterRepository(api.characters, "memory"),
+		app.WithEventLog(api.characters.CharacterRepository),
+		app.WithCharacterViewRepository(memory.NewCharacterViewRepository()),
+		app.WithInventoryHistory(api.characters.CharacterRepository),
+		app.WithCharacterSuspensions(api.characters.CharacterRepository),
+		app.WithCharacterGold(api.characters.CharacterRepository),
+		app.WithClassRepository(memory.NewClassRepository(class.Defaults().List()...)),
+		app.WithLedgerRepository(memory.NewLedgerRepository()),
+		app.WithWalletRepository(memory.NewWalletRepository()),
+		app.WithIdempotencyRepository(memory.NewIdempotencyRepository(clock)),
+		app.WithRateLimitRepository(memory.NewRateLimitRepository()),
+		app.WithProcessedMessages(api.characters.CharacterRepository),
+		app.WithBroker(api.broker),
+		app.WithVaultGateway(gateway.NewMockVaultGateway(logger.Nop{})),
+		app.WithLoginGateway(fakeLogin{rejected: map[uuid.UUID]bool{api.rejected: true}}),
+		app.WithTokenValidator(fakeTokens{}),
+		app.WithLogger(logger.Nop{}),
+		app.WithClock(clock),
+	)
+	require.NoError(t, err)
+
+	api.projector = application.Projector()
+	api.poster = application.LedgerPoster()
+	api.reconciler = application.LedgerReconciler()
+	api.consumer = application.InventoryConsumer()
+	api.server = httptest.NewServer(application.Handler())
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+type request struct {
+	method         string
+	path           string
+	body           string
+	token          string
+	idempotencyKey string
+	requestID      string
+}
+
+func (a *testAPI) do(t *testing.T, r request) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(r.method, a.server.URL+r.path, strings.NewReader(r.body))
+	require.NoError(t, err)
+	if r.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	if r.idempotencyKey != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, r.idempotencyKey)
+	}
+	if r.requestID != "" {
+		req.Header.Set(middleware.HeaderRequestID, r.requestID)
+	}
+
+	resp, err := a.server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func createCharacter(loginId uuid.UUID, nickname, class string) string {
+	return `{"userId":"` + loginId.String() + `","nickname":"` + nickname + `","class":"` + class + `"}`
+}
+
+func problemCode(t *testing.T, body string) string {
+	t.Helper()
+	var problem struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &problem), body)
+	return problem.Code
+}
+
+func TestNewRequiresPorts(t *testing.T) {
+	_, err := app.New(config.Default())
+	assert.ErrorIs(t, err, app.ErrMissingDependency)
+	for _, name := range []string{"character repository", "character event log", "character view repository", "inventory history", "idempotency repository", "vault gateway", "login gateway", "token validator"} {
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestOperationalEndpoints(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp, _ := api.do(t, request{method: http.MethodGet, path: "/healthz"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: "/readyz"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"status":"up"`)
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/openapi.json"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"openapi"`)
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/metrics"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `route="GET /openapi.json"`, "API calls are recorded")
+
+	resp, _ = api.do(t, request{method: http.MethodGet, path: "/character/v1/unknown"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCreateCharacter(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        func(api *testAPI) request
+		expectedStatus int
+		expectedCode   string
+		expectedSaves  int
+	}{
+		{
+			name: "created",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(uuid.New(), "Arthas", "warrior"), token: "valid", idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusOK,
+			expectedSaves:  1,
+		},
+		{
+			name: "missing bearer token",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(uuid.New(), "Arthas", "warrior"), idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "UNAUTHORIZED",
+		},
+		{
+			name: "forged bearer token",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(uuid.New(), "Arthas", "warrior"), token: "forged", idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "UNAUTHORIZED",
+		},
+		{
+			name: "missing idempotency key",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(uuid.New(), "Arthas", "warrior"), token: "valid"}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+		},
+		{
+			name: "payload violating the contract",
+			request: func(api *testAPI) request {
+				return request{body: `{"userId":"` + uuid.NewString() + `"}`, token: "valid", idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+		},
+		{
+			name: "nickname rejected by the domain",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(uuid.New(), "Art", "warrior"), token: "valid", idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_NICKNAME_SIZE",
+		},
+		{
+			name: "login rejected by the identity provider",
+			request: func(api *testAPI) request {
+				return request{body: createCharacter(api.rejected, "Arthas", "warrior"), token: "valid", idempotencyKey: "key-1"}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "INVALID_LOGIN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			r := tt.request(api)
+			r.method, r.path = http.MethodPost, "/character/v1/character"
+
+			resp, body := api.do(t, r)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, body)
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, problemCode(t, body))
+			}
+			assert.Equal(t, tt.expectedSaves, api.characters.saved)
+		})
+	}
+}
+
+func TestCreateCharacterIsIdempotent(t *testing.T) {
+	api := newTestAPI(t)
+	create := request{
+		method:         http.MethodPost,
+		path:           "/character/v1/character",
+		body:           createCharacter(uuid.New(), "Arthas", "mage"),
+		token:          "valid",
+		idempotencyKey: "key-1",
+	}
+
+	first, firstBody := api.do(t, create)
+	require.Equal(t, http.StatusOK, first.StatusCode, firstBody)
+
+	retry, retryBody := api.do(t, create)
+	assert.Equal(t, http.StatusOK, retry.StatusCode)
+	assert.Equal(t, "true", retry.Header.Get(middleware.IdempotentReplayHeader))
+	assert.Equal(t, firstBody, retryBody)
+	assert.Equal(t, 1, api.characters.saved, "the retry must not create a second character")
+
+	create.body = createCharacter(uuid.New(), "Jaina", "mage")
+	reused, reusedBody := api.do(t, create)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode)
+	assert.Equal(t, "IDEMPOTENCY_KEY_MISMATCH", problemCode(t, reusedBody))
+}
+
+func TestRequestIDIsEchoed(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp, _ := api.do(t, request{method: http.MethodGet, path: "/character/v1/openapi.json", requestID: "req-42"})
+	assert.Equal(t, "req-42", resp.Header.Get(middleware.HeaderRequestID))
+
+	resp, _ = api.do(t, request{method: http.MethodGet, path: "/character/v1/openapi.json"})
+	assert.NotEmpty(t, resp.Header.Get(middleware.HeaderRequestID), "a request id is generated when missing")
+}
+
+// createProjected creates a character through the API and projects it into
+// the read model.
+func (a *testAPI) createProjected(t *testing.T) character.CharacterID {
+	t.Helper()
+	return a.createProjectedNamed(t, "Arthas")
+}
+
+// createProjectedNamed creates a warrior. Nicknames are unique, so a test
+// creating several characters names them.
+func (a *testAPI) createProjectedNamed(t *testing.T, nickname string) character.CharacterID {
+	t.Helper()
+	resp, body := a.do(t, request{
+		method:         http.MethodPost,
+		path:           "/character/v1/character",
+		body:           createCharacter(uuid.New(), nickname, "warrior"),
+		token:          "valid",
+		idempotencyKey: uuid.NewString(),
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	_, err := a.projector.CatchUp(context.Background())
+	require.NoError(t, err)
+	return a.characters.ids[len(a.characters.ids)-1]
+}
+
+func TestInventoryConsumer(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		assert.Nil(t, newTestAPI(t).consumer)
+	})
+
+	t.Run("applies commands from the broker", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Consumer.Enabled = true
+		cfg.Consumer.Backoff = 0
+		api := newTestAPIWithConfig(t, cfg)
+		id := api.createProjected(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = api.consumer.Run(ctx) }()
+
+		pickGold := func(amount int) broker.Message {
+			return broker.Message{
+				ID:      uuid.NewString(),
+				Payload: []byte(`{"type":"PickGold","characterId":"` + id.ID().String() + `","amount":` + strconv.Itoa(amount) + `}`),
+			}
+		}
+		redelivered, last := pickGold(25), pickGold(5)
+		for _, msg := range []broker.Message{redelivered, redelivered, last} {
+			require.NoError(t, api.broker.Publish(ctx, cfg.Consumer.Topic, msg))
+		}
+
+		// the topic is read in order, so the redelivery was handled before
+		assert.Eventually(t, func() bool {
+			processed, err := api.characters.IsProcessed(ctx, last.ID)
+			return err == nil && processed
+		}, time.Second, 10*time.Millisecond)
+		stored, err := api.characters.FindCharacterById(ctx, id)
+		require.NoError(t, err)
+		inventory := stored.Inventory()
+		assert.Equal(t, 30, inventory.GetCurrentGold(), "the redelivered command is applied once")
+	})
+}
+
+func TestGetCharacter(t *testing.T) {
+	api := newTestAPI(t)
+	id := api.createProjected(t)
+	path := "/character/v1/character/" + id.ID().String()
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: path, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	var summary struct {
+		ID        string `json:"id"`
+		LoginID   string `json:"loginId"`
+		Nickname  string `json:"nickname"`
+		Class     string `json:"class"`
+		Gold      int    `json:"gold"`
+		ItemCount int    `json:"itemCount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &summary))
+	assert.Equal(t, id.ID().String(), summary.ID)
+	assert.Equal(t, "Arthas", summary.Nickname)
+	assert.Equal(t, "WARRIOR", summary.Class)
+	assert.Zero(t, summary.Gold)
+	assert.Zero(t, summary.ItemCount)
+	assert.Empty(t, summary.LoginID, "only admins see the login")
+
+	resp, _ = api.do(t, request{method: http.MethodGet, path: path})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + uuid.NewString(), token: "valid"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "CHARACTER_NOT_FOUND", problemCode(t, body))
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/character/not-a-uuid", token: "valid"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "MALFORMED_CHARACTER_ID", problemCode(t, body))
+
+	t.Run("admins see the login", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.AdminSubjects = []string{"player-1"}
+		admin := newTestAPIWithConfig(t, cfg)
+		id := admin.createProjected(t)
+		stored, err := admin.characters.FindCharacterById(context.Background(), id)
+		require.NoError(t, err)
+
+		resp, body := admin.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + id.ID().String(), token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		require.NoError(t, json.Unmarshal([]byte(body), &summary))
+		assert.Equal(t, stored.LoginID().ID().String(), summary.LoginID)
+	})
+}
+
+func TestGetCharacterReadsTheProjection(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+	id := api.createProjected(t)
+	path := "/character/v1/character/" + id.ID().String() + "/inventory"
+
+	stored, err := api.characters.FindCharacterById(ctx, id)
+	require.NoError(t, err)
+	sword, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), "Sword", 1)
+	require.NoError(t, err)
+	require.NoError(t, stored.PickItem(*sword))
+	require.NoError(t, stored.PickGold(75))
+	require.NoError(t, api.characters.Update(ctx, *stored))
+
+	var inventory struct {
+		Gold  int `json:"gold"`
+		Items []struct {
+			PlayerItemID string `json:"playerItemId"`
+			Description  string `json:"description"`
+			Quantity     int    `json:"quantity"`
+		} `json:"items"`
+	}
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: path, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &inventory))
+	assert.Zero(t, inventory.Gold, "the change is not projected yet")
+	assert.Empty(t, inventory.Items)
+
+	_, err = api.projector.CatchUp(ctx)
+	require.NoError(t, err)
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: path, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &inventory))
+	assert.Equal(t, 75, inventory.Gold)
+	require.Len(t, inventory.Items, 1)
+	assert.Equal(t, sword.ID().String(), inventory.Items[0].PlayerItemID)
+	assert.Equal(t, "Sword", inventory.Items[0].Description)
+	assert.Equal(t, 1, inventory.Items[0].Quantity)
+}
+
+func TestInventoryHistory(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	ctx := context.Background()
+	id := api.createProjected(t)
+	path := "/character/v1/character/" + id.ID().String() + "/inventory/history"
+
+	stored, err := api.characters.FindCharacterById(ctx, id)
+	require.NoError(t, err)
+	sword, err := playeritem.NewPlayerItem(item.NewItemID(uuid.New()), "Sword", 1)
+	require.NoError(t, err)
+	require.NoError(t, stored.PickItem(*sword))
+	require.NoError(t, stored.PickGold(75))
+	require.NoError(t, stored.DropItem(*sword))
+	require.NoError(t, api.characters.Update(ctx, *stored))
+
+	type history struct {
+		Entries []struct {
+			Type         string `json:"type"`
+			PlayerItemID string `json:"playerItemId"`
+			Amount       int    `json:"amount"`
+		} `json:"entries"`
+	}
+	get := func(t *testing.T, query string) history {
+		t.Helper()
+		resp, body := api.do(t, request{method: http.MethodGet, path: path + query, token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var h history
+		require.NoError(t, json.Unmarshal([]byte(body), &h))
+		return h
+	}
+
+	all := get(t, "")
+	require.Len(t, all.Entries, 3, "the history is read from the event store, not the lagging projection")
+	assert.Equal(t, "ItemAdded", all.Entries[0].Type)
+	assert.Equal(t, "GoldAdded", all.Entries[1].Type)
+	assert.Equal(t, 75, all.Entries[1].Amount)
+	assert.Equal(t, "ItemDropped", all.Entries[2].Type)
+	assert.Equal(t, sword.ID().String(), all.Entries[2].PlayerItemID)
+
+	bySword := get(t, "?itemId="+sword.ItemID().ID().String())
+	assert.Len(t, bySword.Entries, 2)
+
+	later := get(t, "?from="+api.now.Add(time.Second).Format(time.RFC3339))
+	assert.Empty(t, later.Entries)
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: path + "?from=2026-01-02T00:00:00Z&to=2026-01-01T00:00:00Z", token: "valid"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "INVALID_HISTORY_FILTER", problemCode(t, body))
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + uuid.NewString() + "/inventory/history", token: "valid"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "CHARACTER_NOT_FOUND", problemCode(t, body))
+
+	t.Run("players cannot read the history", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + id.ID().String() + "/inventory/history", token: "valid"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+}
+
+func TestGoldLedger(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	ctx := context.Background()
+	id := api.createProjected(t)
+
+	stored, err := api.characters.FindCharacterById(ctx, id)
+	require.NoError(t, err)
+	vaultId := stored.GetCurrentVaultId().ID()
+	require.NoError(t, stored.PickGold(100))
+	require.NoError(t, stored.DepositGold(40, stored.GetCurrentVaultId()))
+	require.NoError(t, api.characters.Update(ctx, *stored))
+	_, err = api.poster.CatchUp(ctx)
+	require.NoError(t, err)
+
+	balance := func(t *testing.T, account string) int {
+		t.Helper()
+		resp, body := api.do(t, request{method: http.MethodGet, path: "/character/v1/ledger/accounts/" + account + "/balance", token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var b struct {
+			Account string `json:"account"`
+			Balance int    `json:"balance"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &b))
+		assert.Equal(t, account, b.Account)
+		return b.Balance
+	}
+	assert.Equal(t, 60, balance(t, "character:"+id.ID().String()))
+	assert.Equal(t, 40, balance(t, "vault:"+vaultId.String()))
+
+	guildId := uuid.NewString()
+	post := request{
+		method:         http.MethodPost,
+		path:           "/character/v1/ledger/transactions",
+		body:           `{"transactionId":"guild-tax-1","reason":"guild tax","entries":[{"account":"vault:` + vaultId.String() + `","amount":-10},{"account":"guild:` + guildId + `","amount":10}]}`,
+		token:          "valid",
+		idempotencyKey: "tax-1",
+	}
+	resp, body := api.do(t, post)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	// a retry under a new idempotency key is still recognised by its id
+	post.idempotencyKey = "tax-1-retry"
+	resp, body = api.do(t, post)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, 30, balance(t, "vault:"+vaultId.String()))
+	assert.Equal(t, 10, balance(t, "guild:"+guildId))
+
+	report, err := api.reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+	assert.Zero(t, report.Imbalance)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unbalanced", `{"transactionId":"t-1","reason":"r","entries":[{"account":"world:source","amount":-5},{"account":"guild:` + guildId + `","amount":4}]}`, http.StatusUnprocessableEntity, "UNBALANCED_TRANSACTION"},
+		{"character account", `{"transactionId":"t-2","reason":"r","entries":[{"account":"world:source","amount":-5},{"account":"character:` + id.ID().String() + `","amount":5}]}`, http.StatusUnprocessableEntity, "CHARACTER_ACCOUNT_NOT_POSTABLE"},
+		{"unknown account", `{"transactionId":"t-3","reason":"r","entries":[{"account":"world:treasury","amount":-5},{"account":"guild:` + guildId + `","amount":5}]}`, http.StatusBadRequest, "INVALID_LEDGER_ACCOUNT"},
+		{"reused id", `{"transactionId":"guild-tax-1","reason":"guild tax","entries":[{"account":"vault:` + vaultId.String() + `","amount":-11},{"account":"guild:` + guildId + `","amount":11}]}`, http.StatusConflict, "TRANSACTION_CONFLICT"},
+		{"character event id", `{"transactionId":"character-event:999","reason":"r","entries":[{"account":"world:source","amount":-5},{"account":"guild:` + guildId + `","amount":5}]}`, http.StatusUnprocessableEntity, "RESERVED_TRANSACTION_ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/ledger/transactions", body: tt.body, token: "valid", idempotencyKey: tt.name})
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+			assert.Equal(t, tt.code, problemCode(t, body))
+		})
+	}
+
+	t.Run("players cannot post", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodPost, path: "/character/v1/ledger/transactions", body: `{"transactionId":"mint-1","reason":"r","entries":[{"account":"world:source","amount":-500},{"account":"guild:` + guildId + `","amount":500}]}`, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+
+	t.Run("players cannot read balances", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodGet, path: "/character/v1/ledger/accounts/guild:" + guildId + "/balance", token: "valid"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+}
+
+func TestWallet(t *testing.T) {
+	cfg := config.Default()
+	cfg.Wallet.PaymentSubjects = []string{"player-1"}
+	cfg.Wallet.DebitSubjects = []string{"player-1"}
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	loginId := uuid.NewString()
+	walletPath := "/character/v1/wallet/" + loginId
+
+	balance := func(t *testing.T, body string) int {
+		t.Helper()
+		var w struct {
+			LoginID string `json:"loginId"`
+			Balance int    `json:"balance"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &w))
+		assert.Equal(t, loginId, w.LoginID)
+		return w.Balance
+	}
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: walletPath, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Zero(t, balance(t, body))
+
+	purchase := request{method: http.MethodPost, path: walletPath + "/purchases", body: `{"purchaseId":"order-1","amount":500}`, token: "valid", idempotencyKey: "order-1"}
+	resp, body = api.do(t, purchase)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, 500, balance(t, body))
+
+	// the payment provider retrying under a new idempotency key is still
+	// recognised by the purchase id
+	purchase.idempotencyKey = "order-1-retry"
+	resp, body = api.do(t, purchase)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, 500, balance(t, body))
+
+	resp, body = api.do(t, request{method: http.MethodPost, path: walletPath + "/debits", body: `{"reference":"skin-1","amount":200,"reason":"skin"}`, token: "valid", idempotencyKey: "skin-1"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, 300, balance(t, body))
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"not enough cash", "/debits", `{"reference":"skin-2","amount":301,"reason":"skin"}`, http.StatusUnprocessableEntity, "NOT_ENOUGH_CASH"},
+		{"reused debit reference", "/debits", `{"reference":"skin-1","amount":100,"reason":"skin"}`, http.StatusConflict, "DUPLICATE_CASH_REFERENCE"},
+		{"reused purchase id", "/purchases", `{"purchaseId":"order-1","amount":900}`, http.StatusConflict, "DUPLICATE_CASH_REFERENCE"},
+		{"non positive amount", "/debits", `{"reference":"skin-3","amount":0,"reason":"skin"}`, http.StatusBadRequest, "INVALID_PAYLOAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := api.do(t, request{method: http.MethodPost, path: walletPath + tt.path, body: tt.body, token: "valid", idempotencyKey: tt.name})
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+			assert.Equal(t, tt.code, problemCode(t, body))
+		})
+	}
+
+	t.Run("unknown login", func(t *testing.T) {
+		resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/wallet/" + api.rejected.String() + "/purchases", body: `{"purchaseId":"order-2","amount":500}`, token: "valid", idempotencyKey: "order-2"})
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, body)
+		assert.Equal(t, "INVALID_LOGIN", problemCode(t, body))
+	})
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: walletPath, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, 300, balance(t, body))
+}
+
+func TestWalletPurchasesRequirePaymentSubject(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/wallet/" + uuid.NewString() + "/purchases", body: `{"purchaseId":"order-1","amount":500}`, token: "valid", idempotencyKey: "order-1"})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+	assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+}
+
+func TestWalletDebitsRequireDebitSubject(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/wallet/" + uuid.NewString() + "/debits", body: `{"reference":"skin-1","amount":200,"reason":"skin"}`, token: "valid", idempotencyKey: "skin-1"})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+	assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+}
+
+func TestWalletReadableByOwnerOnly(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: "/character/v1/wallet/" + uuid.NewString(), token: "valid"})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+	assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+}
+
+func TestWalletReadableByWalletServices(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*config.Config)
+		status    int
+	}{
+		{"payment service", func(cfg *config.Config) { cfg.Wallet.PaymentSubjects = []string{"player-1"} }, http.StatusOK},
+		{"shop service", func(cfg *config.Config) { cfg.Wallet.DebitSubjects = []string{"player-1"} }, http.StatusOK},
+		{"admin", func(cfg *config.Config) { cfg.AdminSubjects = []string{"player-1"} }, http.StatusOK},
+		{"another login", func(cfg *config.Config) { cfg.Wallet.PaymentSubjects = []string{"payments"} }, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.configure(&cfg)
+			api := newTestAPIWithConfig(t, cfg)
+
+			resp, body := api.do(t, request{method: http.MethodGet, path: "/character/v1/wallet/" + uuid.NewString(), token: "valid"})
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Routes = map[string]ratelimit.Limit{"GET /character/{characterId}": {Requests: 2, Per: time.Minute}}
+	api := newTestAPIWithConfig(t, cfg)
+	path := "/character/v1/character/" + uuid.NewString()
+
+	for range 2 {
+		resp, body := api.do(t, request{method: http.MethodGet, path: path, token: "valid"})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, body)
+	}
+
+	resp, body := api.do(t, request{method: http.MethodGet, path: path, token: "valid"})
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, body)
+	assert.Equal(t, "RATE_LIMITED", problemCode(t, body))
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/classes", token: "valid"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "120", resp.Header.Get("RateLimit-Limit"), "other routes use the default limit")
+}
+
+func TestCharacterSearch(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	admin := newTestAPIWithConfig(t, cfg)
+	player := newTestAPI(t)
+
+	type page struct {
+		Characters []struct {
+			ID       string `json:"id"`
+			LoginID  string `json:"loginId"`
+			Nickname string `json:"nickname"`
+			Class    string `json:"class"`
+			Level    int    `json:"level"`
+		} `json:"characters"`
+		NextCursor string `json:"nextCursor"`
+	}
+	search := func(api *testAPI, query string) page {
+		t.Helper()
+		resp, body := api.do(t, request{method: http.MethodGet, path: "/character/v1/character/search?" + query, token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var p page
+		require.NoError(t, json.Unmarshal([]byte(body), &p))
+		return p
+	}
+	nicknames := func(p page) []string {
+		names := make([]string, 0, len(p.Characters))
+		for _, c := range p.Characters {
+			names = append(names, c.Nickname)
+		}
+		return names
+	}
+
+	for _, api := range []*testAPI{admin, player} {
+		for _, c := range []struct{ nickname, class string }{
+			{"Arthas", "warrior"}, {"arwen", "mage"}, {"Aragorn", "ranger"}, {"Thrall", "warrior"},
+		} {
+			resp, body := api.do(t, request{
+				method:         http.MethodPost,
+				path:           "/character/v1/character",
+				body:           createCharacter(uuid.New(), c.nickname, c.class),
+				token:          "valid",
+				idempotencyKey: uuid.NewString(),
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		}
+		_, err := api.projector.CatchUp(context.Background())
+		require.NoError(t, err)
+	}
+
+	first := search(player, "nickname=AR&limit=2")
+	assert.Equal(t, []string{"Aragorn", "Arthas"}, nicknames(first))
+	require.NotEmpty(t, first.NextCursor)
+	second := search(player, "nickname=AR&limit=2&cursor="+first.NextCursor)
+	assert.Equal(t, []string{"arwen"}, nicknames(second))
+	assert.Empty(t, second.NextCursor, "the last page has no cursor")
+
+	assert.Equal(t, []string{"Thrall", "Arthas"}, nicknames(search(player, "class=WARRIOR&order=desc")))
+
+	started := search(player, "nickname=AR&minLevel=1&maxLevel=1")
+	assert.Equal(t, []string{"Aragorn", "Arthas", "arwen"}, nicknames(started))
+	assert.Equal(t, 1, started.Characters[0].Level)
+	assert.Empty(t, search(player, "minLevel=2").Characters, "new characters start at level 1")
+
+	for _, c := range search(player, "").Characters {
+		assert.Empty(t, c.LoginID, "login ids are hidden from players")
+	}
+	for _, c := range search(admin, "").Characters {
+		assert.NotEmpty(t, c.LoginID, "login ids are shown to admins")
+	}
+
+	for name, query := range map[string]string{
+		"malformed cursor":          "cursor=not-a-cursor",
+		"cursor of another order":   "nickname=AR&limit=2&order=desc&cursor=" + first.NextCursor,
+		"guild id that is not uuid": "guildId=guild",
+		"inverted level range":      "minLevel=5&maxLevel=2",
+	} {
+		resp, body := player.do(t, request{method: http.MethodGet, path: "/character/v1/character/search?" + query, token: "valid"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name)
+		assert.Equal(t, "INVALID_SEARCH", problemCode(t, body), name)
+	}
+
+	resp, body := player.do(t, request{method: http.MethodGet, path: "/character/v1/character/search?limit=500", token: "valid"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "INVALID_PAYLOAD", problemCode(t, body), "the contract bounds the limit")
+
+	resp, body = player.do(t, request{method: http.MethodGet, path: "/character/v1/character/search?minLevel=0", token: "valid"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "INVALID_PAYLOAD", problemCode(t, body), "the contract bounds the levels")
+
+	resp, _ = player.do(t, request{method: http.MethodGet, path: "/character/v1/character/search"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestCharacterModeration(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	characterId := api.createProjected(t)
+	characterPath := "/character/v1/character/" + characterId.ID().String()
+	until := api.now.Add(time.Hour).UTC().Format(time.RFC3339)
+
+	moderate := func(t *testing.T, path, body string) (*http.Response, string) {
+		t.Helper()
+		return api.do(t, request{method: http.MethodPost, path: characterPath + path, body: body, token: "valid", idempotencyKey: uuid.NewString()})
+	}
+	standing := func(t *testing.T, body string) (string, string) {
+		t.Helper()
+		var s struct {
+			CharacterID    string `json:"characterId"`
+			Status         string `json:"status"`
+			SuspendedUntil string `json:"suspendedUntil"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &s))
+		assert.Equal(t, characterId.ID().String(), s.CharacterID)
+		return s.Status, s.SuspendedUntil
+	}
+
+	t.Run("players cannot moderate", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodPost, path: characterPath + "/ban", body: `{"reason":"cheating"}`, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+
+	resp, body := moderate(t, "/suspension", `{"until":"`+until+`","reason":"spam"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	status, suspendedUntil := standing(t, body)
+	assert.Equal(t, "SUSPENDED", status)
+	assert.Equal(t, until, suspendedUntil)
+
+	resp, body = moderate(t, "/suspension/lift", `{"reason":"appeal accepted"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	status, suspendedUntil = standing(t, body)
+	assert.Equal(t, "ACTIVE", status)
+	assert.Empty(t, suspendedUntil)
+
+	resp, body = moderate(t, "/ban", `{"reason":"cheating"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	status, _ = standing(t, body)
+	assert.Equal(t, "BANNED", status)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"suspend a banned character", "/suspension", `{"until":"` + until + `","reason":"spam"}`, http.StatusConflict, "INVALID_STATUS_TRANSITION"},
+		{"lift without a suspension", "/suspension/lift", `{"reason":"appeal accepted"}`, http.StatusConflict, "INVALID_STATUS_TRANSITION"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := moderate(t, tt.path, tt.body)
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+			assert.Equal(t, tt.code, problemCode(t, body))
+		})
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		otherPath := "/character/v1/character/" + api.createProjectedNamed(t, "Uther").ID().String()
+		past := api.now.Add(-time.Hour).UTC().Format(time.RFC3339)
+
+		resp, body := api.do(t, request{method: http.MethodPost, path: otherPath + "/suspension", body: `{"until":"` + past + `","reason":"spam"}`, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+		assert.Equal(t, "INVALID_SUSPENSION_END", problemCode(t, body))
+
+		resp, body = api.do(t, request{method: http.MethodPost, path: otherPath + "/ban", body: `{"reason":"  "}`, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+		assert.Equal(t, "MISSING_STATUS_REASON", problemCode(t, body))
+	})
+
+	t.Run("unknown character", func(t *testing.T) {
+		resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/character/" + uuid.NewString() + "/ban", body: `{"reason":"cheating"}`, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, body)
+	})
+}
+
+func TestCharacterExportImport(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	id := api.createProjected(t)
+	exportPath := "/character/v1/character/" + id.ID().String() + "/export"
+
+	t.Run("players cannot export", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodGet, path: exportPath, token: "valid"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+
+	resp, exported := api.do(t, request{method: http.MethodGet, path: exportPath, token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, exported)
+	var document struct {
+		Format    string `json:"format"`
+		Version   int    `json:"version"`
+		Character struct {
+			ID       string `json:"id"`
+			LoginID  string `json:"loginId"`
+			Nickname string `json:"nickname"`
+			Level    int    `json:"level"`
+		} `json:"character"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(exported), &document))
+	assert.Equal(t, "character-export", document.Format)
+	assert.Equal(t, 2, document.Version)
+	assert.Equal(t, 1, document.Character.Level)
+	assert.Equal(t, id.ID().String(), document.Character.ID)
+	assert.Equal(t, "Arthas", document.Character.Nickname)
+
+	// the character moves to another environment
+	target := newTestAPIWithConfig(t, cfg)
+	importing := func(t *testing.T, into *testAPI, loginId string) (*http.Response, string) {
+		t.Helper()
+		body := `{"document":` + exported + `,"remap":{"` + document.Character.LoginID + `":"` + loginId + `"}}`
+		return into.do(t, request{method: http.MethodPost, path: "/character/v1/character/import", body: body, token: "valid", idempotencyKey: uuid.NewString()})
+	}
+
+	newLogin := uuid.NewString()
+	resp, body := importing(t, target, newLogin)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	var result struct {
+		CharacterID string            `json:"characterId"`
+		IDs         map[string]string `json:"ids"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &result))
+	assert.NotEqual(t, id.ID().String(), result.CharacterID)
+	assert.Equal(t, result.CharacterID, result.IDs[id.ID().String()])
+
+	resp, body = target.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + result.CharacterID + "/export", token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, `"loginId":"`+newLogin+`"`)
+	assert.Contains(t, body, `"nickname":"Arthas"`)
+
+	t.Run("login unknown here", func(t *testing.T) {
+		resp, body := importing(t, target, target.rejected.String())
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, body)
+		assert.Equal(t, "INVALID_LOGIN", problemCode(t, body))
+	})
+
+	t.Run("nickname taken here", func(t *testing.T) {
+		resp, body := importing(t, api, uuid.NewString())
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, body)
+		assert.Equal(t, "NICKNAME_TAKEN", problemCode(t, body))
+	})
+}
+
+// Levels are only raised by imports, so the characters of different levels
+// are brought in from another environment.
+func TestCharacterSearchByLevel(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	source := newTestAPIWithConfig(t, cfg)
+	target := newTestAPIWithConfig(t, cfg)
+
+	for _, c := range []struct {
+		nickname string
+		level    int
+	}{{"Arthas", 1}, {"Jaina", 5}, {"Thrall", 10}, {"Illidan", 20}} {
+		id := source.createProjectedNamed(t, c.nickname)
+		resp, exported := source.do(t, request{method: http.MethodGet, path: "/character/v1/character/" + id.ID().String() + "/export", token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, exported)
+		require.Contains(t, exported, `"level":1`)
+		exported = strings.Replace(exported, `"level":1`, `"level":`+strconv.Itoa(c.level), 1)
+
+		resp, body := target.do(t, request{method: http.MethodPost, path: "/character/v1/character/import", body: `{"document":` + exported + `}`, token: "valid", idempotencyKey: uuid.NewString()})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	}
+	_, err := target.projector.CatchUp(context.Background())
+	require.NoError(t, err)
+
+	search := func(query string) map[string]int {
+		t.Helper()
+		resp, body := target.do(t, request{method: http.MethodGet, path: "/character/v1/character/search?" + query, token: "valid"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var p struct {
+			Characters []struct {
+				Nickname string `json:"nickname"`
+				Level    int    `json:"level"`
+			} `json:"characters"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &p))
+		levels := make(map[string]int, len(p.Characters))
+		for _, c := range p.Characters {
+			levels[c.Nickname] = c.Level
+		}
+		return levels
+	}
+
+	assert.Equal(t, map[string]int{"Jaina": 5, "Thrall": 10}, search("minLevel=5&maxLevel=10"), "both bounds are included")
+	assert.Equal(t, map[string]int{"Thrall": 10, "Illidan": 20}, search("minLevel=6"))
+	assert.Equal(t, map[string]int{"Arthas": 1, "Jaina": 5}, search("maxLevel=9"))
+	assert.Empty(t, search("minLevel=21"))
+}
+
+func TestClassCatalog(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminSubjects = []string{"player-1"}
+	api := newTestAPIWithConfig(t, cfg)
+	mace := uuid.NewString()
+	define := `{"displayName":"Paladin","startingItems":[{"itemId":"` + mace + `","description":"Mace","quantity":1}],"stats":{"faith":7}}`
+
+	t.Run("players cannot define classes", func(t *testing.T) {
+		player := newTestAPI(t)
+		resp, body := player.do(t, request{method: http.MethodPut, path: "/character/v1/classes/PALADIN", body: define, token: "valid", idempotencyKey: uuid.NewString()})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, body)
+		assert.Equal(t, "FORBIDDEN", problemCode(t, body))
+	})
+
+	resp, body := api.do(t, request{method: http.MethodPost, path: "/character/v1/character", body: createCharacter(uuid.New(), "Uther", "paladin"), token: "valid", idempotencyKey: uuid.NewString()})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+	assert.Equal(t, "INVALID_CLASS", problemCode(t, body))
+
+	resp, body = api.do(t, request{method: http.MethodPut, path: "/character/v1/classes/paladin", body: define, token: "valid", idempotencyKey: uuid.NewString()})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, `"id":"PALADIN"`)
+
+	resp, body = api.do(t, request{method: http.MethodGet, path: "/character/v1/classes", token: "valid"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	var listed struct {
+		Classes []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"displayName"`
+		} `json:"classes"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &listed))
+	var ids []string
+	for _, c := range listed.Classes {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"MAGE", "PALADIN", "RANGER", "WARRIOR"}, ids)
+
+	resp, body = api.do(t, request{method: http.MethodPost, path: "/character/v1/character", body: createCharacter(uuid.New(), "Uther", "paladin"), token: "valid", idempotencyKey: uuid.NewString()})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	created, err := api.characters.FindCharacterById(context.Background(), api.characters.ids[len(api.characters.ids)-1])
+	require.NoError(t, err)
+	held := created.Inventory()
+	require.Len(t, held.Items(), 1, "starts with the class items")
+	assert.Equal(t, mace, held.Items()[0].ItemID().ID().String())
+
+	t.Run("invalid definitions", func(t *testing.T) {
+		tests := []struct {
+			name, path, body, code string
+		}{
+			{"short id", "/character/v1/classes/P", define, "INVALID_PAYLOAD"},
+			{"id starting with a digit", "/character/v1/classes/1DRUID", define, "INVALID_CLASS_ID"},
+			{"missing display name", "/character/v1/classes/DRUID", `{"displayName":""}`, "INVALID_PAYLOAD"},
+			{"bad item", "/character/v1/classes/DRUID", `{"displayName":"Druid","startingItems":[{"itemId":"staff","description":"Staff","quantity":1}]}`, "INVALID_STARTING_ITEM"},
+			{"negative stat", "/character/v1/classes/DRUID", `{"displayName":"Druid","stats":{"wisdom":-1}}`, "INVALID_PAYLOAD"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp, body := api.do(t, request{method: http.MethodPut, path: tt.path, body: tt.body, token: "valid", idempotencyKey: uuid.NewString()})
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+				assert.Equal(t, tt.code, problemCode(t, body))
+			})
+		}
+	})
+}
+
+func TestUnicodeNicknames(t *testing.T) {
+	cfg := config.Default()
+	cfg.Nickname.Scripts = []string{"Latin", "Han", "Cyrillic"}
+	api := newTestAPIWithConfig(t, cfg)
+	create := func(t *testing.T, nickname string) (*http.Response, string) {
+		t.Helper()
+		return api.do(t, request{method: http.MethodPost, path: "/character/v1/character", body: createCharacter(uuid.New(), nickname, "mage"), token: "valid", idempotencyKey: uuid.NewString()})
+	}
+
+	for _, nickname := range []string{"João", "諸葛孔明", "Arthas"} {
+		resp, body := create(t, nickname)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	}
+
+	tests := []struct {
+		name, nickname, code string
+		status               int
+	}{
+		{"cyrillic lookalike", "Arth\u0430s", "NICKNAME_TAKEN", http.StatusConflict},
+		{"other case", "ARTHAS", "NICKNAME_TAKEN", http.StatusConflict},
+		{"full width", "Ａｒｔｈａｓ", "NICKNAME_TAKEN", http.StatusConflict},
+		{"script not allowed", "서울사람", "INVALID_NICKNAME_CHARS", http.StatusBadRequest},
+		{"too long in graphemes", "ÁÁÁÁÁÁÁÁÁÁÁÁÁÁÁÁ", "INVALID_NICKNAME_SIZE", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := create(t, tt.nickname)
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+			assert.Equal(t, tt.code, problemCode(t, body))
+		})
+	}
+}
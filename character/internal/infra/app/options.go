@@ -0,0 +1,196 @@
+package app
+
+import (
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/token"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/broker"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"github.com/vterry/ddd-study/character/internal/infra/health"
+	"github.com/vterry/ddd-study/character/internal/infra/metrics"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option provides one of the ports App is wired from.
+type Option func(*dependencies)
+
+type dependencies struct {
+	characters    repository.CharacterRepository
+	storageSystem string
+	events        repository.CharacterEventLog
+	views         repository.CharacterViewRepository
+	history       repository.InventoryHistory
+	suspensions   repository.CharacterSuspensions
+	gold          repository.CharacterGold
+	classes       repository.ClassRepository
+	ledger        repository.LedgerRepository
+	wallets       repository.WalletRepository
+	idempotency   repository.IdempotencyRepository
+	rateLimits    repository.RateLimitRepository
+	processed     repository.ProcessedMessageRepository
+	broker        broker.Broker
+	vault         gateway.Vault
+	login         gateway.Login
+	tokens        token.AuthService
+	logger        logger.Logger
+	clock         clock.Clock
+	metrics       *metrics.Metrics
+	tracer        trace.TracerProvider
+	propagator    propagation.TextMapPropagator
+	readiness     *health.Checker
+}
+
+// WithCharacterRepository sets the character repository. system names the
+// backend in metrics and traces, e.g. "mysql".
+func WithCharacterRepository(repo repository.CharacterRepository, system string) Option {
+	return func(d *dependencies) {
+		d.characters = repo
+		d.storageSystem = system
+	}
+}
+
+// WithEventLog sets the log the character read model is projected from. It
+// must read the events the character repository appends.
+func WithEventLog(events repository.CharacterEventLog) Option {
+	return func(d *dependencies) {
+		d.events = events
+	}
+}
+
+func WithCharacterViewRepository(views repository.CharacterViewRepository) Option {
+	return func(d *dependencies) {
+		d.views = views
+	}
+}
+
+func WithInventoryHistory(history repository.InventoryHistory) Option {
+	return func(d *dependencies) {
+		d.history = history
+	}
+}
+
+// WithCharacterSuspensions sets where the suspension sweeper finds the
+// suspensions that ran out. It must read the character repository.
+func WithCharacterSuspensions(suspensions repository.CharacterSuspensions) Option {
+	return func(d *dependencies) {
+		d.suspensions = suspensions
+	}
+}
+
+// WithCharacterGold sets where the ledger reconciler finds the characters
+// holding gold. It must read the character repository.
+func WithCharacterGold(gold repository.CharacterGold) Option {
+	return func(d *dependencies) {
+		d.gold = gold
+	}
+}
+
+// WithClassRepository sets the store of the class catalog.
+func WithClassRepository(classes repository.ClassRepository) Option {
+	return func(d *dependencies) {
+		d.classes = classes
+	}
+}
+
+// WithLedgerRepository sets the store of the gold ledger, which is fed from
+// the event log.
+func WithLedgerRepository(ledger repository.LedgerRepository) Option {
+	return func(d *dependencies) {
+		d.ledger = ledger
+	}
+}
+
+// WithWalletRepository sets the store of the premium cash wallets.
+func WithWalletRepository(wallets repository.WalletRepository) Option {
+	return func(d *dependencies) {
+		d.wallets = wallets
+	}
+}
+
+func WithIdempotencyRepository(repo repository.IdempotencyRepository) Option {
+	return func(d *dependencies) {
+		d.idempotency = repo
+	}
+}
+
+// WithRateLimitRepository sets the store of the rate limiter buckets.
+func WithRateLimitRepository(limits repository.RateLimitRepository) Option {
+	return func(d *dependencies) {
+		d.rateLimits = limits
+	}
+}
+
+// WithProcessedMessages sets where the inventory consumer checks which
+// messages were applied. It must read the message ids the character
+// repository records.
+func WithProcessedMessages(processed repository.ProcessedMessageRepository) Option {
+	return func(d *dependencies) {
+		d.processed = processed
+	}
+}
+
+// WithBroker sets the broker the inventory consumer reads its commands from.
+// It is only required when the consumer is enabled.
+func WithBroker(broker broker.Broker) Option {
+	return func(d *dependencies) {
+		d.broker = broker
+	}
+}
+
+func WithVaultGateway(vault gateway.Vault) Option {
+	return func(d *dependencies) {
+		d.vault = vault
+	}
+}
+
+func WithLoginGateway(login gateway.Login) Option {
+	return func(d *dependencies) {
+		d.login = login
+	}
+}
+
+func WithTokenValidator(tokens token.AuthService) Option {
+	return func(d *dependencies) {
+		d.tokens = tokens
+	}
+}
+
+// WithLogger replaces the default zap logger.
+func WithLogger(logger logger.Logger) Option {
+	return func(d *dependencies) {
+		d.logger = logger
+	}
+}
+
+// WithClock replaces the wall clock.
+func WithClock(clock clock.Clock) Option {
+	return func(d *dependencies) {
+		d.clock = clock
+	}
+}
+
+// WithMetrics shares collectors with adapters built outside App, such as
+// the instrumented Keycloak transport.
+func WithMetrics(metrics *metrics.Metrics) Option {
+	return func(d *dependencies) {
+		d.metrics = metrics
+	}
+}
+
+// WithTracing replaces the global tracer provider and propagator.
+func WithTracing(tracer trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(d *dependencies) {
+		d.tracer = tracer
+		d.propagator = propagator
+	}
+}
+
+// WithReadiness sets the checker behind GET /readyz. Without it readiness
+// always reports up.
+func WithReadiness(checker *health.Checker) Option {
+	return func(d *dependencies) {
+		d.readiness = checker
+	}
+}
@@ -1,60 +1,650 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
-	"github.com/joho/godotenv"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProfileDev  = "dev"
+	ProfileProd = "prod"
+)
+
+const (
+	StorageMySQL    = "mysql"
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+const (
+	RateLimitMemory = "memory"
+	RateLimitMySQL  = "mysql"
+)
+
+const BrokerMemory = "memory"
+
+const (
+	AuthKeycloak = "keycloak"
+	AuthFake     = "fake"
+)
+
+const (
+	InventoryState  = "state"
+	InventoryEvents = "events"
+)
+
+// devDbPassword is only acceptable on a developer machine. Validate rejects
+// it outside the dev profile.
+const devDbPassword = "characterPW"
+
+var tracingExporters = []string{"none", "stdout", "otlp"}
+
+var postgresSSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+var (
+	ErrInvalidConfig  = errors.New("invalid configuration")
+	ErrInsecureConfig = errors.New("insecure configuration")
 )
 
 type Config struct {
-	Addr string
-	Db   DbConfig
-	Auth KeycloakConfig
+	Profile            string           `yaml:"profile"`
+	Addr               string           `yaml:"addr"`
+	GrpcAddr           string           `yaml:"grpcAddr"`
+	GameServerSubjects []string         `yaml:"gameServerSubjects"`
+	Storage            string           `yaml:"storage"`
+	Db                 DbConfig         `yaml:"db"`
+	AuthProvider       string           `yaml:"authProvider"`
+	Auth               KeycloakConfig   `yaml:"auth"`
+	AdminSubjects      []string         `yaml:"adminSubjects"`
+	IdempotencyTTL     time.Duration    `yaml:"idempotencyTTL"`
+	HealthCheckTimeout time.Duration    `yaml:"healthCheckTimeout"`
+	Tracing            TracingConfig    `yaml:"tracing"`
+	Projection         ProjectionConfig `yaml:"projection"`
+	Inventory          InventoryConfig  `yaml:"inventory"`
+	Ledger             LedgerConfig     `yaml:"ledger"`
+	Wallet             WalletConfig     `yaml:"wallet"`
+	Moderation         ModerationConfig `yaml:"moderation"`
+	Classes            ClassesConfig    `yaml:"classes"`
+	Nickname           NicknameConfig   `yaml:"nickname"`
+	RateLimit          RateLimitConfig  `yaml:"rateLimit"`
+	Consumer           ConsumerConfig   `yaml:"consumer"`
 }
 
+// DbConfig locates the database of STORAGE=mysql or STORAGE=postgres.
+// SSLMode only applies to PostgreSQL.
 type DbConfig struct {
-	User     string
-	Password string
-	Address  string
-	Name     string
+	User             string `yaml:"user"`
+	Password         string `yaml:"password"`
+	Address          string `yaml:"address"`
+	Name             string `yaml:"name"`
+	SSLMode          string `yaml:"sslMode"`
+	MigrateOnStartup bool   `yaml:"migrateOnStartup"`
 }
 
-type KeycloakConfig struct {
-	BaseURL      string
-	ClientID     string
-	ClientSecret string
-	Realm        string
+// ProjectionConfig drives the projector that keeps the character read model
+// up to date.
+type ProjectionConfig struct {
+	PollInterval     time.Duration `yaml:"pollInterval"`
+	BatchSize        int           `yaml:"batchSize"`
+	GapTimeout       time.Duration `yaml:"gapTimeout"`
+	RebuildOnStartup bool          `yaml:"rebuildOnStartup"`
 }
 
-var Envs = initConfig()
+// InventoryConfig selects how inventories are persisted. With
+// InventoryEvents they are rebuilt from their events, replaying from the
+// latest of the snapshots taken every SnapshotEvery inventory events. The
+// inventory tables are written in both modes, so either can be switched to.
+type InventoryConfig struct {
+	Persistence   string `yaml:"persistence"`
+	SnapshotEvery int    `yaml:"snapshotEvery"`
+}
 
-func initConfig() Config {
+// LedgerConfig drives the poster that copies the gold events into the ledger
+// and the job reconciling the ledger with the characters. The poster reads
+// the event log with the batch size and gap timeout of the projection.
+type LedgerConfig struct {
+	PollInterval      time.Duration `yaml:"pollInterval"`
+	ReconcileInterval time.Duration `yaml:"reconcileInterval"`
+}
 
-	if err := godotenv.Load(); err != nil {
-		fmt.Printf("Warning: .env file not found: %v\n", err)
-	}
+// WalletConfig lists the token subjects of the payment services trusted to
+// credit purchased cash, and of the shop services trusted to spend it. With
+// none, no purchase can be credited or no cash spent.
+type WalletConfig struct {
+	PaymentSubjects []string `yaml:"paymentSubjects"`
+	DebitSubjects   []string `yaml:"debitSubjects"`
+}
+
+// ModerationConfig drives the job lifting the suspensions that ran out. A
+// character stays frozen for up to SweepInterval after its suspension ends.
+type ModerationConfig struct {
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+}
+
+// ClassesConfig drives the reload of the class catalog. A class defined on
+// another instance shows up here within RefreshInterval.
+type ClassesConfig struct {
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+}
+
+// NicknameConfig lists the Unicode scripts, named as in unicode.Scripts,
+// whose letters nicknames may hold.
+type NicknameConfig struct {
+	Scripts []string `yaml:"scripts"`
+}
+
+// RateLimitConfig limits how often each account, or each client IP on the
+// routes without authentication, may call a route. Routes are keyed by the
+// pattern they are registered with, e.g. "POST /character", and the others
+// use Default. With RateLimitMySQL every replica shares the same buckets;
+// with RateLimitMemory each replica counts on its own.
+type RateLimitConfig struct {
+	Store   string                     `yaml:"store"`
+	Default ratelimit.Limit            `yaml:"default"`
+	Routes  map[string]ratelimit.Limit `yaml:"routes"`
+}
+
+// ConsumerConfig drives the consumer applying the inventory commands game
+// servers publish to Topic. A command is tried MaxAttempts times, Backoff
+// longer after each failure, before it is moved to DeadLetterTopic. The only
+// broker so far is BrokerMemory, which nothing outside the process publishes
+// to, so the consumer can only be enabled in the dev profile.
+type ConsumerConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	Broker          string        `yaml:"broker"`
+	Topic           string        `yaml:"topic"`
+	DeadLetterTopic string        `yaml:"deadLetterTopic"`
+	MaxAttempts     int           `yaml:"maxAttempts"`
+	Backoff         time.Duration `yaml:"backoff"`
+}
+
+type TracingConfig struct {
+	Exporter     string `yaml:"exporter"`
+	ServiceName  string `yaml:"serviceName"`
+	OTLPEndpoint string `yaml:"otlpEndpoint"`
+}
+
+// KeycloakConfig is only read with AuthKeycloak. AuthFake, allowed in the
+// dev profile only, trusts every bearer token as the subject it names and
+// every login, so the service runs without an identity provider.
+type KeycloakConfig struct {
+	BaseURL      string `yaml:"baseURL"`
+	ClientID     string `yaml:"clientID"`
+	ClientSecret string `yaml:"clientSecret"`
+	Realm        string `yaml:"realm"`
+}
 
+// Default returns the built-in configuration, the first layer Load starts
+// from. The client secret has no default: it must always be provided.
+func Default() Config {
 	return Config{
-		Addr: getEnv("APP_ADDR", ":8080"),
+		Profile:  ProfileProd,
+		Addr:     ":8080",
+		GrpcAddr: ":9090",
+		Storage:  StorageMySQL,
 		Db: DbConfig{
-			User:     getEnv("DB_USER", "character"),
-			Password: getEnv("DB_PASSWORD", "characterPW"),
-			Address:  fmt.Sprintf("%s:%s", getEnv("DB_HOST", "127.0.0.1"), getEnv("DB_PORT", "3306")),
-			Name:     getEnv("DB_NAME", "character-db"),
+			User:     "character",
+			Password: devDbPassword,
+			Address:  "127.0.0.1:3306",
+			Name:     "character-db",
+			SSLMode:  "require",
 		},
+		AuthProvider: AuthKeycloak,
 		Auth: KeycloakConfig{
-			BaseURL:      getEnv("AUTH_BASE_URL", "http://localhost:7080"),
-			ClientID:     getEnv("AUTH_CLIENT_ID", "playground"),
-			ClientSecret: getEnv("AUTH_CLIENT_SECRET", "PAfdvjPnUDFyTqm5fBuqjHxiAJCGQLVu"),
-			Realm:        getEnv("AUTH_REALM", "playground"),
+			BaseURL:  "http://localhost:7080",
+			ClientID: "playground",
+			Realm:    "playground",
+		},
+		IdempotencyTTL:     24 * time.Hour,
+		HealthCheckTimeout: 2 * time.Second,
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			ServiceName:  "character",
+			OTLPEndpoint: "localhost:4318",
+		},
+		Projection: ProjectionConfig{
+			PollInterval: 500 * time.Millisecond,
+			BatchSize:    100,
+			GapTimeout:   5 * time.Second,
+		},
+		Inventory: InventoryConfig{
+			Persistence:   InventoryState,
+			SnapshotEvery: 50,
+		},
+		Ledger: LedgerConfig{
+			PollInterval:      time.Second,
+			ReconcileInterval: 10 * time.Minute,
+		},
+		Moderation: ModerationConfig{
+			SweepInterval: time.Minute,
+		},
+		Classes: ClassesConfig{
+			RefreshInterval: time.Minute,
+		},
+		Nickname: NicknameConfig{
+			Scripts: slices.Clone(character.DefaultNicknameScripts),
 		},
+		RateLimit: RateLimitConfig{
+			Store:   RateLimitMemory,
+			Default: ratelimit.Limit{Requests: 120, Per: time.Minute},
+			Routes: map[string]ratelimit.Limit{
+				"POST /character": {Requests: 10, Per: time.Minute},
+			},
+		},
+		Consumer: ConsumerConfig{
+			Broker:          BrokerMemory,
+			Topic:           "character.inventory.commands",
+			DeadLetterTopic: "character.inventory.commands.dlq",
+			MaxAttempts:     3,
+			Backoff:         500 * time.Millisecond,
+		},
+	}
+}
+
+// Load layers the configuration sources in increasing precedence: Default,
+// the YAML file at path (skipped when path is empty), environment variables
+// and finally the secret files named by the *_FILE variables. The result is
+// validated before it is returned.
+func Load(path string) (Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookup func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
 	}
+
+	e := env{lookup: lookup}
+	e.string("APP_PROFILE", &cfg.Profile)
+	e.string("APP_ADDR", &cfg.Addr)
+	e.string("GRPC_ADDR", &cfg.GrpcAddr)
+	e.list("GAME_SERVER_SUBJECTS", &cfg.GameServerSubjects)
+	e.string("STORAGE", &cfg.Storage)
+	e.string("DB_USER", &cfg.Db.User)
+	e.string("DB_PASSWORD", &cfg.Db.Password)
+	e.address("DB_HOST", "DB_PORT", &cfg.Db.Address)
+	e.string("DB_NAME", &cfg.Db.Name)
+	e.string("DB_SSLMODE", &cfg.Db.SSLMode)
+	e.bool("DB_MIGRATE_ON_STARTUP", &cfg.Db.MigrateOnStartup)
+	e.string("AUTH_PROVIDER", &cfg.AuthProvider)
+	e.string("AUTH_BASE_URL", &cfg.Auth.BaseURL)
+	e.string("AUTH_CLIENT_ID", &cfg.Auth.ClientID)
+	e.string("AUTH_CLIENT_SECRET", &cfg.Auth.ClientSecret)
+	e.string("AUTH_REALM", &cfg.Auth.Realm)
+	e.list("ADMIN_SUBJECTS", &cfg.AdminSubjects)
+	e.duration("IDEMPOTENCY_TTL", &cfg.IdempotencyTTL)
+	e.duration("HEALTH_CHECK_TIMEOUT", &cfg.HealthCheckTimeout)
+	e.string("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	e.string("TRACING_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	e.string("TRACING_OTLP_ENDPOINT", &cfg.Tracing.OTLPEndpoint)
+	e.duration("PROJECTION_POLL_INTERVAL", &cfg.Projection.PollInterval)
+	e.int("PROJECTION_BATCH_SIZE", &cfg.Projection.BatchSize)
+	e.duration("PROJECTION_GAP_TIMEOUT", &cfg.Projection.GapTimeout)
+	e.bool("PROJECTION_REBUILD_ON_STARTUP", &cfg.Projection.RebuildOnStartup)
+	e.string("INVENTORY_PERSISTENCE", &cfg.Inventory.Persistence)
+	e.int("INVENTORY_SNAPSHOT_EVERY", &cfg.Inventory.SnapshotEvery)
+	e.duration("LEDGER_POLL_INTERVAL", &cfg.Ledger.PollInterval)
+	e.duration("LEDGER_RECONCILE_INTERVAL", &cfg.Ledger.ReconcileInterval)
+	e.duration("MODERATION_SWEEP_INTERVAL", &cfg.Moderation.SweepInterval)
+	e.duration("CLASSES_REFRESH_INTERVAL", &cfg.Classes.RefreshInterval)
+	e.list("NICKNAME_SCRIPTS", &cfg.Nickname.Scripts)
+	e.list("WALLET_PAYMENT_SUBJECTS", &cfg.Wallet.PaymentSubjects)
+	e.list("WALLET_DEBIT_SUBJECTS", &cfg.Wallet.DebitSubjects)
+	e.string("RATE_LIMIT_STORE", &cfg.RateLimit.Store)
+	e.limit("RATE_LIMIT_DEFAULT", &cfg.RateLimit.Default)
+	e.routeLimits("RATE_LIMIT_ROUTES", &cfg.RateLimit.Routes)
+	e.bool("CONSUMER_ENABLED", &cfg.Consumer.Enabled)
+	e.string("CONSUMER_BROKER", &cfg.Consumer.Broker)
+	e.string("CONSUMER_TOPIC", &cfg.Consumer.Topic)
+	e.string("CONSUMER_DEAD_LETTER_TOPIC", &cfg.Consumer.DeadLetterTopic)
+	e.int("CONSUMER_MAX_ATTEMPTS", &cfg.Consumer.MaxAttempts)
+	e.duration("CONSUMER_BACKOFF", &cfg.Consumer.Backoff)
+
+	e.secretFile("DB_PASSWORD_FILE", &cfg.Db.Password)
+	e.secretFile("AUTH_CLIENT_SECRET_FILE", &cfg.Auth.ClientSecret)
+
+	if err := errors.Join(e.errs...); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+func loadFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read config file: %w", ErrInvalidConfig, err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: cannot parse config file %s: %w", ErrInvalidConfig, path, err)
+	}
+	return nil
+}
+
+// Validate reports every problem found in the configuration at once.
+func (c Config) Validate() error {
+	var problems []error
+	invalid := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+	insecure := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]any{ErrInsecureConfig}, args...)...))
+	}
+
+	if c.Profile != ProfileDev && c.Profile != ProfileProd {
+		invalid("APP_PROFILE must be %q or %q, got %q", ProfileDev, ProfileProd, c.Profile)
+	}
+	if c.Addr == "" {
+		invalid("APP_ADDR is required")
+	}
+	if c.GrpcAddr == "" {
+		invalid("GRPC_ADDR is required")
+	}
+	switch c.Storage {
+	case StorageMySQL:
+	case StoragePostgres:
+		if !slices.Contains(postgresSSLModes, c.Db.SSLMode) {
+			invalid("DB_SSLMODE must be one of %v, got %q", postgresSSLModes, c.Db.SSLMode)
+		}
+		// the PostgreSQL adapter has no event log replay nor snapshots
+		if c.Inventory.Persistence == InventoryEvents {
+			invalid("INVENTORY_PERSISTENCE=%s requires STORAGE=%s", InventoryEvents, StorageMySQL)
+		}
+	case StorageMemory:
+		if c.Profile != ProfileDev {
+			invalid("STORAGE=%s is only allowed in the %s profile", StorageMemory, ProfileDev)
+		}
+	default:
+		invalid("STORAGE must be %q, %q or %q, got %q", StorageMySQL, StoragePostgres, StorageMemory, c.Storage)
+	}
+	if c.Db.User == "" {
+		invalid("DB_USER is required")
+	}
+	if c.Db.Address == "" {
+		invalid("DB_HOST and DB_PORT are required")
+	}
+	if c.Db.Name == "" {
+		invalid("DB_NAME is required")
+	}
+	switch c.AuthProvider {
+	case AuthKeycloak:
+		if u, err := url.Parse(c.Auth.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("AUTH_BASE_URL must be an absolute URL, got %q", c.Auth.BaseURL)
+		}
+		if c.Auth.ClientID == "" {
+			invalid("AUTH_CLIENT_ID is required")
+		}
+		if c.Auth.ClientSecret == "" {
+			invalid("AUTH_CLIENT_SECRET is required")
+		}
+		if c.Auth.Realm == "" {
+			invalid("AUTH_REALM is required")
+		}
+	case AuthFake:
+		if c.Profile != ProfileDev {
+			invalid("AUTH_PROVIDER=%s is only allowed in the %s profile", AuthFake, ProfileDev)
+		}
+	default:
+		invalid("AUTH_PROVIDER must be %q or %q, got %q", AuthKeycloak, AuthFake, c.AuthProvider)
+	}
+	if c.IdempotencyTTL <= 0 {
+		invalid("IDEMPOTENCY_TTL must be positive")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		invalid("HEALTH_CHECK_TIMEOUT must be positive")
+	}
+	if c.Projection.PollInterval <= 0 {
+		invalid("PROJECTION_POLL_INTERVAL must be positive")
+	}
+	if c.Projection.BatchSize <= 0 {
+		invalid("PROJECTION_BATCH_SIZE must be positive")
+	}
+	if c.Projection.GapTimeout < 0 {
+		invalid("PROJECTION_GAP_TIMEOUT must not be negative")
+	}
+	if c.Inventory.Persistence != InventoryState && c.Inventory.Persistence != InventoryEvents {
+		invalid("INVENTORY_PERSISTENCE must be %q or %q, got %q", InventoryState, InventoryEvents, c.Inventory.Persistence)
+	}
+	if c.Inventory.SnapshotEvery <= 0 {
+		invalid("INVENTORY_SNAPSHOT_EVERY must be positive")
+	}
+	if c.Ledger.PollInterval <= 0 {
+		invalid("LEDGER_POLL_INTERVAL must be positive")
+	}
+	if c.Ledger.ReconcileInterval <= 0 {
+		invalid("LEDGER_RECONCILE_INTERVAL must be positive")
+	}
+	if c.Moderation.SweepInterval <= 0 {
+		invalid("MODERATION_SWEEP_INTERVAL must be positive")
+	}
+	if c.Classes.RefreshInterval <= 0 {
+		invalid("CLASSES_REFRESH_INTERVAL must be positive")
+	}
+	if len(c.Nickname.Scripts) == 0 {
+		invalid("NICKNAME_SCRIPTS must list at least one script")
+	}
+	for _, script := range c.Nickname.Scripts {
+		if _, ok := unicode.Scripts[script]; !ok {
+			invalid("NICKNAME_SCRIPTS has unknown script %q", script)
+		}
+	}
+	if slices.Contains(c.GameServerSubjects, "") {
+		invalid("GAME_SERVER_SUBJECTS must not contain empty subjects")
+	}
+	if slices.Contains(c.AdminSubjects, "") {
+		invalid("ADMIN_SUBJECTS must not contain empty subjects")
+	}
+	if slices.Contains(c.Wallet.PaymentSubjects, "") {
+		invalid("WALLET_PAYMENT_SUBJECTS must not contain empty subjects")
+	}
+	if slices.Contains(c.Wallet.DebitSubjects, "") {
+		invalid("WALLET_DEBIT_SUBJECTS must not contain empty subjects")
+	}
+	switch c.RateLimit.Store {
+	case RateLimitMemory:
+	case RateLimitMySQL:
+		if c.Storage != StorageMySQL {
+			invalid("RATE_LIMIT_STORE=%s requires STORAGE=%s", RateLimitMySQL, StorageMySQL)
+		}
+	default:
+		invalid("RATE_LIMIT_STORE must be %q or %q, got %q", RateLimitMemory, RateLimitMySQL, c.RateLimit.Store)
+	}
+	if c.RateLimit.Default.Validate() != nil {
+		invalid("RATE_LIMIT_DEFAULT must allow a positive number of requests per positive period")
+	}
+	for route, limit := range c.RateLimit.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			invalid("RATE_LIMIT_ROUTES must be keyed by route patterns such as \"POST /character\", got %q", route)
+		}
+		if limit.Validate() != nil {
+			invalid("RATE_LIMIT_ROUTES limit of %q must allow a positive number of requests per positive period", route)
+		}
+	}
+	if c.Consumer.Enabled {
+		switch c.Consumer.Broker {
+		case BrokerMemory:
+			if c.Profile != ProfileDev {
+				invalid("CONSUMER_BROKER=%s is only allowed in the %s profile", BrokerMemory, ProfileDev)
+			}
+		default:
+			invalid("CONSUMER_BROKER must be %q, got %q", BrokerMemory, c.Consumer.Broker)
+		}
+		if c.Consumer.Topic == "" {
+			invalid("CONSUMER_TOPIC is required")
+		}
+		if c.Consumer.DeadLetterTopic == "" || c.Consumer.DeadLetterTopic == c.Consumer.Topic {
+			invalid("CONSUMER_DEAD_LETTER_TOPIC is required and must differ from CONSUMER_TOPIC")
+		}
+		if c.Consumer.MaxAttempts <= 0 {
+			invalid("CONSUMER_MAX_ATTEMPTS must be positive")
+		}
+		if c.Consumer.Backoff < 0 {
+			invalid("CONSUMER_BACKOFF must not be negative")
+		}
+	}
+	if !slices.Contains(tracingExporters, c.Tracing.Exporter) {
+		invalid("TRACING_EXPORTER must be one of %v, got %q", tracingExporters, c.Tracing.Exporter)
+	}
+
+	if c.Profile != ProfileDev && c.Db.Password == devDbPassword {
+		insecure("DB_PASSWORD must not use the development default outside the %s profile", ProfileDev)
+	}
+	if c.Profile != ProfileDev && c.Storage == StoragePostgres && c.Db.SSLMode == "disable" {
+		insecure("DB_SSLMODE=disable is only allowed in the %s profile", ProfileDev)
+	}
+
+	return errors.Join(problems...)
+}
+
+// env applies environment variables over the configuration, collecting the
+// malformed ones instead of silently falling back to the previous layer.
+type env struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+func (e *env) string(key string, dst *string) {
+	if value, ok := e.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (e *env) duration(key string, dst *time.Duration) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be a duration such as 30s or 24h, got %q", key, value))
+		return
+	}
+	*dst = d
+}
+
+func (e *env) int(key string, dst *int) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be an integer, got %q", key, value))
+		return
+	}
+	*dst = i
+}
+
+func (e *env) bool(key string, dst *bool) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+		return
+	}
+	*dst = b
+}
+
+// list reads a comma separated value. An empty value clears the list.
+func (e *env) list(key string, dst *[]string) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	var values []string
+	if strings.TrimSpace(value) != "" {
+		for _, v := range strings.Split(value, ",") {
+			values = append(values, strings.TrimSpace(v))
+		}
+	}
+	*dst = values
+}
+
+func (e *env) limit(key string, dst *ratelimit.Limit) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = limit
+}
+
+// routeLimits reads comma separated route=limit pairs, e.g.
+// "POST /character=10/1m". They override the limits of those routes only.
+func (e *env) routeLimits(key string, dst *map[string]ratelimit.Limit) {
+	value, ok := e.lookup(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return
+	}
+	limits := make(map[string]ratelimit.Limit, len(*dst))
+	for route, limit := range *dst {
+		limits[route] = limit
+	}
+	for _, pair := range strings.Split(value, ",") {
+		route, rule, ok := strings.Cut(pair, "=")
+		if !ok {
+			e.errs = append(e.errs, fmt.Errorf("%s must list route=limit pairs, got %q", key, pair))
+			continue
+		}
+		limit, err := ratelimit.ParseLimit(rule)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		limits[strings.TrimSpace(route)] = limit
+	}
+	*dst = limits
+}
+
+// address overrides only the half of the host:port pair that is set.
+func (e *env) address(hostKey, portKey string, dst *string) {
+	host, port, _ := strings.Cut(*dst, ":")
+	e.string(hostKey, &host)
+	e.string(portKey, &port)
+	*dst = fmt.Sprintf("%s:%s", host, port)
+}
+
+func (e *env) secretFile(key string, dst *string) {
+	path, ok := e.lookup(key)
+	if !ok || path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: cannot read secret file: %w", key, err))
+		return
 	}
-	return fallback
+	*dst = strings.TrimSpace(string(data))
 }
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/ratelimit"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	file := writeFile(t, "character.yaml", `
+addr: ":8000"
+grpcAddr: ":9000"
+db:
+  password: file-password
+auth:
+  realm: file-realm
+idempotencyTTL: 1h
+rateLimit:
+  default: 30/1m
+  routes:
+    "GET /wallet/{loginId}": 5/1s
+`)
+	secret := writeFile(t, "client-secret", "from-file\n")
+
+	cfg, err := load(file, lookupFrom(map[string]string{
+		"APP_ADDR":                ":8100",
+		"DB_HOST":                 "mysql",
+		"DB_MIGRATE_ON_STARTUP":   "true",
+		"PROJECTION_BATCH_SIZE":   "25",
+		"WALLET_PAYMENT_SUBJECTS": "payments, store",
+		"WALLET_DEBIT_SUBJECTS":   "shop",
+		"ADMIN_SUBJECTS":          "gm-1",
+		"GAME_SERVER_SUBJECTS":    "realm-1",
+		"RATE_LIMIT_ROUTES":       "POST /character=3/1m, POST /ledger/transactions=20/1m",
+		"AUTH_CLIENT_SECRET":      "from-env",
+		"AUTH_CLIENT_SECRET_FILE": secret,
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8100", cfg.Addr, "environment overrides the file")
+	assert.Equal(t, ":9000", cfg.GrpcAddr, "file overrides the defaults")
+	assert.Equal(t, "file-realm", cfg.Auth.Realm)
+	assert.Equal(t, time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, "mysql:3306", cfg.Db.Address, "only the host is overridden")
+	assert.True(t, cfg.Db.MigrateOnStartup)
+	assert.Equal(t, 25, cfg.Projection.BatchSize)
+	assert.Equal(t, []string{"payments", "store"}, cfg.Wallet.PaymentSubjects)
+	assert.Equal(t, []string{"shop"}, cfg.Wallet.DebitSubjects)
+	assert.Equal(t, []string{"gm-1"}, cfg.AdminSubjects)
+	assert.Equal(t, []string{"realm-1"}, cfg.GameServerSubjects)
+	assert.Equal(t, ratelimit.Limit{Requests: 30, Per: time.Minute}, cfg.RateLimit.Default)
+	assert.Equal(t, map[string]ratelimit.Limit{
+		"GET /wallet/{loginId}":     {Requests: 5, Per: time.Second},
+		"POST /character":           {Requests: 3, Per: time.Minute},
+		"POST /ledger/transactions": {Requests: 20, Per: time.Minute},
+	}, cfg.RateLimit.Routes, "the environment overrides single routes")
+	assert.Equal(t, 500*time.Millisecond, cfg.Projection.PollInterval, "defaults survive untouched")
+	assert.Equal(t, "from-file", cfg.Auth.ClientSecret, "secret files override the environment")
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		env         map[string]string
+		expectedErr error
+		contains    string
+	}{
+		{
+			name:        "missing client secret",
+			env:         map[string]string{"APP_PROFILE": ProfileDev},
+			expectedErr: ErrInvalidConfig,
+			contains:    "AUTH_CLIENT_SECRET is required",
+		},
+		{
+			name:        "default database password outside the dev profile",
+			env:         map[string]string{"AUTH_CLIENT_SECRET": "secret"},
+			expectedErr: ErrInsecureConfig,
+			contains:    "DB_PASSWORD",
+		},
+		{
+			name:        "malformed duration",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "IDEMPOTENCY_TTL": "one day"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "IDEMPOTENCY_TTL must be a duration",
+		},
+		{
+			name:        "malformed boolean",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "DB_MIGRATE_ON_STARTUP": "yes please"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "DB_MIGRATE_ON_STARTUP must be true or false",
+		},
+		{
+			name:        "malformed integer",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "PROJECTION_BATCH_SIZE": "many"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "PROJECTION_BATCH_SIZE must be an integer",
+		},
+		{
+			name:        "empty projection batch",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "PROJECTION_BATCH_SIZE": "0"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "PROJECTION_BATCH_SIZE must be positive",
+		},
+		{
+			name:        "unknown inventory persistence",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "INVENTORY_PERSISTENCE": "ledger"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "INVENTORY_PERSISTENCE must be",
+		},
+		{
+			name:        "disabled ledger reconciliation",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "LEDGER_RECONCILE_INTERVAL": "0s"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "LEDGER_RECONCILE_INTERVAL must be positive",
+		},
+		{
+			name:        "disabled suspension sweep",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "MODERATION_SWEEP_INTERVAL": "0s"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "MODERATION_SWEEP_INTERVAL must be positive",
+		},
+		{
+			name:        "disabled class catalog refresh",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "CLASSES_REFRESH_INTERVAL": "0s"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "CLASSES_REFRESH_INTERVAL must be positive",
+		},
+		{
+			name:        "unknown nickname script",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "NICKNAME_SCRIPTS": "Latin,Klingon"},
+			expectedErr: ErrInvalidConfig,
+			contains:    `NICKNAME_SCRIPTS has unknown script "Klingon"`,
+		},
+		{
+			name:        "no nickname script",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "NICKNAME_SCRIPTS": ""},
+			expectedErr: ErrInvalidConfig,
+			contains:    "NICKNAME_SCRIPTS must list at least one script",
+		},
+		{
+			name:        "empty payment subject",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "WALLET_PAYMENT_SUBJECTS": "payments,,store"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "WALLET_PAYMENT_SUBJECTS must not contain empty subjects",
+		},
+		{
+			name:        "malformed rate limit",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "RATE_LIMIT_DEFAULT": "lots"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "RATE_LIMIT_DEFAULT",
+		},
+		{
+			name:        "rate limit route without method",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "RATE_LIMIT_ROUTES": "/character=1/1m"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "RATE_LIMIT_ROUTES must be keyed by route patterns",
+		},
+		{
+			name:        "shared rate limits without a database",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "STORAGE": StorageMemory, "RATE_LIMIT_STORE": RateLimitMySQL},
+			expectedErr: ErrInvalidConfig,
+			contains:    "RATE_LIMIT_STORE=mysql requires STORAGE=mysql",
+		},
+		{
+			name:        "unknown storage",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "STORAGE": "redis"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "STORAGE must be",
+		},
+		{
+			name:        "unknown postgres ssl mode",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "STORAGE": StoragePostgres, "DB_SSLMODE": "prefer"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "DB_SSLMODE must be one of",
+		},
+		{
+			name:        "postgres storage with event-sourced inventories",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "STORAGE": StoragePostgres, "INVENTORY_PERSISTENCE": InventoryEvents},
+			expectedErr: ErrInvalidConfig,
+			contains:    "INVENTORY_PERSISTENCE=events requires STORAGE=mysql",
+		},
+		{
+			name:        "postgres without tls outside the dev profile",
+			env:         map[string]string{"AUTH_CLIENT_SECRET": "secret", "DB_PASSWORD": "strong", "STORAGE": StoragePostgres, "DB_SSLMODE": "disable"},
+			expectedErr: ErrInsecureConfig,
+			contains:    "DB_SSLMODE=disable",
+		},
+		{
+			name:        "memory storage outside the dev profile",
+			env:         map[string]string{"AUTH_CLIENT_SECRET": "secret", "DB_PASSWORD": "strong", "STORAGE": StorageMemory},
+			expectedErr: ErrInvalidConfig,
+			contains:    "only allowed in the dev profile",
+		},
+		{
+			name:        "consumer outside the dev profile",
+			env:         map[string]string{"AUTH_CLIENT_SECRET": "secret", "DB_PASSWORD": "strong", "CONSUMER_ENABLED": "true"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "CONSUMER_BROKER=memory is only allowed in the dev profile",
+		},
+		{
+			name:        "consumer dead letters to its own topic",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "CONSUMER_ENABLED": "true", "CONSUMER_DEAD_LETTER_TOPIC": "character.inventory.commands"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "CONSUMER_DEAD_LETTER_TOPIC",
+		},
+		{
+			name:        "relative keycloak url",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "AUTH_BASE_URL": "keycloak:7080"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "AUTH_BASE_URL",
+		},
+		{
+			name:        "fake identity provider outside the dev profile",
+			env:         map[string]string{"AUTH_CLIENT_SECRET": "secret", "DB_PASSWORD": "strong", "AUTH_PROVIDER": AuthFake},
+			expectedErr: ErrInvalidConfig,
+			contains:    "AUTH_PROVIDER=fake is only allowed in the dev profile",
+		},
+		{
+			name:        "unknown identity provider",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "AUTH_PROVIDER": "okta"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "AUTH_PROVIDER must be",
+		},
+		{
+			name:        "unknown tracing exporter",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "TRACING_EXPORTER": "zipkin"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "TRACING_EXPORTER",
+		},
+		{
+			name:        "unknown field in file",
+			file:        "idempotencyTtl: 1h\n",
+			env:         map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret"},
+			expectedErr: ErrInvalidConfig,
+			contains:    "idempotencyTtl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.file != "" {
+				path = writeFile(t, "character.yaml", tt.file)
+			}
+
+			_, err := load(path, lookupFrom(tt.env))
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.ErrorContains(t, err, tt.contains)
+		})
+	}
+}
+
+func TestLoadDevProfileAcceptsDefaults(t *testing.T) {
+	cfg, err := load("", lookupFrom(map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default().Db.Password, cfg.Db.Password)
+}
+
+func TestLoadFakeIdentityProviderNeedsNoKeycloakSettings(t *testing.T) {
+	cfg, err := load("", lookupFrom(map[string]string{"APP_PROFILE": ProfileDev, "STORAGE": StorageMemory, "AUTH_PROVIDER": AuthFake, "AUTH_BASE_URL": ""}))
+
+	require.NoError(t, err)
+	assert.Equal(t, AuthFake, cfg.AuthProvider)
+}
+
+func TestLoadPostgresStorage(t *testing.T) {
+	cfg, err := load("", lookupFrom(map[string]string{"APP_PROFILE": ProfileDev, "AUTH_CLIENT_SECRET": "secret", "STORAGE": StoragePostgres, "DB_SSLMODE": "disable"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, StoragePostgres, cfg.Storage)
+	assert.Equal(t, "disable", cfg.Db.SSLMode)
+}
@@ -1,60 +1,173 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 
-	mysqlCfg "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/joho/godotenv"
 	"github.com/vterry/ddd-study/character/internal/infra/config"
 	"github.com/vterry/ddd-study/character/internal/infra/db"
 )
 
+const usage = `Uso: migrate <comando> [argumento]
+
+Comandos:
+  up          aplica todas as migrations pendentes
+  down        reverte todas as migrations
+  status      lista as migrations e quais já foram aplicadas
+  version     mostra a versão atual do schema
+  steps N     aplica N migrations (ou reverte, se N for negativo)
+  goto V      migra para a versão V
+  force V     marca a versão V sem executar SQL (use -1 para nenhuma versão)`
+
 func main() {
-	db, err := db.NewMySQLStorage(mysqlCfg.Config{
-		User:                 config.Envs.Db.User,
-		Passwd:               config.Envs.Db.Password,
-		Addr:                 config.Envs.Db.Address,
-		DBName:               config.Envs.Db.Name,
-		Net:                  "tcp",
-		AllowNativePasswords: true,
-		ParseTime:            true,
-	})
+	_ = godotenv.Load()
 
-	if err != nil {
-		log.Fatal(err)
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
 	}
 
-	driver, err := mysql.WithInstance(db, &mysql.Config{})
-
-	if err != nil {
+	if err := run(os.Args[1], os.Args[2:]); err != nil {
 		log.Fatal(err)
 	}
+}
 
-	m, err := migrate.NewWithDatabaseInstance("file://internal/infra/db/migrate/migrations", "mysql", driver)
+func run(cmd string, args []string) error {
+	// a duplicated or mismatched version is reported before touching the database
+	migrations, err := db.Migrations()
+	if err != nil {
+		return err
+	}
 
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	if len(os.Args) < 2 {
-		log.Fatal("Comando de migration não especificado. Use 'up', 'down' ou 'force [version]'.")
+	m, err := db.NewMigrator(db.MySQLConfig(cfg.Db))
+	if err != nil {
+		return err
 	}
-	cmd := os.Args[1]
+	defer m.Close()
 
-	if cmd == "up" {
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+	switch cmd {
+	case "up":
+		if err := ignoreNoChange(m.Up()); err != nil {
+			return err
 		}
 		log.Println("Migrations aplicadas com sucesso.")
-	}
 
-	if cmd == "down" {
-		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+	case "down":
+		if err := ignoreNoChange(m.Down()); err != nil {
+			return err
 		}
 		log.Println("Migrations revertidas com sucesso.")
+
+	case "status":
+		return status(m, migrations)
+
+	case "version":
+		version, dirty, err := currentVersion(m)
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatVersion(version, dirty))
+
+	case "steps":
+		n, err := intArg(args, "steps")
+		if err != nil {
+			return err
+		}
+		if err := ignoreNoChange(m.Steps(n)); err != nil {
+			return err
+		}
+		log.Printf("%d migration(s) executada(s) com sucesso.", n)
+
+	case "goto":
+		v, err := intArg(args, "goto")
+		if err != nil {
+			return err
+		}
+		if v < 0 {
+			return fmt.Errorf("versão inválida: %d", v)
+		}
+		if err := ignoreNoChange(m.Migrate(uint(v))); err != nil {
+			return err
+		}
+		log.Printf("Schema migrado para a versão %d.", v)
+
+	case "force":
+		v, err := intArg(args, "force")
+		if err != nil {
+			return err
+		}
+		if err := m.Force(v); err != nil {
+			return err
+		}
+		log.Printf("Versão forçada para %d.", v)
+
+	default:
+		return fmt.Errorf("comando desconhecido %q\n\n%s", cmd, usage)
+	}
+
+	return nil
+}
+
+func status(m *migrate.Migrate, migrations []db.Migration) error {
+	version, dirty, err := currentVersion(m)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Versão atual: %s\n\n", formatVersion(version, dirty))
+	for _, migration := range migrations {
+		mark := " "
+		if migration.Version <= version {
+			mark = "x"
+		}
+		fmt.Printf("[%s] %02d %s\n", mark, migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// currentVersion reports version 0 when no migration was applied yet.
+func currentVersion(m *migrate.Migrate) (uint, bool, error) {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+func formatVersion(version uint, dirty bool) string {
+	switch {
+	case version == 0:
+		return "nenhuma"
+	case dirty:
+		return fmt.Sprintf("%d (dirty)", version)
+	default:
+		return strconv.FormatUint(uint64(version), 10)
+	}
+}
+
+func intArg(args []string, cmd string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("o comando %s exige um argumento numérico\n\n%s", cmd, usage)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("argumento inválido para %s: %q", cmd, args[0])
+	}
+	return n, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
 	}
+	return err
 }
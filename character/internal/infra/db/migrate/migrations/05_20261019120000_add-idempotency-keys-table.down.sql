@@ -0,0 +1 @@
+DROP TABLE IF EXISTS IDEMPOTENCY_KEYS;
\ No newline at end of file
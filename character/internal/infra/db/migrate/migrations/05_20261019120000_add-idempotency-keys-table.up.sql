@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS IDEMPOTENCY_KEYS (
+    `ID` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `IDEMPOTENCY_KEY` VARCHAR(255) NOT NULL,
+    `FINGERPRINT` CHAR(64) NOT NULL,
+    `STATUS_CODE` SMALLINT UNSIGNED NOT NULL,
+    `CONTENT_TYPE` VARCHAR(255) NULL,
+    `RESPONSE_BODY` BLOB NULL,
+    `COMPLETED` BOOLEAN NOT NULL DEFAULT FALSE,
+    `EXPIRES_AT` DATETIME NOT NULL,
+
+    PRIMARY KEY(ID),
+    UNIQUE KEY(IDEMPOTENCY_KEY),
+    INDEX IDX_IDEMPOTENCY_KEYS_EXPIRES_AT (EXPIRES_AT)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS PROCESSED_MESSAGES;
\ No newline at end of file
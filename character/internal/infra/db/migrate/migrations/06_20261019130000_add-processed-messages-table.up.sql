@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS PROCESSED_MESSAGES (
+    `ID` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `MESSAGE_ID` VARCHAR(255) NOT NULL,
+    `PROCESSED_AT` DATETIME NOT NULL,
+
+    PRIMARY KEY(ID),
+    UNIQUE KEY(MESSAGE_ID)
+);
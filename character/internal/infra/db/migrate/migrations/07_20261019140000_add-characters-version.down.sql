@@ -0,0 +1 @@
+ALTER TABLE CHARACTERS DROP COLUMN `VERSION`;
@@ -0,0 +1 @@
+ALTER TABLE CHARACTERS ADD COLUMN `VERSION` INT UNSIGNED NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS CHARACTER_EVENTS;
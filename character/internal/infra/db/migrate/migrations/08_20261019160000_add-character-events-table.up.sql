@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_EVENTS (
+    `POSITION` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `CHARACTER_ID` VARCHAR(255) NOT NULL,
+    `EVENT_TYPE` VARCHAR(64) NOT NULL,
+    `PAYLOAD` JSON NOT NULL,
+    `OCCURRED_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(POSITION),
+    INDEX IDX_CHARACTER_EVENTS_CHARACTER (CHARACTER_ID, POSITION)
+);
+
+-- Characters created before events were recorded get a history that rebuilds
+-- their current state, so the read model can be projected for them too.
+INSERT INTO CHARACTER_EVENTS (CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT)
+SELECT c.CHARACTER_ID, 'CharacterCreated',
+    JSON_OBJECT('loginId', c.LOGIN_ID, 'nickname', c.NICKNAME, 'class', c.CLASS, 'inventoryId', c.INVENTORY_ID, 'guildId', c.GUILD_ID, 'vaultId', c.VAULT_ID),
+    UTC_TIMESTAMP(6)
+FROM CHARACTERS c
+ORDER BY c.ID;
+
+INSERT INTO CHARACTER_EVENTS (CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT)
+SELECT c.CHARACTER_ID, 'GoldAdded', JSON_OBJECT('amount', i.GOLD_AMOUNT), UTC_TIMESTAMP(6)
+FROM CHARACTERS c
+JOIN INVENTORIES i ON i.INVENTORY_ID = c.INVENTORY_ID
+WHERE i.GOLD_AMOUNT > 0
+ORDER BY c.ID;
+
+INSERT INTO CHARACTER_EVENTS (CHARACTER_ID, EVENT_TYPE, PAYLOAD, OCCURRED_AT)
+SELECT c.CHARACTER_ID, 'ItemAdded',
+    JSON_OBJECT('playerItemId', pi.PLAYER_ITEM_ID, 'itemId', pi.ITEM_ID, 'description', pi.DESCRIPTION, 'quantity', pi.QUANTITY),
+    UTC_TIMESTAMP(6)
+FROM CHARACTERS c
+JOIN PLAYER_ITEMS pi ON pi.INVENTORY_ID = c.INVENTORY_ID
+ORDER BY c.ID, pi.ID;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS PROJECTION_CHECKPOINTS;
+DROP TABLE IF EXISTS CHARACTER_ITEM_VIEWS;
+DROP TABLE IF EXISTS CHARACTER_VIEWS;
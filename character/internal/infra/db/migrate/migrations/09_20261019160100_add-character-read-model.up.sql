@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_VIEWS (
+    `CHARACTER_ID` VARCHAR(255) NOT NULL,
+    `LOGIN_ID` VARCHAR(255) NOT NULL,
+    `NICKNAME` VARCHAR(255) NOT NULL,
+    `CLASS` VARCHAR(32) NOT NULL,
+    `INVENTORY_ID` VARCHAR(255) NOT NULL,
+    `GUILD_ID` VARCHAR(255) NOT NULL,
+    `VAULT_ID` VARCHAR(255) NOT NULL,
+    `GOLD_AMOUNT` INT NOT NULL,
+    `POSITION` BIGINT UNSIGNED NOT NULL,
+
+    PRIMARY KEY(CHARACTER_ID)
+);
+
+CREATE TABLE IF NOT EXISTS CHARACTER_ITEM_VIEWS (
+    `CHARACTER_ID` VARCHAR(255) NOT NULL,
+    `PLAYER_ITEM_ID` VARCHAR(255) NOT NULL,
+    `ITEM_ID` VARCHAR(255) NOT NULL,
+    `DESCRIPTION` VARCHAR(255) NOT NULL,
+    `QUANTITY` INT NOT NULL,
+
+    PRIMARY KEY(CHARACTER_ID, PLAYER_ITEM_ID)
+);
+
+CREATE TABLE IF NOT EXISTS PROJECTION_CHECKPOINTS (
+    `PROJECTION` VARCHAR(64) NOT NULL,
+    `POSITION` BIGINT UNSIGNED NOT NULL,
+
+    PRIMARY KEY(PROJECTION)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS INVENTORY_SNAPSHOTS;
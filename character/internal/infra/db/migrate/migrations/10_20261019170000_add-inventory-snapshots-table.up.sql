@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS INVENTORY_SNAPSHOTS (
+    `CHARACTER_ID` VARCHAR(255) NOT NULL,
+    `POSITION` BIGINT UNSIGNED NOT NULL,
+    `GOLD_AMOUNT` INT NOT NULL,
+    `ITEMS` JSON NOT NULL,
+    `CREATED_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(CHARACTER_ID, POSITION)
+);
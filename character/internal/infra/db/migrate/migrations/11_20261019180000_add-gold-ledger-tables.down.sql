@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS LEDGER_BALANCES;
+DROP TABLE IF EXISTS LEDGER_ENTRIES;
+DROP TABLE IF EXISTS LEDGER_TRANSACTIONS;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS LEDGER_TRANSACTIONS (
+    `TRANSACTION_ID` VARCHAR(255) NOT NULL,
+    `REASON` VARCHAR(255) NOT NULL,
+    `OCCURRED_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(TRANSACTION_ID)
+);
+
+CREATE TABLE IF NOT EXISTS LEDGER_ENTRIES (
+    `TRANSACTION_ID` VARCHAR(255) NOT NULL,
+    `ACCOUNT_ID` VARCHAR(255) NOT NULL,
+    `AMOUNT` INT NOT NULL,
+
+    PRIMARY KEY(TRANSACTION_ID, ACCOUNT_ID),
+    INDEX IDX_LEDGER_ENTRIES_ACCOUNT (ACCOUNT_ID)
+);
+
+CREATE TABLE IF NOT EXISTS LEDGER_BALANCES (
+    `ACCOUNT_ID` VARCHAR(255) NOT NULL,
+    `ACCOUNT_KIND` VARCHAR(32) NOT NULL,
+    `BALANCE` BIGINT NOT NULL,
+
+    PRIMARY KEY(ACCOUNT_ID),
+    INDEX IDX_LEDGER_BALANCES_KIND (ACCOUNT_KIND)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS WALLET_MOVEMENTS;
+DROP TABLE IF EXISTS WALLETS;
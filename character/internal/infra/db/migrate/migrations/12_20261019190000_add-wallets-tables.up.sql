@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS WALLETS (
+    `LOGIN_ID` VARCHAR(255) NOT NULL,
+    `BALANCE` INT NOT NULL,
+    `VERSION` INT NOT NULL,
+
+    PRIMARY KEY(LOGIN_ID),
+    CONSTRAINT CHK_WALLETS_BALANCE CHECK (BALANCE >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS WALLET_MOVEMENTS (
+    `LOGIN_ID` VARCHAR(255) NOT NULL,
+    `REFERENCE` VARCHAR(255) NOT NULL,
+    `AMOUNT` INT NOT NULL,
+    `SOURCE` VARCHAR(255) NOT NULL,
+    `CREATED_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(LOGIN_ID, REFERENCE)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS RATE_LIMIT_BUCKETS;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS RATE_LIMIT_BUCKETS (
+    `BUCKET_KEY` VARCHAR(512) NOT NULL,
+    `TOKENS` DOUBLE NOT NULL,
+    `UPDATED_AT` DATETIME(6) NULL,
+    `EXPIRES_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(BUCKET_KEY),
+    INDEX IDX_RATE_LIMIT_BUCKETS_EXPIRES_AT (EXPIRES_AT)
+);
@@ -0,0 +1,6 @@
+DROP INDEX IDX_CHARACTER_VIEWS_GOLD ON CHARACTER_VIEWS;
+DROP INDEX IDX_CHARACTER_VIEWS_GUILD_NICKNAME ON CHARACTER_VIEWS;
+DROP INDEX IDX_CHARACTER_VIEWS_CLASS_NICKNAME ON CHARACTER_VIEWS;
+DROP INDEX IDX_CHARACTER_VIEWS_NICKNAME ON CHARACTER_VIEWS;
+
+ALTER TABLE CHARACTER_VIEWS DROP COLUMN `NICKNAME_KEY`;
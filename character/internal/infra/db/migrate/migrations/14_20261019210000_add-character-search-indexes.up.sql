@@ -0,0 +1,7 @@
+ALTER TABLE CHARACTER_VIEWS
+    ADD COLUMN `NICKNAME_KEY` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin GENERATED ALWAYS AS (LOWER(NICKNAME)) STORED;
+
+CREATE INDEX IDX_CHARACTER_VIEWS_NICKNAME ON CHARACTER_VIEWS (NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IDX_CHARACTER_VIEWS_CLASS_NICKNAME ON CHARACTER_VIEWS (CLASS, NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IDX_CHARACTER_VIEWS_GUILD_NICKNAME ON CHARACTER_VIEWS (GUILD_ID, NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IDX_CHARACTER_VIEWS_GOLD ON CHARACTER_VIEWS (GOLD_AMOUNT, CHARACTER_ID);
@@ -0,0 +1,6 @@
+ALTER TABLE CHARACTERS
+    ADD COLUMN `STATUS` VARCHAR(16) NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN `SUSPENDED_UNTIL` DATETIME(6) NULL,
+    ADD COLUMN `STATUS_REASON` VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IDX_CHARACTERS_SUSPENDED_UNTIL ON CHARACTERS (STATUS, SUSPENDED_UNTIL);
@@ -0,0 +1,4 @@
+-- fails while a character has a class added through the catalog
+ALTER TABLE CHARACTERS MODIFY COLUMN `CLASS` ENUM('MAGE', 'WARRIOR', 'RANGER') NOT NULL;
+
+DROP TABLE IF EXISTS CHARACTER_CLASSES;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_CLASSES (
+    `CLASS_ID` VARCHAR(32) NOT NULL,
+    `DISPLAY_NAME` VARCHAR(255) NOT NULL,
+    `STARTING_ITEMS` JSON NOT NULL,
+    `BASE_STATS` JSON NOT NULL,
+    `UPDATED_AT` DATETIME(6) NOT NULL,
+
+    PRIMARY KEY(CLASS_ID)
+);
+
+INSERT INTO CHARACTER_CLASSES (CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS, UPDATED_AT) VALUES
+    ('MAGE', 'Mage', JSON_ARRAY(), JSON_OBJECT('strength', 2, 'agility', 3, 'intellect', 8, 'health', 80), UTC_TIMESTAMP(6)),
+    ('WARRIOR', 'Warrior', JSON_ARRAY(), JSON_OBJECT('strength', 8, 'agility', 4, 'intellect', 2, 'health', 120), UTC_TIMESTAMP(6)),
+    ('RANGER', 'Ranger', JSON_ARRAY(), JSON_OBJECT('strength', 4, 'agility', 8, 'intellect', 3, 'health', 100), UTC_TIMESTAMP(6));
+
+-- classes now come from the catalog, so the column takes any class id
+ALTER TABLE CHARACTERS MODIFY COLUMN `CLASS` VARCHAR(32) NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX UQ_CHARACTERS_NICKNAME_SKELETON ON CHARACTERS;
+
+ALTER TABLE CHARACTERS DROP COLUMN `NICKNAME_SKELETON`;
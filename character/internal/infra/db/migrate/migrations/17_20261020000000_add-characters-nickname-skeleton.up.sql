@@ -0,0 +1,17 @@
+-- binary collation, so only the skeleton decides which nicknames collide
+ALTER TABLE CHARACTERS
+    ADD COLUMN `NICKNAME_SKELETON` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NULL AFTER `NICKNAME`;
+
+-- nicknames were ASCII letters and digits so far, whose skeleton is the
+-- folded case with the confusable digits replaced
+UPDATE CHARACTERS
+    SET NICKNAME_SKELETON = REPLACE(REPLACE(LOWER(NICKNAME), '1', 'l'), '0', 'o');
+
+-- the character inserted first, with the lowest ID, keeps a nickname
+-- already shared; the others keep theirs without reserving it
+UPDATE CHARACTERS c
+    JOIN (SELECT NICKNAME_SKELETON, MIN(ID) AS KEPT_ID FROM CHARACTERS GROUP BY NICKNAME_SKELETON) k
+        ON c.NICKNAME_SKELETON = k.NICKNAME_SKELETON AND c.ID <> k.KEPT_ID
+    SET c.NICKNAME_SKELETON = NULL;
+
+CREATE UNIQUE INDEX UQ_CHARACTERS_NICKNAME_SKELETON ON CHARACTERS (NICKNAME_SKELETON);
@@ -0,0 +1,5 @@
+DROP INDEX IDX_CHARACTER_VIEWS_LEVEL_NICKNAME ON CHARACTER_VIEWS;
+
+ALTER TABLE CHARACTER_VIEWS DROP COLUMN `LEVEL`;
+
+ALTER TABLE CHARACTERS DROP COLUMN `LEVEL`;
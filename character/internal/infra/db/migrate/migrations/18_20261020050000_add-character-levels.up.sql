@@ -0,0 +1,5 @@
+ALTER TABLE CHARACTERS ADD COLUMN `LEVEL` INT NOT NULL DEFAULT 1 AFTER `VAULT_ID`;
+
+ALTER TABLE CHARACTER_VIEWS ADD COLUMN `LEVEL` INT NOT NULL DEFAULT 1 AFTER `VAULT_ID`;
+
+CREATE INDEX IDX_CHARACTER_VIEWS_LEVEL_NICKNAME ON CHARACTER_VIEWS (LEVEL, NICKNAME_KEY, CHARACTER_ID);
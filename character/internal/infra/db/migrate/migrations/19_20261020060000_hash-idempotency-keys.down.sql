@@ -0,0 +1,2 @@
+-- the hashed keys are kept; they expire like any other
+ALTER TABLE IDEMPOTENCY_KEYS MODIFY `IDEMPOTENCY_KEY` VARCHAR(255) NOT NULL;
@@ -0,0 +1,5 @@
+-- the scoped keys include the request path, which has no length limit, so
+-- only their SHA-256 is stored
+UPDATE IDEMPOTENCY_KEYS SET IDEMPOTENCY_KEY = SHA2(IDEMPOTENCY_KEY, 256);
+
+ALTER TABLE IDEMPOTENCY_KEYS MODIFY `IDEMPOTENCY_KEY` CHAR(64) NOT NULL;
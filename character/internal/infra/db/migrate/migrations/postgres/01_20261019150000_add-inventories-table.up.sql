@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS INVENTORIES (
+    ID SERIAL PRIMARY KEY,
+    INVENTORY_ID UUID NOT NULL UNIQUE,
+    GOLD_AMOUNT INTEGER NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS CHARACTERS (
+    ID SERIAL PRIMARY KEY,
+    CHARACTER_ID UUID NOT NULL UNIQUE,
+    LOGIN_ID UUID NOT NULL UNIQUE,
+    NICKNAME VARCHAR(255) NOT NULL,
+    CLASS VARCHAR(16) NOT NULL CHECK (CLASS IN ('MAGE', 'WARRIOR', 'RANGER')),
+    INVENTORY_ID UUID NOT NULL REFERENCES INVENTORIES(INVENTORY_ID),
+    GUILD_ID UUID NOT NULL,
+    VAULT_ID UUID NOT NULL,
+    VERSION INTEGER NOT NULL DEFAULT 0
+);
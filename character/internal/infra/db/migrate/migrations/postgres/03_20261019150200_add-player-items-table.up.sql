@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS PLAYER_ITEMS (
+    ID SERIAL PRIMARY KEY,
+    PLAYER_ITEM_ID UUID NOT NULL UNIQUE,
+    ITEM_ID UUID NOT NULL,
+    DESCRIPTION VARCHAR(255) NOT NULL,
+    QUANTITY INTEGER NOT NULL,
+    INVENTORY_ID UUID NOT NULL REFERENCES INVENTORIES(INVENTORY_ID) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS IDX_PLAYER_ITEMS_INVENTORY_ID ON PLAYER_ITEMS (INVENTORY_ID);
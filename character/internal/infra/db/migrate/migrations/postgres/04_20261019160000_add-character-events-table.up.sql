@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_EVENTS (
+    POSITION BIGSERIAL PRIMARY KEY,
+    CHARACTER_ID UUID NOT NULL,
+    EVENT_TYPE VARCHAR(64) NOT NULL,
+    PAYLOAD JSONB NOT NULL,
+    OCCURRED_AT TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_EVENTS_CHARACTER ON CHARACTER_EVENTS (CHARACTER_ID, POSITION);
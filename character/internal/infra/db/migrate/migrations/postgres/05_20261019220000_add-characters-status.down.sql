@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS IDX_CHARACTERS_SUSPENDED_UNTIL;
+
+ALTER TABLE CHARACTERS
+    DROP COLUMN STATUS_REASON,
+    DROP COLUMN SUSPENDED_UNTIL,
+    DROP COLUMN STATUS;
@@ -0,0 +1,6 @@
+ALTER TABLE CHARACTERS
+    ADD COLUMN STATUS VARCHAR(16) NOT NULL DEFAULT 'ACTIVE' CHECK (STATUS IN ('ACTIVE', 'SUSPENDED', 'BANNED', 'DELETED')),
+    ADD COLUMN SUSPENDED_UNTIL TIMESTAMPTZ NULL,
+    ADD COLUMN STATUS_REASON VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS IDX_CHARACTERS_SUSPENDED_UNTIL ON CHARACTERS (STATUS, SUSPENDED_UNTIL);
@@ -0,0 +1,3 @@
+-- fails while a character has a class added through the catalog
+ALTER TABLE CHARACTERS ALTER COLUMN CLASS TYPE VARCHAR(16);
+ALTER TABLE CHARACTERS ADD CONSTRAINT characters_class_check CHECK (CLASS IN ('MAGE', 'WARRIOR', 'RANGER'));
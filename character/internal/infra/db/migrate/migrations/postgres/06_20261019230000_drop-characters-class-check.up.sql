@@ -0,0 +1,3 @@
+-- classes now come from the catalog, so the column takes any class id
+ALTER TABLE CHARACTERS DROP CONSTRAINT IF EXISTS characters_class_check;
+ALTER TABLE CHARACTERS ALTER COLUMN CLASS TYPE VARCHAR(32);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS UQ_CHARACTERS_NICKNAME_SKELETON;
+
+ALTER TABLE CHARACTERS DROP COLUMN IF EXISTS NICKNAME_SKELETON;
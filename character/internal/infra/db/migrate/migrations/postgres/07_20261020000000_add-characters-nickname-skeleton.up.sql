@@ -0,0 +1,15 @@
+ALTER TABLE CHARACTERS ADD COLUMN NICKNAME_SKELETON VARCHAR(255) NULL;
+
+-- nicknames were ASCII letters and digits so far, whose skeleton is the
+-- folded case with the confusable digits replaced
+UPDATE CHARACTERS
+    SET NICKNAME_SKELETON = TRANSLATE(LOWER(NICKNAME), '10', 'lo');
+
+-- the character inserted first, with the lowest ID, keeps a nickname
+-- already shared; the others keep theirs without reserving it
+UPDATE CHARACTERS c
+    SET NICKNAME_SKELETON = NULL
+    FROM (SELECT NICKNAME_SKELETON, MIN(ID) AS KEPT_ID FROM CHARACTERS GROUP BY NICKNAME_SKELETON) k
+    WHERE c.NICKNAME_SKELETON = k.NICKNAME_SKELETON AND c.ID <> k.KEPT_ID;
+
+CREATE UNIQUE INDEX IF NOT EXISTS UQ_CHARACTERS_NICKNAME_SKELETON ON CHARACTERS (NICKNAME_SKELETON);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS PROCESSED_MESSAGES (
+    MESSAGE_ID VARCHAR(255) PRIMARY KEY,
+    PROCESSED_AT TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_VIEWS (
+    CHARACTER_ID UUID PRIMARY KEY,
+    LOGIN_ID UUID NOT NULL,
+    NICKNAME VARCHAR(255) NOT NULL,
+    -- compared byte by byte, like the nicknames of the other adapters
+    NICKNAME_KEY VARCHAR(255) COLLATE "C" GENERATED ALWAYS AS (LOWER(NICKNAME)) STORED,
+    CLASS VARCHAR(32) NOT NULL,
+    INVENTORY_ID UUID NOT NULL,
+    GUILD_ID UUID NOT NULL,
+    VAULT_ID UUID NOT NULL,
+    GOLD_AMOUNT INTEGER NOT NULL,
+    POSITION BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_VIEWS_NICKNAME ON CHARACTER_VIEWS (NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_VIEWS_CLASS_NICKNAME ON CHARACTER_VIEWS (CLASS, NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_VIEWS_GUILD_NICKNAME ON CHARACTER_VIEWS (GUILD_ID, NICKNAME_KEY, CHARACTER_ID);
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_VIEWS_GOLD ON CHARACTER_VIEWS (GOLD_AMOUNT, CHARACTER_ID);
+
+CREATE TABLE IF NOT EXISTS CHARACTER_ITEM_VIEWS (
+    CHARACTER_ID UUID NOT NULL,
+    PLAYER_ITEM_ID UUID NOT NULL,
+    ITEM_ID UUID NOT NULL,
+    DESCRIPTION VARCHAR(255) NOT NULL,
+    QUANTITY INTEGER NOT NULL,
+    PRIMARY KEY (CHARACTER_ID, PLAYER_ITEM_ID)
+);
+
+CREATE TABLE IF NOT EXISTS PROJECTION_CHECKPOINTS (
+    PROJECTION VARCHAR(64) PRIMARY KEY,
+    POSITION BIGINT NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS IDEMPOTENCY_KEYS (
+    ID SERIAL PRIMARY KEY,
+    IDEMPOTENCY_KEY VARCHAR(255) NOT NULL UNIQUE,
+    FINGERPRINT CHAR(64) NOT NULL,
+    STATUS_CODE SMALLINT NOT NULL,
+    CONTENT_TYPE VARCHAR(255) NULL,
+    RESPONSE_BODY BYTEA NULL,
+    COMPLETED BOOLEAN NOT NULL DEFAULT FALSE,
+    EXPIRES_AT TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_IDEMPOTENCY_KEYS_EXPIRES_AT ON IDEMPOTENCY_KEYS (EXPIRES_AT);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS LEDGER_TRANSACTIONS (
+    TRANSACTION_ID VARCHAR(255) PRIMARY KEY,
+    REASON VARCHAR(255) NOT NULL,
+    OCCURRED_AT TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS LEDGER_ENTRIES (
+    TRANSACTION_ID VARCHAR(255) NOT NULL,
+    ACCOUNT_ID VARCHAR(255) NOT NULL,
+    AMOUNT INTEGER NOT NULL,
+    PRIMARY KEY (TRANSACTION_ID, ACCOUNT_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_LEDGER_ENTRIES_ACCOUNT ON LEDGER_ENTRIES (ACCOUNT_ID);
+
+CREATE TABLE IF NOT EXISTS LEDGER_BALANCES (
+    ACCOUNT_ID VARCHAR(255) PRIMARY KEY,
+    ACCOUNT_KIND VARCHAR(32) NOT NULL,
+    BALANCE BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_LEDGER_BALANCES_KIND ON LEDGER_BALANCES (ACCOUNT_KIND);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS WALLETS (
+    LOGIN_ID UUID PRIMARY KEY,
+    BALANCE INTEGER NOT NULL CHECK (BALANCE >= 0),
+    VERSION INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS WALLET_MOVEMENTS (
+    LOGIN_ID UUID NOT NULL,
+    REFERENCE VARCHAR(255) NOT NULL,
+    AMOUNT INTEGER NOT NULL,
+    SOURCE VARCHAR(255) NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (LOGIN_ID, REFERENCE)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS CHARACTER_CLASSES (
+    CLASS_ID VARCHAR(32) PRIMARY KEY,
+    DISPLAY_NAME VARCHAR(255) NOT NULL,
+    STARTING_ITEMS JSONB NOT NULL,
+    BASE_STATS JSONB NOT NULL,
+    UPDATED_AT TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO CHARACTER_CLASSES (CLASS_ID, DISPLAY_NAME, STARTING_ITEMS, BASE_STATS, UPDATED_AT) VALUES
+    ('MAGE', 'Mage', '[]', jsonb_build_object('strength', 2, 'agility', 3, 'intellect', 8, 'health', 80), NOW()),
+    ('WARRIOR', 'Warrior', '[]', jsonb_build_object('strength', 8, 'agility', 4, 'intellect', 2, 'health', 120), NOW()),
+    ('RANGER', 'Ranger', '[]', jsonb_build_object('strength', 4, 'agility', 8, 'intellect', 3, 'health', 100), NOW())
+ON CONFLICT (CLASS_ID) DO NOTHING;
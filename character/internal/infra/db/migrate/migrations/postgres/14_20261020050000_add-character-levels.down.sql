@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS IDX_CHARACTER_VIEWS_LEVEL_NICKNAME;
+
+ALTER TABLE CHARACTER_VIEWS DROP COLUMN IF EXISTS LEVEL;
+
+ALTER TABLE CHARACTERS DROP COLUMN IF EXISTS LEVEL;
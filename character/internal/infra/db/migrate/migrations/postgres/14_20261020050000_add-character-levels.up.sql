@@ -0,0 +1,5 @@
+ALTER TABLE CHARACTERS ADD COLUMN LEVEL INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE CHARACTER_VIEWS ADD COLUMN LEVEL INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS IDX_CHARACTER_VIEWS_LEVEL_NICKNAME ON CHARACTER_VIEWS (LEVEL, NICKNAME_KEY, CHARACTER_ID);
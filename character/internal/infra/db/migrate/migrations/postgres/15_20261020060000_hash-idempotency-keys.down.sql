@@ -0,0 +1,2 @@
+-- the hashed keys are kept; they expire like any other
+ALTER TABLE IDEMPOTENCY_KEYS ALTER COLUMN IDEMPOTENCY_KEY TYPE VARCHAR(255);
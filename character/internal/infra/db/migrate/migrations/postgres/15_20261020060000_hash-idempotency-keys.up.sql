@@ -0,0 +1,5 @@
+-- the scoped keys include the request path, which has no length limit, so
+-- only their SHA-256 is stored
+UPDATE IDEMPOTENCY_KEYS SET IDEMPOTENCY_KEY = encode(sha256(convert_to(IDEMPOTENCY_KEY, 'UTF8')), 'hex');
+
+ALTER TABLE IDEMPOTENCY_KEYS ALTER COLUMN IDEMPOTENCY_KEY TYPE CHAR(64);
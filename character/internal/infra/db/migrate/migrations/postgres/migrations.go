@@ -0,0 +1,8 @@
+// Package postgres embeds the PostgreSQL schema used by the postgres
+// repositories.
+package postgres
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang-migrate/migrate/v4"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/vterry/ddd-study/character/internal/infra/db/migrate/migrations"
+)
+
+var (
+	ErrNoMigrations         = errors.New("no migrations found")
+	ErrInvalidMigrationName = errors.New("invalid migration file name")
+	ErrDuplicateMigration   = errors.New("duplicate migration version")
+	ErrMismatchedMigration  = errors.New("migration up and down files do not match")
+	ErrMissingUpMigration   = errors.New("migration has no up file")
+)
+
+// Migration is one schema version, made of an up file and an optional down
+// file sharing the same name.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// Migrations lists the embedded migrations in version order, failing when
+// two files claim the same version or an up and down pair disagree on name.
+func Migrations() ([]Migration, error) {
+	return readMigrations(migrations.FS)
+}
+
+// LatestMigrationVersion returns the highest embedded migration version.
+func LatestMigrationVersion() (uint, error) {
+	list, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	return list[len(list)-1].Version, nil
+}
+
+// NewMigrator opens a dedicated connection and returns a migrator over the
+// embedded migrations. Closing the migrator closes that connection.
+func NewMigrator(cfg mysql.Config) (*migrate.Migrate, error) {
+	if _, err := Migrations(); err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot open embedded migrations: %w", err)
+	}
+
+	// migrations may hold several statements, e.g. a table and its backfill
+	cfg.MultiStatements = true
+	conn, err := NewMySQLStorage(cfg)
+	if err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+
+	driver, err := migratemysql.WithInstance(conn, &migratemysql.Config{})
+	if err != nil {
+		_ = src.Close()
+		_ = conn.Close()
+		return nil, fmt.Errorf("cannot prepare migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "mysql", driver)
+	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
+		return nil, fmt.Errorf("cannot create migrator: %w", err)
+	}
+
+	return m, nil
+}
+
+// MigrateUp applies every pending migration. It is a no-op when the schema
+// is already at the latest version.
+func MigrateUp(cfg mysql.Config) error {
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("cannot apply migrations: %w", err)
+	}
+	return nil
+}
+
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read migrations: %w", err)
+	}
+
+	ups := make(map[uint]*source.Migration)
+	downs := make(map[uint]*source.Migration)
+	var problems []error
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		m, err := source.Parse(entry.Name())
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name()))
+			continue
+		}
+
+		files := ups
+		if m.Direction == source.Down {
+			files = downs
+		}
+		if other, ok := files[m.Version]; ok {
+			problems = append(problems, fmt.Errorf("%w %d: %s and %s", ErrDuplicateMigration, m.Version, other.Raw, m.Raw))
+			continue
+		}
+		files[m.Version] = m
+	}
+
+	for version, down := range downs {
+		up, ok := ups[version]
+		if !ok {
+			problems = append(problems, fmt.Errorf("%w: %s", ErrMissingUpMigration, down.Raw))
+			continue
+		}
+		if up.Identifier != down.Identifier {
+			problems = append(problems, fmt.Errorf("%w at version %d: %s and %s", ErrMismatchedMigration, version, up.Raw, down.Raw))
+		}
+	}
+
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+	if len(ups) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	list := make([]Migration, 0, len(ups))
+	for version, up := range ups {
+		list = append(list, Migration{Version: version, Name: up.Identifier})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+
+	return list, nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestEmbeddedMigrationsAreConsistent(t *testing.T) {
+	list, err := Migrations()
+	require.NoError(t, err)
+
+	for i, migration := range list {
+		assert.Equal(t, uint(i+1), migration.Version, "versions are contiguous")
+	}
+
+	latest, err := LatestMigrationVersion()
+	require.NoError(t, err)
+	assert.Equal(t, list[len(list)-1].Version, latest)
+}
+
+func TestEmbeddedPostgresMigrationsAreConsistent(t *testing.T) {
+	list, err := PostgresMigrations()
+	require.NoError(t, err)
+
+	for i, migration := range list {
+		assert.Equal(t, uint(i+1), migration.Version, "versions are contiguous")
+	}
+
+	latest, err := LatestPostgresMigrationVersion()
+	require.NoError(t, err)
+	assert.Equal(t, list[len(list)-1].Version, latest)
+}
+
+func TestPostgresDSN(t *testing.T) {
+	dsn := PostgresDSN(config.DbConfig{User: "character", Password: "p@ss/word", Address: "db:5432", Name: "character-db", SSLMode: "verify-full"})
+
+	assert.Equal(t, "postgres://character:p%40ss%2Fword@db:5432/character-db?sslmode=verify-full", dsn)
+}
+
+func TestReadMigrations(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsys        fstest.MapFS
+		expected    []Migration
+		expectedErr error
+	}{
+		{
+			name: "up and down pairs in version order",
+			fsys: fstest.MapFS{
+				"02_add-b.up.sql":   file("CREATE TABLE B (ID INT);"),
+				"02_add-b.down.sql": file("DROP TABLE B;"),
+				"01_add-a.up.sql":   file("CREATE TABLE A (ID INT);"),
+				"migrations.go":     file("package migrations"),
+			},
+			expected: []Migration{{Version: 1, Name: "add-a"}, {Version: 2, Name: "add-b"}},
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"01_add-a.up.sql": file(""),
+				"01_add-b.up.sql": file(""),
+			},
+			expectedErr: ErrDuplicateMigration,
+		},
+		{
+			name: "down file belongs to another migration",
+			fsys: fstest.MapFS{
+				"01_add-a.up.sql":   file(""),
+				"01_add-b.down.sql": file(""),
+			},
+			expectedErr: ErrMismatchedMigration,
+		},
+		{
+			name: "down file without up file",
+			fsys: fstest.MapFS{
+				"01_add-a.up.sql":   file(""),
+				"02_add-b.down.sql": file(""),
+			},
+			expectedErr: ErrMissingUpMigration,
+		},
+		{
+			name:        "unparseable name",
+			fsys:        fstest.MapFS{"add-a.sql": file("")},
+			expectedErr: ErrInvalidMigrationName,
+		},
+		{
+			name:        "empty source",
+			fsys:        fstest.MapFS{},
+			expectedErr: ErrNoMigrations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := readMigrations(tt.fsys)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, list)
+		})
+	}
+}
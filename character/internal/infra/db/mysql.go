@@ -1,16 +1,46 @@
 package db
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
 )
 
+const connectTimeout = 5 * time.Second
+
+// MySQLConfig translates the database section of the service configuration
+// into driver settings.
+func MySQLConfig(cfg config.DbConfig) mysql.Config {
+	return mysql.Config{
+		User:                 cfg.User,
+		Passwd:               cfg.Password,
+		Addr:                 cfg.Address,
+		DBName:               cfg.Name,
+		Net:                  "tcp",
+		AllowNativePasswords: true,
+		ParseTime:            true,
+	}
+}
+
+// NewMySQLStorage opens the connection pool and pings the database, so the
+// service fails at startup instead of on the first request.
 func NewMySQLStorage(cfg mysql.Config) (*sql.DB, error) {
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("cannot open mysql connection: %w", err)
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot reach mysql at %s: %w", cfg.Addr, err)
+	}
+
 	return db, nil
 }
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+)
+
+type GrpcServer struct {
+	addr   string
+	server *grpc.Server
+}
+
+func NewGrpcServer(addr string, server *grpc.Server) *GrpcServer {
+	return &GrpcServer{
+		addr:   addr,
+		server: server,
+	}
+}
+
+func (g *GrpcServer) Run() error {
+	listener, err := net.Listen("tcp", g.addr)
+	if err != nil {
+		return err
+	}
+
+	return g.server.Serve(listener)
+}
+
+// Stop drains in-flight calls and falls back to a hard stop once ctx expires.
+func (g *GrpcServer) Stop(ctx context.Context) error {
+	if g.server == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		g.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		g.server.Stop()
+		return ctx.Err()
+	}
+}
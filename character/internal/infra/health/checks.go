@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrDirtyMigration    = errors.New("database schema is dirty")
+	ErrPendingMigrations = errors.New("database schema has pending migrations")
+)
+
+const schemaVersionQuery = "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+func MySQL(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+func Postgres(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Migrations compares the version recorded by golang-migrate with the latest
+// migration shipped with the service.
+func Migrations(db *sql.DB, latest uint) Check {
+	return func(ctx context.Context) error {
+		var (
+			version uint
+			dirty   bool
+		)
+
+		err := db.QueryRowContext(ctx, schemaVersionQuery).Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: no migration applied, latest is %d", ErrPendingMigrations, latest)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read schema version: %w", err)
+		}
+
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirtyMigration, version)
+		}
+
+		if version < latest {
+			return fmt.Errorf("%w: current %d, latest %d", ErrPendingMigrations, version, latest)
+		}
+
+		return nil
+	}
+}
+
+// OIDC fetches the discovery document of the identity provider.
+func OIDC(client *http.Client, issuerURL string) Check {
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+		if err != nil {
+			return fmt.Errorf("cannot create discovery request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("identity provider unreachable: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("identity provider answered with status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
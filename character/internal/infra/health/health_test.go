@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthy(ctx context.Context) error {
+	return nil
+}
+
+func failing(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCheckerRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]Check
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "no checks registered",
+			checks:         map[string]Check{},
+			expectedStatus: StatusUp,
+			expectedChecks: map[string]string{},
+		},
+		{
+			name:           "all dependencies up",
+			checks:         map[string]Check{"mysql": healthy, "oidc": healthy},
+			expectedStatus: StatusUp,
+			expectedChecks: map[string]string{"mysql": StatusUp, "oidc": StatusUp},
+		},
+		{
+			name:           "one dependency down",
+			checks:         map[string]Check{"mysql": failing, "oidc": healthy},
+			expectedStatus: StatusDown,
+			expectedChecks: map[string]string{"mysql": StatusDown, "oidc": StatusUp},
+		},
+		{
+			name:           "check exceeding the timeout",
+			checks:         map[string]Check{"mysql": hanging},
+			expectedStatus: StatusDown,
+			expectedChecks: map[string]string{"mysql": StatusDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+
+			report := checker.Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.expectedChecks))
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, report.Checks[name].Status, name)
+			}
+		})
+	}
+}
+
+func TestCheckerRunReportsError(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("mysql", failing)
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, "connection refused", report.Checks["mysql"].Error)
+}
+
+func TestLivenessHandler(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("mysql", failing)
+
+	rec := httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		check        Check
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "ready",
+			check:        healthy,
+			expectedCode: http.StatusOK,
+			expectedBody: StatusUp,
+		},
+		{
+			name:         "not ready",
+			check:        failing,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Second)
+			checker.Register("mysql", tt.check)
+
+			rec := httptest.NewRecorder()
+			checker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var report Report
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+			assert.Equal(t, tt.expectedBody, report.Status)
+			assert.Equal(t, tt.expectedBody, report.Checks["mysql"].Status)
+		})
+	}
+}
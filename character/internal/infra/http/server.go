@@ -2,68 +2,34 @@ package http
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
-
-	"github.com/vterry/ddd-study/character/internal/adapters/input/rest"
-	"github.com/vterry/ddd-study/character/internal/adapters/input/token"
-	"github.com/vterry/ddd-study/character/internal/adapters/output/gateway"
-	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/mysql"
-	"github.com/vterry/ddd-study/character/internal/core/service"
-	"github.com/vterry/ddd-study/character/internal/infra/config"
-	"github.com/vterry/ddd-study/character/internal/infra/keycloak"
-	"github.com/vterry/ddd-study/character/internal/infra/logger"
 )
 
 type HttpServer struct {
-	addr   string
-	db     *sql.DB
-	server *http.Server
-	ctx    context.Context
+	addr    string
+	handler http.Handler
+	server  *http.Server
 }
 
-func NewHttpServer(ctx context.Context, addr string, db *sql.DB) *HttpServer {
+func NewHttpServer(addr string, handler http.Handler) *HttpServer {
 	return &HttpServer{
-		addr: addr,
-		db:   db,
-		ctx:  ctx,
+		addr:    addr,
+		handler: handler,
 	}
 }
 
 func (h *HttpServer) Run() error {
-
-	characterRepo := mysql.NewCharacterRepository(h.db)
-
-	vaultGateway := gateway.NewMockVaultGateway()
-
-	keycloakClient, err := keycloak.NewKeycloakClient(h.ctx, &config.Envs.Auth)
-	if err != nil {
-		return err
-	}
-	loginGateway := gateway.NewLoginGateway(keycloakClient)
-	loginGateway.Client = &http.Client{}
-
-	tokenAdapter := token.NewTokenValidator(keycloakClient)
-
-	zapLogger := logger.NewZapLogger()
-	characterCoreService := service.NewCharacterService(characterRepo, vaultGateway, zapLogger)
-
-	characterService := rest.NewCharacterService(characterCoreService, loginGateway)
-
-	handler := rest.NewHandler(*characterService, *tokenAdapter)
-
-	v1 := http.NewServeMux()
-	v1.Handle("/character/v1/", http.StripPrefix("/character/v1", v1))
-	handler.RegisterRoutes(v1)
-
 	h.server = &http.Server{
 		Addr:    h.addr,
-		Handler: v1,
+		Handler: h.handler,
 	}
 
 	return h.server.ListenAndServe()
 }
 
 func (h *HttpServer) Stop(ctx context.Context) error {
+	if h.server == nil {
+		return nil
+	}
 	return h.server.Shutdown(ctx)
 }
@@ -23,9 +23,7 @@ func NewKeycloakClient(ctx context.Context, config *config.KeycloakConfig, optio
 		opt(config)
 	}
 
-	providerURL := fmt.Sprintf("%s/realms/%s", config.BaseURL, config.Realm)
-
-	provider, err := oidc.NewProvider(ctx, providerURL)
+	provider, err := oidc.NewProvider(ctx, IssuerURL(config))
 	if err != nil {
 		return nil, fmt.Errorf("cannot get a oidc provider: %w", err)
 	}
@@ -47,6 +45,11 @@ func NewKeycloakClient(ctx context.Context, config *config.KeycloakConfig, optio
 	}, nil
 }
 
+// IssuerURL is the OIDC issuer of the configured realm.
+func IssuerURL(config *config.KeycloakConfig) string {
+	return fmt.Sprintf("%s/realms/%s", config.BaseURL, config.Realm)
+}
+
 func (k *KeycloakClient) BaseURL() string {
 	return k.config.BaseURL
 }
@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/logger"
 	"go.uber.org/zap"
 )
 
@@ -17,3 +20,15 @@ func (z *ZapLogger) Info(msg string, args ...interface{})  { z.logger.Infow(msg,
 func (z *ZapLogger) Warn(msg string, args ...interface{})  { z.logger.Warnw(msg, args...) }
 func (z *ZapLogger) Error(msg string, args ...interface{}) { z.logger.Errorw(msg, args...) }
 func (z *ZapLogger) Debug(msg string, args ...interface{}) { z.logger.Debugw(msg, args...) }
+
+func (z *ZapLogger) With(args ...interface{}) logger.Logger {
+	return &ZapLogger{logger: z.logger.With(args...)}
+}
+
+func (z *ZapLogger) WithContext(ctx context.Context) logger.Logger {
+	fields := logger.FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return z
+	}
+	return z.With(fields...)
+}
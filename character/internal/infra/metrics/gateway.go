@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+)
+
+const (
+	TargetLogin    = "login"
+	TargetVault    = "vault"
+	TargetKeycloak = "keycloak"
+)
+
+type loginGateway struct {
+	next    gateway.Login
+	metrics *Metrics
+}
+
+func InstrumentLoginGateway(next gateway.Login, metrics *Metrics) gateway.Login {
+	return &loginGateway{next: next, metrics: metrics}
+}
+
+func (g *loginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID) (bool, error) {
+	start := time.Now()
+	valid, err := g.next.IsLoginValid(ctx, loginId)
+	g.metrics.observeOutbound(TargetLogin, "IsLoginValid", start, err)
+	return valid, err
+}
+
+type vaultGateway struct {
+	next    gateway.Vault
+	metrics *Metrics
+}
+
+func InstrumentVaultGateway(next gateway.Vault, metrics *Metrics) gateway.Vault {
+	return &vaultGateway{next: next, metrics: metrics}
+}
+
+func (g *vaultGateway) CreateVault(ctx context.Context) (vault.VaultID, error) {
+	start := time.Now()
+	vaultID, err := g.next.CreateVault(ctx)
+	g.metrics.observeOutbound(TargetVault, "CreateVault", start, err)
+	return vaultID, err
+}
+
+// Operation names a single outbound HTTP request for the instrumented
+// transport. Requests it returns an empty string for are not recorded.
+type Operation func(r *http.Request) string
+
+type transport struct {
+	next      http.RoundTripper
+	target    string
+	operation Operation
+	metrics   *Metrics
+}
+
+// InstrumentTransport records the requests sent through next that operation
+// recognises. Responses with a 5xx status count as errors.
+func InstrumentTransport(next http.RoundTripper, target string, operation Operation, metrics *Metrics) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{
+		next:      next,
+		target:    target,
+		operation: operation,
+		metrics:   metrics,
+	}
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	operation := t.operation(r)
+	if operation == "" {
+		return t.next.RoundTrip(r)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+
+	result := outcome(err)
+	if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+		result = OutcomeError
+	}
+	t.metrics.OutboundRequests.WithLabelValues(t.target, operation, result).Inc()
+	t.metrics.OutboundDuration.WithLabelValues(t.target, operation).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+func (m *Metrics) observeOutbound(target, operation string, start time.Time, err error) {
+	m.OutboundRequests.WithLabelValues(target, operation, outcome(err)).Inc()
+	m.OutboundDuration.WithLabelValues(target, operation).Observe(time.Since(start).Seconds())
+}
+
+// KeycloakToken recognises requests to the token endpoint of a Keycloak realm.
+func KeycloakToken(r *http.Request) string {
+	if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+		return "token"
+	}
+	return ""
+}
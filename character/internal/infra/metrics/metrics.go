@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "character"
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Metrics holds every collector exposed by the service. Each instance owns its
+// registry, so tests can build one without clashing with the others.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+
+	OutboundRequests *prometheus.CounterVec
+	OutboundDuration *prometheus.HistogramVec
+
+	QueryDuration *prometheus.HistogramVec
+
+	CharactersCreated prometheus.Counter
+	GoldMoved         *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the HTTP requests handled, by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		OutboundRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbound_requests_total",
+			Help:      "Calls made to external dependencies, by target, operation and outcome.",
+		}, []string{"target", "operation", "outcome"}),
+		OutboundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "outbound_request_duration_seconds",
+			Help:      "Latency of the calls made to external dependencies, by target and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"target", "operation"}),
+		QueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Latency of the repository operations, by repository, operation and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"repository", "operation", "outcome"}),
+		CharactersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "characters_created_total",
+			Help:      "Characters successfully created.",
+		}),
+		GoldMoved: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gold_moved_total",
+			Help:      "Amount of gold moved, by operation.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.OutboundRequests,
+		m.OutboundDuration,
+		m.QueryDuration,
+		m.CharactersCreated,
+		m.GoldMoved,
+	)
+
+	return m
+}
+
+// ObserveProjectionLag exposes the lag reported by lag, read on every scrape.
+// The gauge is NaN while the lag cannot be read.
+func (m *Metrics) ObserveProjectionLag(lag func(ctx context.Context) (int64, error)) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "projection_lag_events",
+		Help:      "Character events recorded but not yet applied to the read model.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		events, err := lag(ctx)
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(events)
+	}))
+}
+
+// ObserveLedgerMismatches exposes the character accounts found apart from
+// their characters by the last reconciliation, read on every scrape.
+func (m *Metrics) ObserveLedgerMismatches(mismatches func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_mismatched_accounts",
+		Help:      "Character accounts whose ledger balance differs from the character gold at the last reconciliation.",
+	}, func() float64 {
+		return float64(mismatches())
+	}))
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
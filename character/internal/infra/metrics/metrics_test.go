@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+)
+
+type stubCharacterService struct {
+	service.CharacterService
+	err error
+}
+
+func (s *stubCharacterService) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error {
+	return s.err
+}
+
+func (s *stubCharacterService) DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) error {
+	return s.err
+}
+
+func (s *stubCharacterService) PickGold(ctx context.Context, characterId character.CharacterID, amount int) error {
+	return s.err
+}
+
+type stubCharacterRepository struct {
+	err error
+}
+
+func (r *stubCharacterRepository) FindCharacterById(ctx context.Context, characterId character.CharacterID) (*character.Character, error) {
+	return nil, r.err
+}
+
+func (r *stubCharacterRepository) Save(ctx context.Context, character character.Character) error {
+	return r.err
+}
+
+func (r *stubCharacterRepository) Update(ctx context.Context, character character.Character) error {
+	return r.err
+}
+
+func TestInstrumentCharacterService(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedCreated float64
+		expectedDeposit float64
+		expectedPicked  float64
+	}{
+		{
+			name:            "counts successful operations",
+			expectedCreated: 1,
+			expectedDeposit: 30,
+			expectedPicked:  12,
+		},
+		{
+			name: "ignores failed operations",
+			err:  errors.New("boom"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			svc := InstrumentCharacterService(&stubCharacterService{err: tt.err}, m)
+			ctx := context.Background()
+			characterID := character.NewCharacterID(uuid.New())
+
+			_ = svc.CreateCharacter(ctx, login.NewLoginID(uuid.New()), "Nickname", class.Warrior)
+			_ = svc.DepositGold(ctx, characterID, 30, vault.NewVaultID(uuid.New()))
+			_ = svc.PickGold(ctx, characterID, 12)
+
+			assert.Equal(t, tt.expectedCreated, testutil.ToFloat64(m.CharactersCreated))
+			assert.Equal(t, tt.expectedDeposit, testutil.ToFloat64(m.GoldMoved.WithLabelValues(GoldDeposited)))
+			assert.Equal(t, tt.expectedPicked, testutil.ToFloat64(m.GoldMoved.WithLabelValues(GoldPicked)))
+		})
+	}
+}
+
+func TestInstrumentCharacterRepository(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+
+	ok := InstrumentCharacterRepository(&stubCharacterRepository{}, "mysql", m)
+	failing := InstrumentCharacterRepository(&stubCharacterRepository{err: errors.New("boom")}, "mysql", m)
+
+	_, _ = ok.FindCharacterById(ctx, character.NewCharacterID(uuid.New()))
+	_ = failing.Save(ctx, character.Character{})
+
+	count, err := testutil.GatherAndCount(m.registry, "character_repository_query_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	m := New()
+	client := &http.Client{Transport: InstrumentTransport(nil, TargetKeycloak, KeycloakToken, m)}
+
+	for _, path := range []string{"/realms/test/protocol/openid-connect/token", "/admin/realms/test/users/1"} {
+		resp, err := client.Get(upstream.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.OutboundRequests.WithLabelValues(TargetKeycloak, "token", OutcomeError)))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.OutboundRequests))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.CharactersCreated.Inc()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "character_characters_created_total 1")
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+)
+
+type characterRepository struct {
+	next    repository.CharacterRepository
+	name    string
+	metrics *Metrics
+}
+
+// InstrumentCharacterRepository times every call made to next. name labels
+// the storage backend, e.g. "mysql".
+func InstrumentCharacterRepository(next repository.CharacterRepository, name string, metrics *Metrics) repository.CharacterRepository {
+	return &characterRepository{
+		next:    next,
+		name:    name,
+		metrics: metrics,
+	}
+}
+
+func (r *characterRepository) FindCharacterById(ctx context.Context, characterId character.CharacterID) (*character.Character, error) {
+	start := time.Now()
+	found, err := r.next.FindCharacterById(ctx, characterId)
+	r.observe("FindCharacterById", start, err)
+	return found, err
+}
+
+func (r *characterRepository) Save(ctx context.Context, character character.Character) error {
+	start := time.Now()
+	err := r.next.Save(ctx, character)
+	r.observe("Save", start, err)
+	return err
+}
+
+func (r *characterRepository) Update(ctx context.Context, character character.Character) error {
+	start := time.Now()
+	err := r.next.Update(ctx, character)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *characterRepository) observe(operation string, start time.Time, err error) {
+	r.metrics.QueryDuration.WithLabelValues(r.name, operation, outcome(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+)
+
+const (
+	GoldDeposited = "deposit"
+	GoldPicked    = "pick"
+)
+
+var _ service.CharacterService = (*characterService)(nil)
+
+type characterService struct {
+	service.CharacterService
+	metrics *Metrics
+}
+
+// InstrumentCharacterService counts the domain events of the operations that
+// succeed on next. Calls are otherwise passed through untouched.
+func InstrumentCharacterService(next service.CharacterService, metrics *Metrics) service.CharacterService {
+	return &characterService{CharacterService: next, metrics: metrics}
+}
+
+func (s *characterService) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error {
+	if err := s.CharacterService.CreateCharacter(ctx, loginId, nickname, class); err != nil {
+		return err
+	}
+	s.metrics.CharactersCreated.Inc()
+	return nil
+}
+
+func (s *characterService) DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) error {
+	if err := s.CharacterService.DepositGold(ctx, characterId, quantity, vaultId); err != nil {
+		return err
+	}
+	s.metrics.GoldMoved.WithLabelValues(GoldDeposited).Add(float64(quantity))
+	return nil
+}
+
+func (s *characterService) PickGold(ctx context.Context, characterId character.CharacterID, amount int) error {
+	if err := s.CharacterService.PickGold(ctx, characterId, amount); err != nil {
+		return err
+	}
+	s.metrics.GoldMoved.WithLabelValues(GoldPicked).Add(float64(amount))
+	return nil
+}
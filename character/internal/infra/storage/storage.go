@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/dao"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/memory"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/mysql"
+	"github.com/vterry/ddd-study/character/internal/adapters/output/repository/postgres"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/clock"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+	"github.com/vterry/ddd-study/character/internal/infra/db"
+	"github.com/vterry/ddd-study/character/internal/infra/health"
+)
+
+// Storage holds the repositories of the backend selected by config.Storage.
+// It is opened once and shared by the HTTP and gRPC servers, so both see the
+// same data even when it only lives in memory.
+type Storage struct {
+	// System names the backend in metrics and traces.
+	System      string
+	Characters  repository.CharacterRepository
+	Events      repository.CharacterEventLog
+	History     repository.InventoryHistory
+	Suspensions repository.CharacterSuspensions
+	Gold        repository.CharacterGold
+	Classes     repository.ClassRepository
+	Views       repository.CharacterViewRepository
+	Ledger      repository.LedgerRepository
+	Wallets     repository.WalletRepository
+	Idempotency repository.IdempotencyRepository
+	RateLimits  repository.RateLimitRepository
+	Processed   repository.ProcessedMessageRepository
+
+	register func(startup, readiness *health.Checker)
+	close    func() error
+}
+
+// Open connects to the configured backend. For MySQL and PostgreSQL it
+// applies the pending migrations first when cfg.Db.MigrateOnStartup is set.
+func Open(cfg config.Config, clock clock.Clock) (*Storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		characters := memory.NewCharacterRepository(clock, inventoryPersistence(cfg.Inventory))
+		return &Storage{
+			System:      config.StorageMemory,
+			Characters:  characters,
+			Events:      characters,
+			History:     characters,
+			Suspensions: characters,
+			Gold:        characters,
+			Classes:     memory.NewClassRepository(class.Defaults().List()...),
+			Views:       memory.NewCharacterViewRepository(),
+			Ledger:      memory.NewLedgerRepository(),
+			Wallets:     memory.NewWalletRepository(),
+			Idempotency: memory.NewIdempotencyRepository(clock),
+			RateLimits:  memory.NewRateLimitRepository(),
+			Processed:   characters,
+			register:    func(startup, readiness *health.Checker) {},
+			close:       func() error { return nil },
+		}, nil
+
+	case config.StorageMySQL:
+		return openMySQL(cfg.Db, inventoryPersistence(cfg.Inventory), cfg.RateLimit.Store, clock)
+
+	case config.StoragePostgres:
+		return openPostgres(cfg.Db, clock)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown storage %q", config.ErrInvalidConfig, cfg.Storage)
+	}
+}
+
+func openMySQL(cfg config.DbConfig, inventories dao.InventoryPersistence, rateLimitStore string, clock clock.Clock) (*Storage, error) {
+	mysqlCfg := db.MySQLConfig(cfg)
+
+	if cfg.MigrateOnStartup {
+		if err := db.MigrateUp(mysqlCfg); err != nil {
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	latestMigration, err := db.LatestMigrationVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	conn, err := db.NewMySQLStorage(mysqlCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
+	}
+
+	characters := mysql.NewCharacterRepository(conn, inventories)
+
+	var rateLimits repository.RateLimitRepository = memory.NewRateLimitRepository()
+	if rateLimitStore == config.RateLimitMySQL {
+		rateLimits = mysql.NewRateLimitRepository(conn)
+	}
+	return &Storage{
+		System:      config.StorageMySQL,
+		Characters:  characters,
+		Events:      characters,
+		History:     characters,
+		Suspensions: characters,
+		Gold:        characters,
+		Classes:     mysql.NewClassRepository(conn),
+		Views:       mysql.NewCharacterViewRepository(conn),
+		Ledger:      mysql.NewLedgerRepository(conn),
+		Wallets:     mysql.NewWalletRepository(conn),
+		Idempotency: mysql.NewIdempotencyRepository(conn, clock),
+		RateLimits:  rateLimits,
+		Processed:   characters,
+		register: func(startup, readiness *health.Checker) {
+			startup.Register("mysql", health.MySQL(conn))
+			readiness.Register("mysql", health.MySQL(conn))
+			readiness.Register("migrations", health.Migrations(conn, latestMigration))
+		},
+		close: conn.Close,
+	}, nil
+}
+
+// openPostgres keeps the rate limit buckets in memory, and the inventories
+// as state: config.Validate rejects the other choices.
+func openPostgres(cfg config.DbConfig, clock clock.Clock) (*Storage, error) {
+	dsn := db.PostgresDSN(cfg)
+
+	if cfg.MigrateOnStartup {
+		if err := db.MigratePostgresUp(dsn); err != nil {
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	latestMigration, err := db.LatestPostgresMigrationVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	conn, err := db.NewPostgresStorage(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	characters := postgres.NewCharacterRepository(conn)
+	return &Storage{
+		System:      config.StoragePostgres,
+		Characters:  characters,
+		Events:      characters,
+		History:     characters,
+		Suspensions: characters,
+		Gold:        characters,
+		Classes:     postgres.NewClassRepository(conn),
+		Views:       postgres.NewCharacterViewRepository(conn),
+		Ledger:      postgres.NewLedgerRepository(conn),
+		Wallets:     postgres.NewWalletRepository(conn),
+		Idempotency: postgres.NewIdempotencyRepository(conn, clock),
+		RateLimits:  memory.NewRateLimitRepository(),
+		Processed:   characters,
+		register: func(startup, readiness *health.Checker) {
+			startup.Register("postgres", health.Postgres(conn))
+			readiness.Register("postgres", health.Postgres(conn))
+			readiness.Register("migrations", health.Migrations(conn, latestMigration))
+		},
+		close: conn.Close,
+	}, nil
+}
+
+func inventoryPersistence(cfg config.InventoryConfig) dao.InventoryPersistence {
+	return dao.InventoryPersistence{
+		EventSourced:  cfg.Persistence == config.InventoryEvents,
+		SnapshotEvery: cfg.SnapshotEvery,
+	}
+}
+
+// RegisterChecks adds the backend checks: connectivity to the startup
+// checker, connectivity and schema version to the readiness one.
+func (s *Storage) RegisterChecks(startup, readiness *health.Checker) {
+	s.register(startup, readiness)
+}
+
+func (s *Storage) Close() error {
+	return s.close()
+}
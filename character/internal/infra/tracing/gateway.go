@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/gateway"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type loginGateway struct {
+	next   gateway.Login
+	tracer trace.Tracer
+}
+
+func TraceLoginGateway(next gateway.Login, provider trace.TracerProvider) gateway.Login {
+	return &loginGateway{next: next, tracer: provider.Tracer(InstrumentationName)}
+}
+
+func (g *loginGateway) IsLoginValid(ctx context.Context, loginId login.LoginID) (valid bool, err error) {
+	ctx, span := g.tracer.Start(ctx, "LoginGateway.IsLoginValid",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(AttrLoginID.String(loginId.ID().String())),
+	)
+	defer func() { end(span, err) }()
+	return g.next.IsLoginValid(ctx, loginId)
+}
+
+type vaultGateway struct {
+	next   gateway.Vault
+	tracer trace.Tracer
+}
+
+func TraceVaultGateway(next gateway.Vault, provider trace.TracerProvider) gateway.Vault {
+	return &vaultGateway{next: next, tracer: provider.Tracer(InstrumentationName)}
+}
+
+func (g *vaultGateway) CreateVault(ctx context.Context) (vaultID vault.VaultID, err error) {
+	ctx, span := g.tracer.Start(ctx, "VaultGateway.CreateVault", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { end(span, err) }()
+	return g.next.CreateVault(ctx)
+}
+
+// TraceTransport opens a client span for every request sent through next and
+// injects its W3C traceparent header, so the callee joins the trace.
+func TraceTransport(next http.RoundTripper, provider trace.TracerProvider, propagator propagation.TextMapPropagator) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(next,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithPropagators(propagator),
+	)
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type characterRepository struct {
+	next   repository.CharacterRepository
+	system attribute.KeyValue
+	tracer trace.Tracer
+}
+
+// TraceCharacterRepository opens a client span around every call made to
+// next. system names the database, e.g. "mysql".
+func TraceCharacterRepository(next repository.CharacterRepository, system string, provider trace.TracerProvider) repository.CharacterRepository {
+	return &characterRepository{
+		next:   next,
+		system: semconv.DBSystemKey.String(system),
+		tracer: provider.Tracer(InstrumentationName),
+	}
+}
+
+func (r *characterRepository) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "CharacterRepository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(r.system, semconv.DBOperationName(operation)),
+	)
+}
+
+func (r *characterRepository) FindCharacterById(ctx context.Context, characterId character.CharacterID) (found *character.Character, err error) {
+	ctx, span := r.start(ctx, "FindCharacterById")
+	defer func() { end(span, err) }()
+	return r.next.FindCharacterById(ctx, characterId)
+}
+
+func (r *characterRepository) Save(ctx context.Context, character character.Character) (err error) {
+	ctx, span := r.start(ctx, "Save")
+	defer func() { end(span, err) }()
+	return r.next.Save(ctx, character)
+}
+
+func (r *characterRepository) Update(ctx context.Context, character character.Character) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer func() { end(span, err) }()
+	return r.next.Update(ctx, character)
+}
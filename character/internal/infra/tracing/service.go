@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/item"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/domain/playeritem"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	AttrCharacterID = attribute.Key("character.id")
+	AttrLoginID     = attribute.Key("login.id")
+)
+
+type characterService struct {
+	next   service.CharacterService
+	tracer trace.Tracer
+}
+
+// TraceCharacterService opens a span around every call made to next. The
+// span is carried by the context handed to next, so the repository and
+// gateway spans become its children.
+func TraceCharacterService(next service.CharacterService, provider trace.TracerProvider) service.CharacterService {
+	return &characterService{
+		next:   next,
+		tracer: provider.Tracer(InstrumentationName),
+	}
+}
+
+func (s *characterService) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "CharacterService."+operation, trace.WithAttributes(attrs...))
+}
+
+func (s *characterService) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) (err error) {
+	ctx, span := s.start(ctx, "CreateCharacter", AttrLoginID.String(loginId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.CreateCharacter(ctx, loginId, nickname, class)
+}
+
+func (s *characterService) TransferItemTo(ctx context.Context, characterId character.CharacterID, playeritem playeritem.PlayerItem, quantity int, vaultId vault.VaultID) (err error) {
+	ctx, span := s.start(ctx, "TransferItemTo", AttrCharacterID.String(characterId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.TransferItemTo(ctx, characterId, playeritem, quantity, vaultId)
+}
+
+func (s *characterService) TradeItem(ctx context.Context, origin character.CharacterID, playeritem playeritem.PlayerItem, quantity int, destiny character.CharacterID) (err error) {
+	ctx, span := s.start(ctx, "TradeItem", AttrCharacterID.String(origin.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.TradeItem(ctx, origin, playeritem, quantity, destiny)
+}
+
+func (s *characterService) DepositGold(ctx context.Context, characterId character.CharacterID, quantity int, vaultId vault.VaultID) (err error) {
+	ctx, span := s.start(ctx, "DepositGold", AttrCharacterID.String(characterId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.DepositGold(ctx, characterId, quantity, vaultId)
+}
+
+func (s *characterService) PickItem(ctx context.Context, characterId character.CharacterID, itemId item.ItemID, description string, quantity int) (err error) {
+	ctx, span := s.start(ctx, "PickItem", AttrCharacterID.String(characterId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.PickItem(ctx, characterId, itemId, description, quantity)
+}
+
+func (s *characterService) DropItem(ctx context.Context, characterId character.CharacterID, playerItemID playeritem.PlayerItemID) (err error) {
+	ctx, span := s.start(ctx, "DropItem", AttrCharacterID.String(characterId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.DropItem(ctx, characterId, playerItemID)
+}
+
+func (s *characterService) PickGold(ctx context.Context, characterId character.CharacterID, amount int) (err error) {
+	ctx, span := s.start(ctx, "PickGold", AttrCharacterID.String(characterId.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.PickGold(ctx, characterId, amount)
+}
+
+func (s *characterService) LeaveGuild(ctx context.Context, characterID character.CharacterID) (err error) {
+	ctx, span := s.start(ctx, "LeaveGuild", AttrCharacterID.String(characterID.ID().String()))
+	defer func() { end(span, err) }()
+	return s.next.LeaveGuild(ctx, characterID)
+}
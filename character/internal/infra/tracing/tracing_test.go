@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vterry/ddd-study/character/internal/core/domain/character"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/class"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/login"
+	"github.com/vterry/ddd-study/character/internal/core/domain/common/vault"
+	"github.com/vterry/ddd-study/character/internal/core/ports/input/service"
+	"github.com/vterry/ddd-study/character/internal/core/ports/output/repository"
+	"github.com/vterry/ddd-study/character/internal/infra/config"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// savingService stands in for CharacterServiceImpl: it only saves through
+// the repository it is given, forwarding the context.
+type savingService struct {
+	service.CharacterService
+	repo repository.CharacterRepository
+}
+
+func (s *savingService) CreateCharacter(ctx context.Context, loginId login.LoginID, nickname string, class class.Class) error {
+	return s.repo.Save(ctx, character.Character{})
+}
+
+type stubCharacterRepository struct {
+	repository.CharacterRepository
+	err error
+}
+
+func (r *stubCharacterRepository) Save(ctx context.Context, character character.Character) error {
+	return r.err
+}
+
+type stubVaultGateway struct{}
+
+func (stubVaultGateway) CreateVault(ctx context.Context) (vault.VaultID, error) {
+	return vault.NewVaultID(uuid.New()), nil
+}
+
+func newRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	return recorder, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		exporter    string
+		expectedErr error
+	}{
+		{name: "no exporter", exporter: ExporterNone},
+		{name: "stdout exporter", exporter: ExporterStdout},
+		{name: "otlp exporter", exporter: ExporterOTLP},
+		{name: "unknown exporter", exporter: "zipkin", expectedErr: ErrUnknownExporter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewTracerProvider(context.Background(), config.TracingConfig{
+				Exporter:     tt.exporter,
+				ServiceName:  "character-test",
+				OTLPEndpoint: "localhost:4318",
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, provider.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestSpansArePropagatedThroughContext(t *testing.T) {
+	recorder, provider := newRecorder()
+
+	repo := TraceCharacterRepository(&stubCharacterRepository{err: errors.New("duplicated nickname")}, "mysql", provider)
+	svc := TraceCharacterService(&savingService{repo: repo}, provider)
+
+	err := svc.CreateCharacter(context.Background(), login.NewLoginID(uuid.New()), "Nickname", class.Mage)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	repoSpan, serviceSpan := spans[0], spans[1]
+	assert.Equal(t, "CharacterRepository.Save", repoSpan.Name())
+	assert.Equal(t, "CharacterService.CreateCharacter", serviceSpan.Name())
+	assert.Equal(t, serviceSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, repoSpan.Status().Code)
+	assert.Equal(t, codes.Error, serviceSpan.Status().Code)
+}
+
+func TestTraceVaultGateway(t *testing.T) {
+	recorder, provider := newRecorder()
+
+	_, err := TraceVaultGateway(stubVaultGateway{}, provider).CreateVault(context.Background())
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "VaultGateway.CreateVault", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestTraceTransportInjectsTraceparent(t *testing.T) {
+	recorder, provider := newRecorder()
+
+	var traceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer upstream.Close()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	client := &http.Client{Transport: TraceTransport(nil, provider, propagation.TraceContext{})}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	require.NotEmpty(t, traceparent)
+	assert.Contains(t, traceparent, parent.SpanContext().TraceID().String())
+	assert.Len(t, recorder.Ended(), 2)
+}
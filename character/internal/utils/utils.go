@@ -4,14 +4,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator"
 )
 
 var (
-	Validate = validator.New()
+	Validate = newValidator()
 )
 
+// newValidator reports field errors by their json names, so they match the
+// payload the client sent.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
 func ParseJSON(r *http.Request, payload any) error {
 	if r.Body == nil {
 		return fmt.Errorf("missing request body")
@@ -25,12 +41,6 @@ func WriteJSON(w http.ResponseWriter, status int, v any) error {
 	return json.NewEncoder(w).Encode(v)
 }
 
-func WriteError(w http.ResponseWriter, status int, err error) {
-	if err := WriteJSON(w, status, map[string]string{"error": err.Error()}); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-	}
-}
-
 func RecoverSessionId(r *http.Request) string {
 	if reqSessionId := r.Header.Get("session_id"); reqSessionId != "" {
 		return reqSessionId